@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"hr-api/pkg/bus"
 	"hr-api/pkg/cache"
+	"hr-api/service/queue_service"
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/gin-gonic/gin"
 
@@ -30,6 +34,18 @@ func init() {
 // @license.name MIT
 // @license.url https://hr-api/blob/master/LICENSE
 func main() {
+	registerTasks()
+
+	if len(os.Args) > 1 && os.Args[1] == "worker" {
+		runWorker()
+		return
+	}
+
+	// 进程内队列无法跨进程消费，由 API 进程自行运行 worker
+	if setting.QueueSetting.Driver == bus.DriverMemory {
+		go queue_service.Run(context.Background())
+	}
+
 	gin.SetMode(setting.ServerSetting.RunMode)
 
 	routersInit := routers.InitRouter()
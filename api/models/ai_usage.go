@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// AIUsage 一次简历分析调用大模型的 token 消耗，每次分析一条记录，其中的重试与修复请求都累计在 Calls 中
+type AIUsage struct {
+	ID               int    `json:"id" gorm:"primaryKey"`
+	Day              int    `json:"day"`
+	Uid              int    `json:"uid"`
+	JobId            int    `json:"job_id"`
+	ResumeId         int    `json:"resume_id"`
+	Deployment       string `json:"deployment"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+	Calls            int    `json:"calls"`
+	Retries          int    `json:"retries"`
+	LatencyMs        int    `json:"latency_ms"`
+	Success          int    `json:"success"`
+	CreateTime       int    `json:"create_time"`
+}
+
+// AIUsageSummary 按天、用户、职位或部署汇总的 token 消耗
+type AIUsageSummary struct {
+	GroupKey         string  `json:"key"`
+	Analyses         int     `json:"analyses"`
+	Failed           int     `json:"failed"`
+	Calls            int     `json:"calls"`
+	Retries          int     `json:"retries"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+	Cost             float64 `json:"cost" gorm:"-"`
+}
+
+// AIUsageGroupColumns 允许汇总的维度
+var AIUsageGroupColumns = map[string]string{
+	"day":        "day",
+	"uid":        "uid",
+	"job":        "job_id",
+	"deployment": "deployment",
+}
+
+// AddAIUsage add a single usage record
+func AddAIUsage(data map[string]interface{}) error {
+	now := time.Now()
+	usage := AIUsage{
+		Day:              data["day"].(int),
+		Uid:              data["uid"].(int),
+		JobId:            data["job_id"].(int),
+		ResumeId:         data["resume_id"].(int),
+		Deployment:       data["deployment"].(string),
+		PromptTokens:     data["prompt_tokens"].(int),
+		CompletionTokens: data["completion_tokens"].(int),
+		TotalTokens:      data["total_tokens"].(int),
+		Calls:            data["calls"].(int),
+		Retries:          data["retries"].(int),
+		LatencyMs:        data["latency_ms"].(int),
+		Success:          data["success"].(int),
+		CreateTime:       int(now.Unix()),
+	}
+	if err := db.Create(&usage).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAIUsageSummary aggregate usage between startDay and endDay (inclusive) by groupBy
+func GetAIUsageSummary(groupBy string, startDay, endDay int, maps interface{}) ([]*AIUsageSummary, error) {
+	column, ok := AIUsageGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported group by: %s", groupBy)
+	}
+
+	var datas []*AIUsageSummary
+	err := db.Model(&AIUsage{}).
+		Select(column+" AS group_key, COUNT(*) AS analyses, SUM(success = 0) AS failed, "+
+			"SUM(calls) AS calls, SUM(retries) AS retries, SUM(prompt_tokens) AS prompt_tokens, "+
+			"SUM(completion_tokens) AS completion_tokens, SUM(total_tokens) AS total_tokens, AVG(latency_ms) AS avg_latency_ms").
+		Where(maps).
+		Where("day BETWEEN ? AND ?", startDay, endDay).
+		Group(column).
+		Order(column).
+		Scan(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetAIUsageTokens sum of total tokens since startDay
+func GetAIUsageTokens(startDay int) (int, error) {
+	var total int64
+	err := db.Model(&AIUsage{}).
+		Select("COALESCE(SUM(total_tokens), 0)").
+		Where("day >= ?", startDay).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(total), nil
+}
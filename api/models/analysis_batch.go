@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BatchStatusPending  = "pending"
+	BatchStatusRunning  = "running"
+	BatchStatusFinished = "finished"
+)
+
+type AnalysisBatch struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	JobId      int    `json:"job_id"`
+	Status     string `json:"status"`
+	Total      int    `json:"total"`
+	Pending    int    `json:"pending" gorm:"-"`
+	Running    int    `json:"running" gorm:"-"`
+	Succeeded  int    `json:"succeeded"`
+	Failed     int    `json:"failed"`
+	CreateUid  int    `json:"create_uid"`
+	CreateTime int    `json:"create_time"`
+	FinishTime int    `json:"finish_time"`
+}
+
+// GetAnalysisBatch Get a batch by id
+func GetAnalysisBatch(id int) (*AnalysisBatch, error) {
+	var d AnalysisBatch
+	err := db.Model(&AnalysisBatch{}).Where("id = ? ", id).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// AddAnalysisBatch add a single batch and returns its id
+func AddAnalysisBatch(data map[string]interface{}) (int, error) {
+	now := int(time.Now().Unix())
+	batch := AnalysisBatch{
+		JobId:      data["job_id"].(int),
+		Status:     BatchStatusPending,
+		Total:      data["total"].(int),
+		CreateUid:  data["create_uid"].(int),
+		CreateTime: now,
+	}
+	if err := db.Create(&batch).Error; err != nil {
+		return 0, err
+	}
+
+	return batch.ID, nil
+}
+
+// EditAnalysisBatch modify a single batch
+func EditAnalysisBatch(id int, data interface{}) error {
+	if err := db.Model(&AnalysisBatch{}).Where("id = ? ", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ExistAnalysisBatchByID determines whether a batch exists based on the ID
+func ExistAnalysisBatchByID(id int) (bool, error) {
+	var count int64
+	err := db.Model(&AnalysisBatch{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
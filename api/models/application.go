@@ -0,0 +1,151 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 应聘状态
+const (
+	ApplicationActive    = "active"
+	ApplicationWithdrawn = "withdrawn"
+	ApplicationArchived  = "archived"
+)
+
+// ApplicationStatuses 允许的应聘状态
+var ApplicationStatuses = []string{ApplicationActive, ApplicationWithdrawn, ApplicationArchived}
+
+// Application 候选人对某个招聘职位的一次应聘，ResumeId 为投递该职位时使用的简历
+type Application struct {
+	ID            int    `json:"id" gorm:"primaryKey"`
+	CandidateId   int    `json:"candidate_id"`
+	JobId         int    `json:"job_id"`
+	ResumeId      int    `json:"resume_id"`
+	Status        string `json:"status"`
+	Stage         string `json:"stage"`
+	CreateUid     int    `json:"create_uid"`
+	CreateTime    int    `json:"create_time"`
+	UpdateTime    int    `json:"update_time"`
+	JobName       string `json:"job_name" gorm:"->"`
+	CandidateName string `json:"candidate_name" gorm:"->"`
+}
+
+func applicationQuery(maps interface{}) *gorm.DB {
+	return db.Model(&Application{}).
+		Select("applications.*, jobs.name AS job_name, candidates.name AS candidate_name").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Joins("LEFT JOIN candidates ON candidates.id = applications.candidate_id").
+		Where(maps)
+}
+
+// GetApplications get application list data, newest first
+func GetApplications(page int, limit int, maps interface{}) ([]*Application, error) {
+	var (
+		datas []*Application
+		err   error
+	)
+
+	query := applicationQuery(maps).Order("applications.id DESC")
+
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err = query.Find(&datas).Error
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetApplicationTotal counts the total number of applications based on the constraint
+func GetApplicationTotal(maps interface{}) (int, error) {
+	var count int64
+
+	if err := db.Model(&Application{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetApplication Get an application by id
+func GetApplication(id int) (*Application, error) {
+	var d Application
+	err := applicationQuery(map[string]interface{}{"applications.id": id}).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// GetApplicationByCandidateJob get the application of a candidate to a job
+func GetApplicationByCandidateJob(candidateId, jobId int) (*Application, error) {
+	var d Application
+	err := db.Where("candidate_id = ? AND job_id = ?", candidateId, jobId).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// AddApplication add a single application and returns its id
+func AddApplication(data map[string]interface{}) (int, error) {
+	now := int(time.Now().Unix())
+	application := Application{
+		CandidateId: data["candidate_id"].(int),
+		JobId:       data["job_id"].(int),
+		ResumeId:    data["resume_id"].(int),
+		Status:      data["status"].(string),
+		Stage:       data["stage"].(string),
+		CreateUid:   data["create_uid"].(int),
+		CreateTime:  now,
+		UpdateTime:  now,
+	}
+	if err := db.Create(&application).Error; err != nil {
+		return 0, err
+	}
+
+	return application.ID, nil
+}
+
+// EditApplication modify a single application
+func EditApplication(id int, data interface{}) error {
+	if err := db.Model(&Application{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteApplication delete a single application with its stage history, interviews and offers
+func DeleteApplication(id int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteInterviewsOfApplications(tx, []int{id}); err != nil {
+			return err
+		}
+		if err := deleteOffersOfApplications(tx, []int{id}); err != nil {
+			return err
+		}
+		if err := tx.Where("application_id = ?", id).Delete(ApplicationStageLog{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(Application{}).Error
+	})
+}
+
+// ExistApplicationByID determines whether an application exists based on the ID
+func ExistApplicationByID(id int) (bool, error) {
+	var count int64
+	err := db.Model(&Application{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
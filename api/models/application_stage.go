@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ApplicationStageLog 应聘记录的一次阶段变更
+type ApplicationStageLog struct {
+	ID            int    `json:"id" gorm:"primaryKey"`
+	ApplicationId int    `json:"application_id"`
+	FromStage     string `json:"from_stage"`
+	ToStage       string `json:"to_stage"`
+	Reason        string `json:"reason"`
+	CreateUid     int    `json:"create_uid"`
+	CreateUser    string `json:"create_user" gorm:"->"`
+	CreateTime    int    `json:"create_time"`
+}
+
+// MoveApplicationStage 将应聘记录从 from 阶段变更到 to 阶段并记录变更历史。
+// 只有当前阶段仍为 from 时才会更新，返回是否更新成功，用于识别并发修改
+func MoveApplicationStage(id int, from, to, reason string, uid int) (bool, error) {
+	moved := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := int(time.Now().Unix())
+		result := tx.Model(&Application{}).
+			Where("id = ? AND stage = ?", id, from).
+			Updates(map[string]interface{}{"stage": to, "update_time": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		log := ApplicationStageLog{
+			ApplicationId: id,
+			FromStage:     from,
+			ToStage:       to,
+			Reason:        reason,
+			CreateUid:     uid,
+			CreateTime:    now,
+		}
+		if err := tx.Create(&log).Error; err != nil {
+			return err
+		}
+		moved = true
+		return nil
+	})
+	return moved, err
+}
+
+// GetApplicationStageLogs get the stage history of an application, oldest first
+func GetApplicationStageLogs(applicationId int) ([]*ApplicationStageLog, error) {
+	var logs []*ApplicationStageLog
+	err := db.Model(&ApplicationStageLog{}).
+		Select("application_stage_logs.*, users.username AS create_user").
+		Joins("LEFT JOIN users ON users.id = application_stage_logs.create_uid").
+		Where("application_stage_logs.application_id = ?", applicationId).
+		Order("application_stage_logs.id ASC").
+		Find(&logs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// GetApplicationStageCounts counts applications of a job in each stage
+func GetApplicationStageCounts(jobId int) (map[string]int, error) {
+	var rows []struct {
+		Stage string
+		Total int
+	}
+	err := db.Model(&Application{}).
+		Select("stage, COUNT(*) AS total").
+		Where("job_id = ?", jobId).
+		Group("stage").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Stage] = row.Total
+	}
+	return counts, nil
+}
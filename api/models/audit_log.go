@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog 合规审计记录，Detail 中不保存个人信息原文
+type AuditLog struct {
+	ID         int             `json:"id" gorm:"primaryKey"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   int             `json:"target_id"`
+	Uid        int             `json:"uid"`
+	Detail     json.RawMessage `json:"detail" gorm:"type:text"`
+	CreateTime int             `json:"create_time"`
+}
+
+// AddAuditLog add a single audit entry
+func AddAuditLog(data map[string]interface{}) error {
+	entry := AuditLog{
+		Action:     data["action"].(string),
+		TargetType: data["target_type"].(string),
+		TargetId:   data["target_id"].(int),
+		Uid:        data["uid"].(int),
+		Detail:     data["detail"].([]byte),
+		CreateTime: int(time.Now().Unix()),
+	}
+	if err := db.Create(&entry).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
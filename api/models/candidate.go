@@ -0,0 +1,164 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Candidate 候选人，同一个人的多份简历通过手机号或邮箱归并到同一候选人
+type Candidate struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	Name       string `json:"name"`
+	Phone      string `json:"phone"`
+	PhoneKey   string `json:"-"`
+	Email      string `json:"email"`
+	Location   string `json:"location"`
+	Remark     string `json:"remark"`
+	CreateUid  int    `json:"create_uid"`
+	CreateTime int    `json:"create_time"`
+	UpdateTime int    `json:"update_time"`
+}
+
+// candidateKeyword 按姓名、手机号（原样或规范化后）或邮箱模糊查询
+func candidateKeyword(query *gorm.DB, keyword string) *gorm.DB {
+	if keyword == "" {
+		return query
+	}
+	like := "%" + keyword + "%"
+	return query.Where("name LIKE ? OR phone LIKE ? OR phone_key LIKE ? OR email LIKE ?", like, like, like, like)
+}
+
+// GetCandidates get candidate list data, newest first
+func GetCandidates(page int, limit int, keyword string, maps interface{}) ([]*Candidate, error) {
+	var (
+		datas []*Candidate
+		err   error
+	)
+
+	query := candidateKeyword(db.Model(&Candidate{}).Where(maps), keyword).Order("id DESC")
+
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err = query.Find(&datas).Error
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetCandidateTotal counts the total number of candidates based on the constraint
+func GetCandidateTotal(keyword string, maps interface{}) (int, error) {
+	var count int64
+
+	query := candidateKeyword(db.Model(&Candidate{}).Where(maps), keyword)
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetCandidate Get a candidate by id
+func GetCandidate(id int) (*Candidate, error) {
+	var d Candidate
+	err := db.Where("id = ?", id).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// GetCandidateByIdentity get the earliest candidate matching the normalized phone key or email
+func GetCandidateByIdentity(phoneKey, email string) (*Candidate, error) {
+	var d Candidate
+	if phoneKey == "" && email == "" {
+		return &d, nil
+	}
+
+	query := db.Model(&Candidate{})
+	switch {
+	case phoneKey != "" && email != "":
+		query = query.Where("phone_key = ? OR email = ?", phoneKey, email)
+	case phoneKey != "":
+		query = query.Where("phone_key = ?", phoneKey)
+	default:
+		query = query.Where("email = ?", email)
+	}
+
+	err := query.Order("id").First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// AddCandidate add a single candidate and returns its id
+func AddCandidate(data map[string]interface{}) (int, error) {
+	now := int(time.Now().Unix())
+	candidate := Candidate{
+		Name:       data["name"].(string),
+		Phone:      data["phone"].(string),
+		PhoneKey:   data["phone_key"].(string),
+		Email:      data["email"].(string),
+		Location:   data["location"].(string),
+		Remark:     data["remark"].(string),
+		CreateUid:  data["create_uid"].(int),
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := db.Create(&candidate).Error; err != nil {
+		return 0, err
+	}
+
+	return candidate.ID, nil
+}
+
+// EditCandidate modify a single candidate
+func EditCandidate(id int, data interface{}) error {
+	if err := db.Model(&Candidate{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCandidate delete a candidate, its applications with their stage history, interviews and offers, and unlink its resumes
+func DeleteCandidate(id int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Resume{}).Where("candidate_id = ?", id).Update("candidate_id", 0).Error; err != nil {
+			return err
+		}
+		applicationIds := tx.Model(&Application{}).Select("id").Where("candidate_id = ?", id)
+		if err := deleteInterviewsOfApplications(tx, applicationIds); err != nil {
+			return err
+		}
+		if err := deleteOffersOfApplications(tx, applicationIds); err != nil {
+			return err
+		}
+		if err := tx.Where("application_id IN (?)", applicationIds).Delete(ApplicationStageLog{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("candidate_id = ?", id).Delete(Application{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(Candidate{}).Error
+	})
+}
+
+// ExistCandidateByID determines whether a candidate exists based on the ID
+func ExistCandidateByID(id int) (bool, error) {
+	var count int64
+	err := db.Model(&Candidate{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,304 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 面试状态
+const (
+	InterviewScheduled = "scheduled"
+	InterviewCancelled = "cancelled"
+	InterviewCompleted = "completed"
+)
+
+// InterviewStatuses 允许的面试状态
+var InterviewStatuses = []string{InterviewScheduled, InterviewCancelled, InterviewCompleted}
+
+// Interview 一场面试，StartTime 与 EndTime 为 Unix 时间戳，
+// Sequence 为日历邀请的版本号，每次改期或取消后递增
+type Interview struct {
+	ID            int            `json:"id" gorm:"primaryKey"`
+	ApplicationId int            `json:"application_id"`
+	Round         int            `json:"round"`
+	StartTime     int            `json:"start_time"`
+	EndTime       int            `json:"end_time"`
+	Location      string         `json:"location"`
+	MeetingUrl    string         `json:"meeting_url"`
+	Status        string         `json:"status"`
+	Sequence      int            `json:"sequence"`
+	Remark        string         `json:"remark"`
+	CreateUid     int            `json:"create_uid"`
+	CreateTime    int            `json:"create_time"`
+	UpdateTime    int            `json:"update_time"`
+	JobId         int            `json:"job_id" gorm:"->"`
+	JobName       string         `json:"job_name" gorm:"->"`
+	CandidateId   int            `json:"candidate_id" gorm:"->"`
+	CandidateName string         `json:"candidate_name" gorm:"->"`
+	Interviewers  []*Interviewer `json:"interviewers" gorm:"-"`
+}
+
+// InterviewInterviewer 面试与面试官的关联
+type InterviewInterviewer struct {
+	ID          int `json:"id" gorm:"primaryKey"`
+	InterviewId int `json:"interview_id"`
+	Uid         int `json:"uid"`
+	CreateTime  int `json:"create_time"`
+}
+
+// Interviewer 面试官信息
+type Interviewer struct {
+	InterviewId int    `json:"-"`
+	Uid         int    `json:"uid"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+}
+
+// InterviewConflict 面试官在该时间段已有的其他面试
+type InterviewConflict struct {
+	Uid         int    `json:"uid"`
+	Username    string `json:"username"`
+	InterviewId int    `json:"interview_id"`
+	StartTime   int    `json:"start_time"`
+	EndTime     int    `json:"end_time"`
+}
+
+func interviewQuery(maps interface{}) *gorm.DB {
+	return db.Model(&Interview{}).
+		Select("interviews.*, applications.job_id, applications.candidate_id, jobs.name AS job_name, candidates.name AS candidate_name").
+		Joins("LEFT JOIN applications ON applications.id = interviews.application_id").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Joins("LEFT JOIN candidates ON candidates.id = applications.candidate_id").
+		Where(maps)
+}
+
+// interviewerSubQuery 某个面试官参与的面试ID
+func interviewerSubQuery(uid int) *gorm.DB {
+	return db.Model(&InterviewInterviewer{}).Select("interview_id").Where("uid = ?", uid)
+}
+
+// loadInterviewers 批量查询面试官
+func loadInterviewers(interviews []*Interview) error {
+	if len(interviews) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(interviews))
+	byId := make(map[int]*Interview, len(interviews))
+	for _, interview := range interviews {
+		ids = append(ids, interview.ID)
+		byId[interview.ID] = interview
+		interview.Interviewers = []*Interviewer{}
+	}
+
+	var interviewers []*Interviewer
+	err := db.Model(&InterviewInterviewer{}).
+		Select("interview_interviewers.interview_id, interview_interviewers.uid, users.username, users.email").
+		Joins("LEFT JOIN users ON users.id = interview_interviewers.uid").
+		Where("interview_interviewers.interview_id IN ?", ids).
+		Order("interview_interviewers.id ASC").
+		Scan(&interviewers).Error
+	if err != nil {
+		return err
+	}
+
+	for _, interviewer := range interviewers {
+		if interview, ok := byId[interviewer.InterviewId]; ok {
+			interview.Interviewers = append(interview.Interviewers, interviewer)
+		}
+	}
+	return nil
+}
+
+func findInterviews(query *gorm.DB, page int, limit int) ([]*Interview, error) {
+	var datas []*Interview
+
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err := query.Find(&datas).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if err := loadInterviewers(datas); err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+// GetInterviews get interview list data ordered by start time
+func GetInterviews(page int, limit int, maps interface{}) ([]*Interview, error) {
+	return findInterviews(interviewQuery(maps).Order("interviews.start_time ASC"), page, limit)
+}
+
+// GetInterviewTotal counts the total number of interviews based on the constraint
+func GetInterviewTotal(maps interface{}) (int, error) {
+	var count int64
+
+	if err := db.Model(&Interview{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetInterviewsByInterviewer get interviews of an interviewer ending after since, ordered by start time
+func GetInterviewsByInterviewer(uid int, since int, page int, limit int, maps interface{}) ([]*Interview, error) {
+	query := interviewQuery(maps).
+		Where("interviews.id IN (?)", interviewerSubQuery(uid)).
+		Where("interviews.end_time >= ?", since).
+		Order("interviews.start_time ASC")
+	return findInterviews(query, page, limit)
+}
+
+// GetInterviewTotalByInterviewer counts interviews of an interviewer ending after since
+func GetInterviewTotalByInterviewer(uid int, since int, maps interface{}) (int, error) {
+	var count int64
+
+	err := db.Model(&Interview{}).Where(maps).
+		Where("interviews.id IN (?)", interviewerSubQuery(uid)).
+		Where("interviews.end_time >= ?", since).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetInterview Get an interview by id
+func GetInterview(id int) (*Interview, error) {
+	var d Interview
+	err := interviewQuery(map[string]interface{}{"interviews.id": id}).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if d.ID > 0 {
+		if err := loadInterviewers([]*Interview{&d}); err != nil {
+			return nil, err
+		}
+	}
+	return &d, nil
+}
+
+// GetInterviewConflicts get scheduled interviews of the given interviewers overlapping [start, end),
+// excludeId is the interview being rescheduled
+func GetInterviewConflicts(uids []int, start int, end int, excludeId int) ([]*InterviewConflict, error) {
+	var conflicts []*InterviewConflict
+	if len(uids) == 0 {
+		return conflicts, nil
+	}
+
+	err := db.Model(&InterviewInterviewer{}).
+		Select("interview_interviewers.uid, users.username, interviews.id AS interview_id, interviews.start_time, interviews.end_time").
+		Joins("JOIN interviews ON interviews.id = interview_interviewers.interview_id").
+		Joins("LEFT JOIN users ON users.id = interview_interviewers.uid").
+		Where("interview_interviewers.uid IN ?", uids).
+		Where("interviews.status = ? AND interviews.id <> ?", InterviewScheduled, excludeId).
+		Where("interviews.start_time < ? AND interviews.end_time > ?", end, start).
+		Order("interviews.start_time ASC").
+		Scan(&conflicts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return conflicts, nil
+}
+
+func replaceInterviewers(tx *gorm.DB, interviewId int, uids []int, now int) error {
+	if err := tx.Where("interview_id = ?", interviewId).Delete(InterviewInterviewer{}).Error; err != nil {
+		return err
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	rows := make([]InterviewInterviewer, 0, len(uids))
+	for _, uid := range uids {
+		rows = append(rows, InterviewInterviewer{InterviewId: interviewId, Uid: uid, CreateTime: now})
+	}
+	return tx.Create(&rows).Error
+}
+
+// AddInterview add an interview with its interviewers and returns its id
+func AddInterview(data map[string]interface{}, uids []int) (int, error) {
+	now := int(time.Now().Unix())
+	interview := Interview{
+		ApplicationId: data["application_id"].(int),
+		Round:         data["round"].(int),
+		StartTime:     data["start_time"].(int),
+		EndTime:       data["end_time"].(int),
+		Location:      data["location"].(string),
+		MeetingUrl:    data["meeting_url"].(string),
+		Status:        InterviewScheduled,
+		Remark:        data["remark"].(string),
+		CreateUid:     data["create_uid"].(int),
+		CreateTime:    now,
+		UpdateTime:    now,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&interview).Error; err != nil {
+			return err
+		}
+		return replaceInterviewers(tx, interview.ID, uids, now)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return interview.ID, nil
+}
+
+// EditInterview modify an interview, interviewers are replaced when uids is not nil
+func EditInterview(id int, data interface{}, uids []int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Interview{}).Where("id = ?", id).Updates(data).Error; err != nil {
+			return err
+		}
+		if uids == nil {
+			return nil
+		}
+		return replaceInterviewers(tx, id, uids, int(time.Now().Unix()))
+	})
+}
+
+// DeleteInterview delete an interview with its interviewers and scorecards
+func DeleteInterview(id int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("interview_id = ?", id).Delete(Scorecard{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("interview_id = ?", id).Delete(InterviewInterviewer{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(Interview{}).Error
+	})
+}
+
+// deleteInterviewsOfApplications delete interviews and scorecards of the applications selected by applicationIds
+func deleteInterviewsOfApplications(tx *gorm.DB, applicationIds interface{}) error {
+	if err := tx.Where("application_id IN (?)", applicationIds).Delete(Scorecard{}).Error; err != nil {
+		return err
+	}
+	interviewIds := tx.Model(&Interview{}).Select("id").Where("application_id IN (?)", applicationIds)
+	if err := tx.Where("interview_id IN (?)", interviewIds).Delete(InterviewInterviewer{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("application_id IN (?)", applicationIds).Delete(Interview{}).Error
+}
+
+// ExistInterviewByID determines whether an interview exists based on the ID
+func ExistInterviewByID(id int) (bool, error) {
+	var count int64
+	err := db.Model(&Interview{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
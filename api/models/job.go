@@ -6,29 +6,100 @@ import (
 	"gorm.io/gorm"
 )
 
+// 职位状态
+const (
+	JobDraft  = "draft"
+	JobOpen   = "open"
+	JobOnHold = "on_hold"
+	JobClosed = "closed"
+	JobFilled = "filled"
+)
+
+// JobStatuses 允许的职位状态
+var JobStatuses = []string{JobDraft, JobOpen, JobOnHold, JobClosed, JobFilled}
+
+// 用工类型
+const (
+	EmploymentFullTime   = "full_time"
+	EmploymentPartTime   = "part_time"
+	EmploymentContract   = "contract"
+	EmploymentInternship = "internship"
+)
+
+// EmploymentTypes 允许的用工类型
+var EmploymentTypes = []string{EmploymentFullTime, EmploymentPartTime, EmploymentContract, EmploymentInternship}
+
 type Job struct {
-	ID         int    `json:"id" gorm:"primaryKey"`
-	Name       string `json:"name"`
-	Demand     string `json:"demand"`
-	Desc       string `json:"desc"`
-	CreateUid  int    `json:"create_uid"`
-	CreateUser string `json:"create_user" gorm:"-"`
-	CreateTime int    `json:"create_time"`
-	UpdateTime int    `json:"update_time"`
+	ID     int    `json:"id" gorm:"primaryKey"`
+	Name   string `json:"name"`
+	Demand string `json:"demand"`
+	Desc   string `json:"desc"`
+	// PromptTemplate AI分析使用的提示词模板，为空时使用 default
+	PromptTemplate string `json:"prompt_template"`
+	CreateUid      int    `json:"create_uid"`
+	CreateUser     string `json:"create_user" gorm:"-"`
+	CreateTime     int    `json:"create_time"`
+	UpdateTime     int    `json:"update_time"`
+
+	// Stages 逗号分隔的招聘流程阶段，为空时使用默认流程
+	Stages string `json:"stages"`
+	// Criteria 逗号分隔的面试评价维度，为空时使用默认维度
+	Criteria string `json:"criteria"`
+
+	Status    string `json:"status"`
+	Headcount int    `json:"headcount"`
+	// Department 所属部门，Location 工作地点
+	Department string `json:"department"`
+	Location   string `json:"location"`
+	// 月薪范围，0 表示未填写
+	SalaryMin      int    `json:"salary_min"`
+	SalaryMax      int    `json:"salary_max"`
+	EmploymentType string `json:"employment_type"`
+	// HiringManagerUid 用人经理，0 表示未指定
+	HiringManagerUid int    `json:"hiring_manager_uid"`
+	HiringManager    string `json:"hiring_manager" gorm:"-"`
+	// CloseTime 计划截止招聘的时间，0 表示不限
+	CloseTime int `json:"close_time"`
+}
+
+// JobFilter 职位列表的范围筛选条件，0 表示不限
+type JobFilter struct {
+	Keyword string
+	// 薪资范围与 [SalaryMin, SalaryMax] 有交集的职位
+	SalaryMin int
+	SalaryMax int
+	// CloseBefore 截止时间在该时间之前的职位
+	CloseBefore int
+}
+
+// jobQuery 按等值条件 maps 与范围条件 filter 查询职位
+func jobQuery(maps interface{}, filter JobFilter) *gorm.DB {
+	query := db.Model(&Job{}).Where(maps)
+
+	if filter.Keyword != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Keyword+"%")
+	}
+	if filter.SalaryMin > 0 {
+		query = query.Where("(salary_max = 0 OR salary_max >= ?)", filter.SalaryMin)
+	}
+	if filter.SalaryMax > 0 {
+		query = query.Where("salary_min <= ?", filter.SalaryMax)
+	}
+	if filter.CloseBefore > 0 {
+		query = query.Where("close_time > 0 AND close_time <= ?", filter.CloseBefore)
+	}
+
+	return query
 }
 
 // GetJobs get job list data
-func GetJobs(page int, limit int, keyword string, maps interface{}) ([]*Job, error) {
+func GetJobs(page int, limit int, filter JobFilter, maps interface{}) ([]*Job, error) {
 	var (
 		jobs []*Job
 		err  error
 	)
 
-	query := db.Model(&Job{}).Where(maps)
-
-	if keyword != "" {
-		query = query.Where("name LIKE ?", "%"+keyword+"%")
-	}
+	query := jobQuery(maps, filter)
 
 	if page > 0 && limit > 0 {
 		offset := (page - 1) * limit
@@ -49,6 +120,14 @@ func GetJobs(page int, limit int, keyword string, maps interface{}) ([]*Job, err
 				return nil, err
 			}
 			jobs[index].CreateUser = user.Username
+
+			if job.HiringManagerUid > 0 {
+				manager, err := GetUser(job.HiringManagerUid)
+				if err != nil {
+					return nil, err
+				}
+				jobs[index].HiringManager = manager.Username
+			}
 		}
 	}
 
@@ -67,15 +146,10 @@ func GetJob(id int) (*Job, error) {
 }
 
 // GetJobTotal counts the total number of jobs based on the constraint
-func GetJobTotal(keyword string, maps interface{}) (int, error) {
+func GetJobTotal(filter JobFilter, maps interface{}) (int, error) {
 	var count int64
 
-	query := db.Model(&Job{}).Where(maps)
-	if keyword != "" {
-		query = query.Where("name LIKE ?", "%"+keyword+"%")
-	}
-
-	if err := query.Count(&count).Error; err != nil {
+	if err := jobQuery(maps, filter).Count(&count).Error; err != nil {
 		return 0, err
 	}
 
@@ -86,11 +160,24 @@ func GetJobTotal(keyword string, maps interface{}) (int, error) {
 func AddJob(data map[string]interface{}) error {
 	now := int(time.Now().Unix())
 	job := Job{
-		Name:       data["name"].(string),
-		Demand:     data["demand"].(string),
-		Desc:       data["desc"].(string),
-		CreateTime: now,
-		CreateUid:  data["create_uid"].(int),
+		Name:           data["name"].(string),
+		Demand:         data["demand"].(string),
+		Desc:           data["desc"].(string),
+		PromptTemplate: data["prompt_template"].(string),
+		Stages:         data["stages"].(string),
+		Criteria:       data["criteria"].(string),
+		CreateTime:     now,
+		CreateUid:      data["create_uid"].(int),
+
+		Status:           data["status"].(string),
+		Headcount:        data["headcount"].(int),
+		Department:       data["department"].(string),
+		Location:         data["location"].(string),
+		SalaryMin:        data["salary_min"].(int),
+		SalaryMax:        data["salary_max"].(int),
+		EmploymentType:   data["employment_type"].(string),
+		HiringManagerUid: data["hiring_manager_uid"].(int),
+		CloseTime:        data["close_time"].(int),
 	}
 	if err := db.Debug().Create(&job).Error; err != nil {
 		return err
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// JobCandidate 招聘需求下的候选简历及其最新AI分析评分
+type JobCandidate struct {
+	ResumeId     int    `json:"resume_id"`
+	FileName     string `json:"filename" gorm:"column:filename"`
+	CreateUid    int    `json:"create_uid"`
+	CreateTime   int    `json:"create_time"`
+	AnalysisId   int    `json:"analysis_id"`
+	MatchScore   int    `json:"match_score"`
+	EstimatedYoe int    `json:"estimated_yoe"`
+	Skills       string `json:"skills"`
+}
+
+// JobCandidateFilter 候选人筛选条件
+type JobCandidateFilter struct {
+	MinScore int
+	MinYoe   int
+	// Skills 要求全部具备的技术技能（小写）
+	Skills []string
+}
+
+// candidateQuery 每份简历只关联其在该招聘需求下最新的一次分析
+func candidateQuery(jobId int, filter JobCandidateFilter) *gorm.DB {
+	query := db.Table("resumes").
+		Joins(`LEFT JOIN resume_analyses ra ON ra.id = (
+			SELECT MAX(a.id) FROM resume_analyses a WHERE a.resume_id = resumes.id AND a.job_id = resumes.job_id)`).
+		Where("resumes.job_id = ?", jobId)
+
+	if filter.MinScore > 0 {
+		query = query.Where("ra.match_score >= ?", filter.MinScore)
+	}
+	if filter.MinYoe > 0 {
+		query = query.Where("ra.estimated_yoe >= ?", filter.MinYoe)
+	}
+	for _, skill := range filter.Skills {
+		query = query.Where(`CONCAT(',', ra.skills, ',') LIKE ? ESCAPE '\\'`, "%,"+escapeLike(strings.ToLower(skill))+",%")
+	}
+
+	return query
+}
+
+// likeEscaper 转义 LIKE 中的通配符，使技能名中的 % _ \ 按字面匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// GetJobCandidates get resumes of a job ranked by match score, unanalyzed resumes last
+func GetJobCandidates(page int, limit int, jobId int, filter JobCandidateFilter) ([]*JobCandidate, error) {
+	var datas []*JobCandidate
+
+	query := candidateQuery(jobId, filter).
+		Select(`resumes.id AS resume_id, resumes.filename, resumes.create_uid, resumes.create_time,
+			COALESCE(ra.id, 0) AS analysis_id, COALESCE(ra.match_score, 0) AS match_score,
+			COALESCE(ra.estimated_yoe, 0) AS estimated_yoe, COALESCE(ra.skills, '') AS skills`).
+		Order("ra.id IS NULL, ra.match_score DESC, resumes.id DESC")
+
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err := query.Scan(&datas).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetJobCandidateTotal counts the candidates of a job based on the filter
+func GetJobCandidateTotal(jobId int, filter JobCandidateFilter) (int, error) {
+	var count int64
+
+	if err := candidateQuery(jobId, filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationPreference 用户对某个通知事件的邮件偏好，没有记录时默认接收
+type NotificationPreference struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	Uid        int    `json:"uid"`
+	Event      string `json:"event"`
+	Enabled    bool   `json:"enabled"`
+	UpdateTime int    `json:"update_time"`
+}
+
+// GetNotificationPreferences get the notification preferences of a user
+func GetNotificationPreferences(uid int) ([]*NotificationPreference, error) {
+	var datas []*NotificationPreference
+	err := db.Model(&NotificationPreference{}).Where("uid = ?", uid).Find(&datas).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// SetNotificationPreferences save the preferences of a user, keyed by event
+func SetNotificationPreferences(uid int, prefs map[string]bool) error {
+	now := int(time.Now().Unix())
+	return db.Transaction(func(tx *gorm.DB) error {
+		for event, enabled := range prefs {
+			result := tx.Model(&NotificationPreference{}).
+				Where("uid = ? AND event = ?", uid, event).
+				Updates(map[string]interface{}{"enabled": enabled, "update_time": now})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				continue
+			}
+
+			// 值未变化时 RowsAffected 也为 0，先确认记录是否存在
+			var count int64
+			if err := tx.Model(&NotificationPreference{}).Where("uid = ? AND event = ?", uid, event).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+			if err := tx.Create(&NotificationPreference{Uid: uid, Event: event, Enabled: enabled, UpdateTime: now}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetNotificationOptOuts get the users among uids who turned off notifications of event
+func GetNotificationOptOuts(event string, uids []int) ([]int, error) {
+	var optOuts []int
+	err := db.Model(&NotificationPreference{}).
+		Where("event = ? AND uid IN ? AND enabled = ?", event, uids, false).
+		Pluck("uid", &optOuts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return optOuts, nil
+}
@@ -0,0 +1,274 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// offer 状态
+const (
+	OfferDraft           = "draft"
+	OfferPendingApproval = "pending_approval"
+	OfferSent            = "sent"
+	OfferAccepted        = "accepted"
+	OfferDeclined        = "declined"
+	OfferExpired         = "expired"
+	OfferWithdrawn       = "withdrawn"
+)
+
+// OfferStatuses 允许的 offer 状态
+var OfferStatuses = []string{OfferDraft, OfferPendingApproval, OfferSent, OfferAccepted, OfferDeclined, OfferExpired, OfferWithdrawn}
+
+// 审批状态
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// Offer 发给候选人的录用通知，Salary 为税前月薪（元），StartDate 为 2006-01-02 格式的入职日期，
+// ExpireTime 为候选人确认的截止时间，CurrentStep 为当前待审批的审批人序号（从 1 开始）
+type Offer struct {
+	ID            int              `json:"id" gorm:"primaryKey"`
+	ApplicationId int              `json:"application_id"`
+	Salary        int              `json:"salary"`
+	Level         string           `json:"level"`
+	StartDate     string           `json:"start_date"`
+	ExpireTime    int              `json:"expire_time"`
+	Template      string           `json:"template"`
+	Status        string           `json:"status"`
+	CurrentStep   int              `json:"current_step"`
+	Remark        string           `json:"remark"`
+	SentTime      int              `json:"sent_time"`
+	RespondTime   int              `json:"respond_time"`
+	CreateUid     int              `json:"create_uid"`
+	CreateTime    int              `json:"create_time"`
+	UpdateTime    int              `json:"update_time"`
+	JobId         int              `json:"job_id" gorm:"->"`
+	JobName       string           `json:"job_name" gorm:"->"`
+	CandidateId   int              `json:"candidate_id" gorm:"->"`
+	CandidateName string           `json:"candidate_name" gorm:"->"`
+	Approvals     []*OfferApproval `json:"approvals" gorm:"-"`
+}
+
+// OfferApproval 审批链中的一个审批人，按 Step 顺序依次审批
+type OfferApproval struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	OfferId    int    `json:"offer_id"`
+	Step       int    `json:"step"`
+	Uid        int    `json:"uid"`
+	Status     string `json:"status"`
+	Comment    string `json:"comment"`
+	ActTime    int    `json:"act_time"`
+	CreateTime int    `json:"create_time"`
+	Username   string `json:"username" gorm:"->"`
+}
+
+func offerQuery(maps interface{}) *gorm.DB {
+	return db.Model(&Offer{}).
+		Select("offers.*, applications.job_id, applications.candidate_id, jobs.name AS job_name, candidates.name AS candidate_name").
+		Joins("LEFT JOIN applications ON applications.id = offers.application_id").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Joins("LEFT JOIN candidates ON candidates.id = applications.candidate_id").
+		Where(maps)
+}
+
+// GetOffers get offer list data, newest first
+func GetOffers(page int, limit int, maps interface{}) ([]*Offer, error) {
+	var (
+		datas []*Offer
+		err   error
+	)
+
+	query := offerQuery(maps).Order("offers.id DESC")
+
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err = query.Find(&datas).Error
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetOfferTotal counts the total number of offers based on the constraint
+func GetOfferTotal(maps interface{}) (int, error) {
+	var count int64
+
+	if err := db.Model(&Offer{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetOffer Get an offer with its approval chain by id
+func GetOffer(id int) (*Offer, error) {
+	var d Offer
+	err := offerQuery(map[string]interface{}{"offers.id": id}).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if d.ID > 0 {
+		d.Approvals, err = GetOfferApprovals(d.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &d, nil
+}
+
+// GetOfferApprovals get the approval chain of an offer in order
+func GetOfferApprovals(offerId int) ([]*OfferApproval, error) {
+	var datas []*OfferApproval
+	err := db.Model(&OfferApproval{}).
+		Select("offer_approvals.*, users.username").
+		Joins("LEFT JOIN users ON users.id = offer_approvals.uid").
+		Where("offer_approvals.offer_id = ?", offerId).
+		Order("offer_approvals.step ASC").
+		Find(&datas).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetPendingOfferApprovals get offers waiting for uid to approve
+func GetPendingOfferApprovals(uid int) ([]*Offer, error) {
+	var datas []*Offer
+	err := offerQuery(map[string]interface{}{"offers.status": OfferPendingApproval}).
+		Joins("JOIN offer_approvals ON offer_approvals.offer_id = offers.id AND offer_approvals.step = offers.current_step").
+		Where("offer_approvals.uid = ?", uid).
+		Order("offers.id ASC").
+		Find(&datas).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+func replaceOfferApprovals(tx *gorm.DB, offerId int, uids []int, now int) error {
+	if err := tx.Where("offer_id = ?", offerId).Delete(OfferApproval{}).Error; err != nil {
+		return err
+	}
+	if len(uids) == 0 {
+		return nil
+	}
+
+	rows := make([]OfferApproval, 0, len(uids))
+	for i, uid := range uids {
+		rows = append(rows, OfferApproval{
+			OfferId:    offerId,
+			Step:       i + 1,
+			Uid:        uid,
+			Status:     ApprovalPending,
+			CreateTime: now,
+		})
+	}
+	return tx.Create(&rows).Error
+}
+
+// AddOffer add a draft offer with its ordered approvers and returns its id
+func AddOffer(data map[string]interface{}, approvers []int) (int, error) {
+	now := int(time.Now().Unix())
+	offer := Offer{
+		ApplicationId: data["application_id"].(int),
+		Salary:        data["salary"].(int),
+		Level:         data["level"].(string),
+		StartDate:     data["start_date"].(string),
+		ExpireTime:    data["expire_time"].(int),
+		Template:      data["template"].(string),
+		Status:        OfferDraft,
+		Remark:        data["remark"].(string),
+		CreateUid:     data["create_uid"].(int),
+		CreateTime:    now,
+		UpdateTime:    now,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&offer).Error; err != nil {
+			return err
+		}
+		return replaceOfferApprovals(tx, offer.ID, approvers, now)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return offer.ID, nil
+}
+
+// EditOffer modify an offer, approvers are replaced when not nil
+func EditOffer(id int, data interface{}, approvers []int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Offer{}).Where("id = ?", id).Updates(data).Error; err != nil {
+			return err
+		}
+		if approvers == nil {
+			return nil
+		}
+		return replaceOfferApprovals(tx, id, approvers, int(time.Now().Unix()))
+	})
+}
+
+// TransitOffer update an offer only if it is still in status from, returns whether it was updated
+func TransitOffer(id int, from string, data map[string]interface{}) (bool, error) {
+	result := db.Model(&Offer{}).Where("id = ? AND status = ?", id, from).Updates(data)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ResetOfferApprovals mark every approval of an offer as pending again
+func ResetOfferApprovals(offerId int) error {
+	return db.Model(&OfferApproval{}).Where("offer_id = ?", offerId).
+		Updates(map[string]interface{}{"status": ApprovalPending, "comment": "", "act_time": 0}).Error
+}
+
+// EditOfferApproval record the decision of an approver
+func EditOfferApproval(id int, data interface{}) error {
+	if err := db.Model(&OfferApproval{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteOffer delete an offer and its approval chain
+func DeleteOffer(id int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("offer_id = ?", id).Delete(OfferApproval{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(Offer{}).Error
+	})
+}
+
+// deleteOffersOfApplications delete offers and approvals of the applications selected by applicationIds
+func deleteOffersOfApplications(tx *gorm.DB, applicationIds interface{}) error {
+	offerIds := tx.Model(&Offer{}).Select("id").Where("application_id IN (?)", applicationIds)
+	if err := tx.Where("offer_id IN (?)", offerIds).Delete(OfferApproval{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("application_id IN (?)", applicationIds).Delete(Offer{}).Error
+}
+
+// ExistOfferByID determines whether an offer exists based on the ID
+func ExistOfferByID(id int) (bool, error) {
+	var count int64
+	err := db.Model(&Offer{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -1,23 +1,38 @@
 package models
 
 import (
+	"fmt"
 	"hr-api/pkg/util"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// 重复简历的判定依据
+const (
+	DuplicateContent  = "content"
+	DuplicateIdentity = "identity"
+	DuplicateSimilar  = "similar"
+)
+
 type Resume struct {
-	ID         int    `json:"id" gorm:"primaryKey"`
-	JobId      int    `json:"job_id"`
-	FileName   string `json:"filename" gorm:"column:filename"`
-	Url        string `json:"-" gorm:"column:url"`
-	Size       int    `json:"size"`
-	CreateUid  int    `json:"create_uid"`
-	CreateUser string `json:"create_user" gorm:"-"`
-	CreateTime int    `json:"create_time"`
-	UpdateTime int    `json:"update_time"`
-	JobName    string `json:"job_name" gorm:"->"`
+	ID          int    `json:"id" gorm:"primaryKey"`
+	JobId       int    `json:"job_id"`
+	CandidateId int    `json:"candidate_id"`
+	FileName    string `json:"filename" gorm:"column:filename"`
+	Url         string `json:"-" gorm:"column:url"`
+	Size        int    `json:"size"`
+	ContentHash string `json:"-"`
+	Minhash     string `json:"-"`
+	// DuplicateOf 可能重复的最早一份简历，DuplicateReason 为判定依据
+	DuplicateOf     int    `json:"duplicate_of"`
+	DuplicateReason string `json:"duplicate_reason"`
+	DuplicateHint   string `json:"duplicate_hint,omitempty" gorm:"-"`
+	CreateUid       int    `json:"create_uid"`
+	CreateUser      string `json:"create_user" gorm:"-"`
+	CreateTime      int    `json:"create_time"`
+	UpdateTime      int    `json:"update_time"`
+	JobName         string `json:"job_name" gorm:"->"`
 }
 
 // GetResumes get resume list data
@@ -53,6 +68,9 @@ func GetResumes(page int, limit int, keyword string, maps interface{}) ([]*Resum
 				return nil, err
 			}
 			datas[index].CreateUser = user.Username
+			if job.DuplicateOf > 0 {
+				datas[index].DuplicateHint = fmt.Sprintf("possible duplicate of #%d", job.DuplicateOf)
+			}
 		}
 	}
 
@@ -70,6 +88,17 @@ func GetResume(id int) (*Resume, error) {
 	return &d, nil
 }
 
+// GetResumeIdsByJob Get ids of all resumes attached to a job
+func GetResumeIdsByJob(jobId int) ([]int, error) {
+	var ids []int
+	err := db.Model(&Resume{}).Where("job_id = ?", jobId).Order("id").Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 // GetResumeTotal counts the total number of resumes based on the constraint
 func GetResumeTotal(keyword string, maps interface{}) (int, error) {
 	var count int64
@@ -86,24 +115,76 @@ func GetResumeTotal(keyword string, maps interface{}) (int, error) {
 	return int(count), nil
 }
 
-// AddResume add a single resume
-func AddResume(data map[string]interface{}) error {
+// AddResume add a single resume and returns its id
+func AddResume(data map[string]interface{}) (int, error) {
 	now := int(time.Now().Unix())
 	rawUrl := data["url"].(string)
 	filename := util.GetFilenameFromURL(rawUrl)
 	job := Resume{
-		JobId:      data["job_id"].(int),
-		FileName:   filename,
-		Url:        rawUrl,
-		Size:       data["size"].(int),
-		CreateTime: now,
-		CreateUid:  data["create_uid"].(int),
+		JobId:       data["job_id"].(int),
+		FileName:    filename,
+		Url:         rawUrl,
+		Size:        data["size"].(int),
+		ContentHash: data["content_hash"].(string),
+		CreateTime:  now,
+		CreateUid:   data["create_uid"].(int),
 	}
 	if err := db.Create(&job).Error; err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return job.ID, nil
+}
+
+// GetFirstResumeByContentHash get the earliest other resume with the same file content
+func GetFirstResumeByContentHash(id int, hash string) (*Resume, error) {
+	var d Resume
+	err := db.Where("content_hash = ? AND id <> ?", hash, id).Order("id").First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// GetFirstResumeByCandidate get the earliest other resume of the same candidate
+func GetFirstResumeByCandidate(id int, candidateId int) (*Resume, error) {
+	var d Resume
+	err := db.Where("candidate_id = ? AND id <> ?", candidateId, id).Order("id").First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// GetResumesByCandidate get all resumes of a candidate with the job names, oldest first
+func GetResumesByCandidate(candidateId int) ([]*Resume, error) {
+	var datas []*Resume
+	err := db.Select("jobs.name AS job_name, resumes.*").
+		Joins("LEFT JOIN jobs ON jobs.id = resumes.job_id").
+		Where("resumes.candidate_id = ?", candidateId).
+		Order("resumes.id").
+		Find(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetResumesByIds get resumes by ids, ordered by id
+func GetResumesByIds(ids []int) ([]*Resume, error) {
+	var datas []*Resume
+	if len(ids) == 0 {
+		return datas, nil
+	}
+
+	if err := db.Where("id IN ?", ids).Order("id").Find(&datas).Error; err != nil {
+		return nil, err
+	}
+
+	return datas, nil
 }
 
 // EditResume modify a single resume
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ResumeAnalysis struct {
+	ID            int             `json:"id" gorm:"primaryKey"`
+	ResumeId      int             `json:"resume_id"`
+	JobId         int             `json:"job_id"`
+	Deployment    string          `json:"deployment"`
+	PromptVersion string          `json:"prompt_version"`
+	MatchScore    int             `json:"match_score"`
+	EstimatedYoe  int             `json:"estimated_yoe"`
+	Skills        string          `json:"skills"`
+	PersonalInfo  json.RawMessage `json:"personal_info" gorm:"type:text"`
+	Result        json.RawMessage `json:"result" gorm:"type:mediumtext"`
+	CreateUid     int             `json:"create_uid"`
+	CreateTime    int             `json:"create_time"`
+}
+
+// GetResumeAnalyses get analysis history of a resume, newest first
+func GetResumeAnalyses(page int, limit int, maps interface{}) ([]*ResumeAnalysis, error) {
+	var (
+		datas []*ResumeAnalysis
+		err   error
+	)
+
+	query := db.Model(&ResumeAnalysis{}).Where(maps).Order("id DESC")
+
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err = query.Find(&datas).Error
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// ResumeAnalysisBrief 不含原始结果的分析摘要
+type ResumeAnalysisBrief struct {
+	ID            int    `json:"id"`
+	ResumeId      int    `json:"resume_id"`
+	JobId         int    `json:"job_id"`
+	JobName       string `json:"job_name"`
+	Deployment    string `json:"deployment"`
+	PromptVersion string `json:"prompt_version"`
+	MatchScore    int    `json:"match_score"`
+	EstimatedYoe  int    `json:"estimated_yoe"`
+	CreateTime    int    `json:"create_time"`
+}
+
+// GetResumeAnalysisBriefs get analysis summaries of the given resumes, oldest first
+func GetResumeAnalysisBriefs(resumeIds []int) ([]*ResumeAnalysisBrief, error) {
+	var datas []*ResumeAnalysisBrief
+	if len(resumeIds) == 0 {
+		return datas, nil
+	}
+
+	err := db.Model(&ResumeAnalysis{}).
+		Select(`resume_analyses.id, resume_analyses.resume_id, resume_analyses.job_id, jobs.name AS job_name,
+			resume_analyses.deployment, resume_analyses.prompt_version, resume_analyses.match_score,
+			resume_analyses.estimated_yoe, resume_analyses.create_time`).
+		Joins("LEFT JOIN jobs ON jobs.id = resume_analyses.job_id").
+		Where("resume_analyses.resume_id IN ?", resumeIds).
+		Order("resume_analyses.id").
+		Scan(&datas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetResumeAnalysisTotal counts the total number of analyses based on the constraint
+func GetResumeAnalysisTotal(maps interface{}) (int, error) {
+	var count int64
+
+	if err := db.Model(&ResumeAnalysis{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetLatestResumeAnalysis Get the latest analysis of a resume
+func GetLatestResumeAnalysis(maps interface{}) (*ResumeAnalysis, error) {
+	var d ResumeAnalysis
+	err := db.Model(&ResumeAnalysis{}).Where(maps).Order("id DESC").First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// GetLatestModelResumeAnalysis Get the latest analysis of a resume for a job that was not made by rules
+func GetLatestModelResumeAnalysis(resumeId, jobId int, rulesDeployment string) (*ResumeAnalysis, error) {
+	var d ResumeAnalysis
+	err := db.Model(&ResumeAnalysis{}).
+		Where("resume_id = ? AND job_id = ? AND deployment <> ?", resumeId, jobId, rulesDeployment).
+		Order("id DESC").First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// AddResumeAnalysis add a single analysis result
+func AddResumeAnalysis(data map[string]interface{}) error {
+	now := int(time.Now().Unix())
+	analysis := ResumeAnalysis{
+		ResumeId:      data["resume_id"].(int),
+		JobId:         data["job_id"].(int),
+		Deployment:    data["deployment"].(string),
+		PromptVersion: data["prompt_version"].(string),
+		MatchScore:    data["match_score"].(int),
+		EstimatedYoe:  data["estimated_yoe"].(int),
+		Skills:        data["skills"].(string),
+		PersonalInfo:  data["personal_info"].([]byte),
+		Result:        data["result"].([]byte),
+		CreateUid:     data["create_uid"].(int),
+		CreateTime:    now,
+	}
+	if err := db.Create(&analysis).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteResumeAnalyses delete all analyses of a resume
+func DeleteResumeAnalyses(resumeId int) error {
+	if err := db.Where("resume_id = ?", resumeId).Delete(ResumeAnalysis{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
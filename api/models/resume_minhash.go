@@ -0,0 +1,52 @@
+package models
+
+// ResumeMinhashBand 简历 MinHash 签名的 LSH 分段，用于快速查找近似重复的简历
+type ResumeMinhashBand struct {
+	ID       int   `json:"id" gorm:"primaryKey"`
+	ResumeId int   `json:"resume_id"`
+	Band     int64 `json:"band"`
+}
+
+// ReplaceResumeMinhashBands replace the LSH bands of a resume
+func ReplaceResumeMinhashBands(resumeId int, bands []int64) error {
+	if err := DeleteResumeMinhashBands(resumeId); err != nil {
+		return err
+	}
+	if len(bands) == 0 {
+		return nil
+	}
+
+	rows := make([]ResumeMinhashBand, 0, len(bands))
+	for _, band := range bands {
+		rows = append(rows, ResumeMinhashBand{ResumeId: resumeId, Band: band})
+	}
+	return db.Create(&rows).Error
+}
+
+// GetResumeIdsByBands get ids of other resumes sharing at least one band
+func GetResumeIdsByBands(resumeId int, bands []int64) ([]int, error) {
+	var ids []int
+	if len(bands) == 0 {
+		return ids, nil
+	}
+
+	err := db.Model(&ResumeMinhashBand{}).
+		Where("band IN ? AND resume_id <> ?", bands, resumeId).
+		Distinct().
+		Order("resume_id").
+		Pluck("resume_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// DeleteResumeMinhashBands delete all bands of a resume
+func DeleteResumeMinhashBands(resumeId int) error {
+	if err := db.Where("resume_id = ?", resumeId).Delete(ResumeMinhashBand{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
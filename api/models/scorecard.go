@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 面试官的录用建议
+const (
+	RecommendHire   = "hire"
+	RecommendNoHire = "no_hire"
+)
+
+// Scorecard 面试官对一场面试的结构化评价，Ratings 为按职位评价维度打分的 JSON 数组
+type Scorecard struct {
+	ID             int             `json:"id" gorm:"primaryKey"`
+	InterviewId    int             `json:"interview_id"`
+	ApplicationId  int             `json:"application_id"`
+	Uid            int             `json:"uid"`
+	Ratings        json.RawMessage `json:"ratings" gorm:"type:text"`
+	Recommendation string          `json:"recommendation"`
+	Comment        string          `json:"comment"`
+	CreateTime     int             `json:"create_time"`
+	UpdateTime     int             `json:"update_time"`
+	Username       string          `json:"username" gorm:"->"`
+	Round          int             `json:"round" gorm:"->"`
+}
+
+// GetScorecards get scorecards with interviewer names, ordered by interview round
+func GetScorecards(maps interface{}) ([]*Scorecard, error) {
+	var datas []*Scorecard
+	err := db.Model(&Scorecard{}).
+		Select("scorecards.*, users.username, interviews.round").
+		Joins("LEFT JOIN users ON users.id = scorecards.uid").
+		Joins("LEFT JOIN interviews ON interviews.id = scorecards.interview_id").
+		Where(maps).
+		Order("interviews.round ASC, scorecards.id ASC").
+		Find(&datas).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetScorecard get the scorecard of an interviewer for an interview
+func GetScorecard(interviewId int, uid int) (*Scorecard, error) {
+	var d Scorecard
+	err := db.Where("interview_id = ? AND uid = ?", interviewId, uid).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// CountPendingScorecards counts interviews of an application assigned to uid,
+// not cancelled, that uid has not submitted a scorecard for
+func CountPendingScorecards(applicationId int, uid int) (int, error) {
+	var count int64
+	err := db.Model(&Interview{}).
+		Where("interviews.application_id = ? AND interviews.status <> ?", applicationId, InterviewCancelled).
+		Where("interviews.id IN (?)", interviewerSubQuery(uid)).
+		Where("interviews.id NOT IN (?)", db.Model(&Scorecard{}).Select("interview_id").Where("uid = ?", uid)).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// AddScorecard add a single scorecard
+func AddScorecard(data map[string]interface{}) error {
+	now := int(time.Now().Unix())
+	scorecard := Scorecard{
+		InterviewId:    data["interview_id"].(int),
+		ApplicationId:  data["application_id"].(int),
+		Uid:            data["uid"].(int),
+		Ratings:        data["ratings"].([]byte),
+		Recommendation: data["recommendation"].(string),
+		Comment:        data["comment"].(string),
+		CreateTime:     now,
+		UpdateTime:     now,
+	}
+	if err := db.Create(&scorecard).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EditScorecard modify a single scorecard
+func EditScorecard(id int, data interface{}) error {
+	if err := db.Model(&Scorecard{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
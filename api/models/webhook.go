@@ -0,0 +1,221 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// webhook 投递状态
+const (
+	DeliveryPending  = "pending"
+	DeliveryRetrying = "retrying"
+	DeliverySuccess  = "success"
+	DeliveryFailed   = "failed"
+)
+
+// Webhook 职位的 webhook 订阅，Events 为逗号分隔的事件，MinScore 为高匹配度事件的分数阈值
+type Webhook struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	JobId      int    `json:"job_id"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Url        string `json:"url"`
+	Secret     string `json:"-"`
+	Events     string `json:"events"`
+	MinScore   int    `json:"min_score"`
+	Enabled    bool   `json:"enabled"`
+	CreateUid  int    `json:"create_uid"`
+	CreateTime int    `json:"create_time"`
+	UpdateTime int    `json:"update_time"`
+	JobName    string `json:"job_name" gorm:"->"`
+}
+
+// WebhookDelivery 一次事件投递及其最近一次尝试的结果，Attempts 为已尝试的次数
+type WebhookDelivery struct {
+	ID            int    `json:"id" gorm:"primaryKey"`
+	WebhookId     int    `json:"webhook_id"`
+	Event         string `json:"event"`
+	EventId       string `json:"event_id"`
+	Payload       string `json:"payload"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	ResponseCode  int    `json:"response_code"`
+	ResponseBody  string `json:"response_body"`
+	Error         string `json:"error"`
+	NextRetryTime int    `json:"next_retry_time"`
+	CreateTime    int    `json:"create_time"`
+	UpdateTime    int    `json:"update_time"`
+}
+
+func webhookQuery(maps interface{}) *gorm.DB {
+	return db.Model(&Webhook{}).
+		Select("webhooks.*, jobs.name AS job_name").
+		Joins("LEFT JOIN jobs ON jobs.id = webhooks.job_id").
+		Where(maps)
+}
+
+// GetWebhooks get webhook list data
+func GetWebhooks(page int, limit int, maps interface{}) ([]*Webhook, error) {
+	var (
+		datas []*Webhook
+		err   error
+	)
+
+	query := webhookQuery(maps).Order("webhooks.id DESC")
+
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err = query.Find(&datas).Error
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetWebhookTotal counts the total number of webhooks based on the constraint
+func GetWebhookTotal(maps interface{}) (int, error) {
+	var count int64
+
+	if err := db.Model(&Webhook{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetWebhook Get a webhook by id
+func GetWebhook(id int) (*Webhook, error) {
+	var d Webhook
+	err := webhookQuery(map[string]interface{}{"webhooks.id": id}).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// AddWebhook add a webhook subscription and returns its id
+func AddWebhook(data map[string]interface{}) (int, error) {
+	now := int(time.Now().Unix())
+	webhook := Webhook{
+		JobId:      data["job_id"].(int),
+		Name:       data["name"].(string),
+		Kind:       data["kind"].(string),
+		Url:        data["url"].(string),
+		Secret:     data["secret"].(string),
+		Events:     data["events"].(string),
+		MinScore:   data["min_score"].(int),
+		Enabled:    data["enabled"].(bool),
+		CreateUid:  data["create_uid"].(int),
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := db.Create(&webhook).Error; err != nil {
+		return 0, err
+	}
+
+	return webhook.ID, nil
+}
+
+// EditWebhook modify a webhook subscription
+func EditWebhook(id int, data interface{}) error {
+	if err := db.Model(&Webhook{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhook delete a webhook subscription and its delivery log
+func DeleteWebhook(id int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(Webhook{}).Error
+	})
+}
+
+// ExistWebhookByID determines whether a webhook exists based on the ID
+func ExistWebhookByID(id int) (bool, error) {
+	var count int64
+	err := db.Model(&Webhook{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetWebhookDeliveries get the delivery log of a webhook, newest first
+func GetWebhookDeliveries(page int, limit int, maps interface{}) ([]*WebhookDelivery, error) {
+	var datas []*WebhookDelivery
+
+	query := db.Model(&WebhookDelivery{}).Where(maps).Order("id DESC")
+	if page > 0 && limit > 0 {
+		offset := (page - 1) * limit
+		query = query.Offset(offset).Limit(limit)
+	}
+
+	err := query.Find(&datas).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+// GetWebhookDeliveryTotal counts the deliveries based on the constraint
+func GetWebhookDeliveryTotal(maps interface{}) (int, error) {
+	var count int64
+
+	if err := db.Model(&WebhookDelivery{}).Where(maps).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetWebhookDelivery Get a delivery by id
+func GetWebhookDelivery(id int) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	err := db.Model(&WebhookDelivery{}).Where("id = ?", id).First(&d).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// AddWebhookDelivery add a pending delivery and returns its id
+func AddWebhookDelivery(data map[string]interface{}) (int, error) {
+	now := int(time.Now().Unix())
+	delivery := WebhookDelivery{
+		WebhookId:  data["webhook_id"].(int),
+		Event:      data["event"].(string),
+		EventId:    data["event_id"].(string),
+		Payload:    data["payload"].(string),
+		Status:     DeliveryPending,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := db.Create(&delivery).Error; err != nil {
+		return 0, err
+	}
+
+	return delivery.ID, nil
+}
+
+// EditWebhookDelivery record the result of a delivery attempt
+func EditWebhookDelivery(id int, data interface{}) error {
+	if err := db.Model(&WebhookDelivery{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
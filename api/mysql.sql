@@ -7,10 +7,24 @@ CREATE TABLE IF NOT EXISTS `jobs` (
   `name` varchar(128) NOT NULL DEFAULT '' COMMENT '职位名称',
   `demand` text COMMENT '职位要求(详细描述对应聘者的技能、经验等要求)',
   `desc` text COMMENT '职位描述(详细描述职位的工作内容、职责等)',
+  `prompt_template` varchar(64) NOT NULL DEFAULT '' COMMENT 'AI分析使用的提示词模板, 为空时使用default',
+  `stages` varchar(512) NOT NULL DEFAULT '' COMMENT '逗号分隔的招聘流程阶段, 为空时使用默认流程',
+  `criteria` varchar(512) NOT NULL DEFAULT '' COMMENT '逗号分隔的面试评价维度, 为空时使用默认维度',
+  `status` varchar(16) NOT NULL DEFAULT 'open' COMMENT '状态: draft, open, on_hold, closed, filled',
+  `headcount` int unsigned NOT NULL DEFAULT '1' COMMENT '招聘人数',
+  `department` varchar(64) NOT NULL DEFAULT '' COMMENT '所属部门',
+  `location` varchar(128) NOT NULL DEFAULT '' COMMENT '工作地点',
+  `salary_min` int unsigned NOT NULL DEFAULT '0' COMMENT '月薪下限, 0表示未填写',
+  `salary_max` int unsigned NOT NULL DEFAULT '0' COMMENT '月薪上限, 0表示未填写',
+  `employment_type` varchar(16) NOT NULL DEFAULT 'full_time' COMMENT '用工类型: full_time, part_time, contract, internship',
+  `hiring_manager_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '用人经理',
+  `close_time` int unsigned NOT NULL DEFAULT '0' COMMENT '计划截止招聘时间, 0表示不限',
   `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '创建人',
   `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
   `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
-  PRIMARY KEY (`id`)
+  PRIMARY KEY (`id`),
+  KEY `status` (`status`),
+  KEY `department` (`department`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='招聘职位表';
 
 CREATE TABLE IF NOT EXISTS `resumes` (
@@ -19,11 +33,18 @@ CREATE TABLE IF NOT EXISTS `resumes` (
   `url` varchar(1024) NOT NULL DEFAULT '' COMMENT '上传Blob Storage Service后简历的URL地址'
   `filename` varchar(128) NOT NULL DEFAULT '' COMMENT '上传简历的文件名',
   `size` int unsigned NOT NULL DEFAULT '0' COMMENT '简历文件大小',
+  `candidate_id` int unsigned NOT NULL DEFAULT '0' COMMENT '归并到的候选人ID',
+  `content_hash` char(64) NOT NULL DEFAULT '' COMMENT '文件内容SHA-256',
+  `minhash` varchar(512) NOT NULL DEFAULT '' COMMENT '简历文本MinHash签名(十六进制)',
+  `duplicate_of` int unsigned NOT NULL DEFAULT '0' COMMENT '可能重复的最早一份简历ID',
+  `duplicate_reason` varchar(16) NOT NULL DEFAULT '' COMMENT '重复判定依据 content内容相同 identity手机号或邮箱相同 similar文本相似',
   `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '上传人',
   `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间即上传时间',
   `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
   PRIMARY KEY (`id`),
-  KEY `job_id` (`job_id`)
+  KEY `job_id` (`job_id`),
+  KEY `candidate_id` (`candidate_id`),
+  KEY `content_hash` (`content_hash`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='上传的简历表';
 
 CREATE TABLE IF NOT EXISTS `resume_analyze_records` (
@@ -75,3 +96,245 @@ CREATE TABLE IF NOT EXISTS `role_perms` (
   PRIMARY KEY (`id`),
   KEY `role_id` (`role_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='角色权限表';
+
+CREATE TABLE IF NOT EXISTS `resume_analyses` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `resume_id` int unsigned NOT NULL DEFAULT '0' COMMENT '关联的简历ID',
+  `job_id` int unsigned NOT NULL DEFAULT '0' COMMENT '分析时关联的招聘职位ID',
+  `deployment` varchar(128) NOT NULL DEFAULT '' COMMENT '模型部署名称',
+  `prompt_version` varchar(64) NOT NULL DEFAULT '' COMMENT '提示词版本',
+  `match_score` int NOT NULL DEFAULT '0' COMMENT '岗位匹配度评分',
+  `estimated_yoe` int NOT NULL DEFAULT '0' COMMENT '预估工作年限',
+  `skills` varchar(2048) NOT NULL DEFAULT '' COMMENT '技术技能(小写, 逗号分隔)',
+  `personal_info` text COMMENT '提取的候选人个人信息(JSON)',
+  `result` mediumtext COMMENT 'AI分析原始结果(JSON)',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '发起分析的用户',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `resume_job_model` (`resume_id`,`job_id`,`deployment`,`prompt_version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='简历AI分析结果表';
+
+CREATE TABLE IF NOT EXISTS `analysis_batches` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `job_id` int unsigned NOT NULL DEFAULT '0' COMMENT '关联的招聘职位ID',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT '批次状态 pending等待中 running执行中 finished已完成',
+  `total` int unsigned NOT NULL DEFAULT '0' COMMENT '简历总数',
+  `succeeded` int unsigned NOT NULL DEFAULT '0' COMMENT '分析成功数(完成后写入)',
+  `failed` int unsigned NOT NULL DEFAULT '0' COMMENT '分析失败数(完成后写入)',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '创建人',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `finish_time` int unsigned NOT NULL DEFAULT '0' COMMENT '完成时间',
+  PRIMARY KEY (`id`),
+  KEY `job_id` (`job_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='简历批量分析批次表';
+
+CREATE TABLE IF NOT EXISTS `ai_usages` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `day` int unsigned NOT NULL DEFAULT '0' COMMENT '调用日期, 如20260101',
+  `uid` int unsigned NOT NULL DEFAULT '0' COMMENT '发起分析的用户ID',
+  `job_id` int unsigned NOT NULL DEFAULT '0' COMMENT '关联的招聘职位ID',
+  `resume_id` int unsigned NOT NULL DEFAULT '0' COMMENT '关联的简历ID',
+  `deployment` varchar(128) NOT NULL DEFAULT '' COMMENT '模型部署名称',
+  `prompt_tokens` int unsigned NOT NULL DEFAULT '0' COMMENT '输入token数',
+  `completion_tokens` int unsigned NOT NULL DEFAULT '0' COMMENT '输出token数',
+  `total_tokens` int unsigned NOT NULL DEFAULT '0' COMMENT '总token数',
+  `calls` int unsigned NOT NULL DEFAULT '0' COMMENT '模型请求次数(含重试与修复)',
+  `retries` int unsigned NOT NULL DEFAULT '0' COMMENT '重试次数',
+  `latency_ms` int unsigned NOT NULL DEFAULT '0' COMMENT '耗时(毫秒)',
+  `success` tinyint unsigned NOT NULL DEFAULT '0' COMMENT '是否分析成功',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `day` (`day`),
+  KEY `uid_day` (`uid`,`day`),
+  KEY `job_day` (`job_id`,`day`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='AI分析token消耗表';
+
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `action` varchar(64) NOT NULL DEFAULT '' COMMENT '审计动作, 如redact_pii',
+  `target_type` varchar(32) NOT NULL DEFAULT '' COMMENT '对象类型, 如resume',
+  `target_id` int unsigned NOT NULL DEFAULT '0' COMMENT '对象ID',
+  `uid` int unsigned NOT NULL DEFAULT '0' COMMENT '操作用户ID',
+  `detail` text COMMENT '审计详情(JSON), 不含个人信息原文',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `target` (`target_type`,`target_id`),
+  KEY `action_time` (`action`,`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='合规审计日志表';
+
+CREATE TABLE IF NOT EXISTS `candidates` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(64) NOT NULL DEFAULT '' COMMENT '姓名',
+  `phone` varchar(32) NOT NULL DEFAULT '' COMMENT '手机号',
+  `phone_key` varchar(32) NOT NULL DEFAULT '' COMMENT '规范化后的手机号, 用于查重',
+  `email` varchar(128) NOT NULL DEFAULT '' COMMENT '邮箱(小写)',
+  `location` varchar(128) NOT NULL DEFAULT '' COMMENT '所在地',
+  `remark` varchar(1024) NOT NULL DEFAULT '' COMMENT '备注',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '创建人',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  KEY `phone_key` (`phone_key`),
+  KEY `email` (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='候选人表';
+
+CREATE TABLE IF NOT EXISTS `applications` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `candidate_id` int unsigned NOT NULL DEFAULT '0' COMMENT '候选人ID',
+  `job_id` int unsigned NOT NULL DEFAULT '0' COMMENT '招聘需求ID',
+  `resume_id` int unsigned NOT NULL DEFAULT '0' COMMENT '投递的简历ID',
+  `status` varchar(16) NOT NULL DEFAULT 'active' COMMENT '状态: active/withdrawn/archived',
+  `stage` varchar(32) NOT NULL DEFAULT 'screening' COMMENT '招聘流程阶段',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '创建人',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `candidate_job` (`candidate_id`,`job_id`),
+  KEY `job_stage` (`job_id`,`stage`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='应聘记录表';
+
+CREATE TABLE IF NOT EXISTS `application_stage_logs` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `application_id` int unsigned NOT NULL DEFAULT '0' COMMENT '应聘记录ID',
+  `from_stage` varchar(32) NOT NULL DEFAULT '' COMMENT '变更前阶段',
+  `to_stage` varchar(32) NOT NULL DEFAULT '' COMMENT '变更后阶段',
+  `reason` varchar(512) NOT NULL DEFAULT '' COMMENT '变更原因',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '操作人',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '变更时间',
+  PRIMARY KEY (`id`),
+  KEY `application_id` (`application_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='应聘阶段变更记录表';
+
+CREATE TABLE IF NOT EXISTS `interviews` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `application_id` int unsigned NOT NULL DEFAULT '0' COMMENT '应聘记录ID',
+  `round` int unsigned NOT NULL DEFAULT '1' COMMENT '面试轮次',
+  `start_time` int unsigned NOT NULL DEFAULT '0' COMMENT '开始时间',
+  `end_time` int unsigned NOT NULL DEFAULT '0' COMMENT '结束时间',
+  `location` varchar(255) NOT NULL DEFAULT '' COMMENT '面试地点',
+  `meeting_url` varchar(1024) NOT NULL DEFAULT '' COMMENT '线上会议链接',
+  `status` varchar(16) NOT NULL DEFAULT 'scheduled' COMMENT '状态: scheduled/cancelled/completed',
+  `sequence` int unsigned NOT NULL DEFAULT '0' COMMENT '日历邀请版本号, 改期或取消后递增',
+  `remark` varchar(1024) NOT NULL DEFAULT '' COMMENT '备注',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '创建人',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  KEY `application_id` (`application_id`),
+  KEY `start_time` (`start_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='面试表';
+
+CREATE TABLE IF NOT EXISTS `interview_interviewers` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `interview_id` int unsigned NOT NULL DEFAULT '0' COMMENT '面试ID',
+  `uid` int unsigned NOT NULL DEFAULT '0' COMMENT '面试官用户ID',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `interview_uid` (`interview_id`,`uid`),
+  KEY `uid` (`uid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='面试官表';
+
+CREATE TABLE IF NOT EXISTS `scorecards` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `interview_id` int unsigned NOT NULL DEFAULT '0' COMMENT '面试ID',
+  `application_id` int unsigned NOT NULL DEFAULT '0' COMMENT '应聘记录ID',
+  `uid` int unsigned NOT NULL DEFAULT '0' COMMENT '面试官用户ID',
+  `ratings` text COMMENT '各评价维度的评分与评语(JSON)',
+  `recommendation` varchar(16) NOT NULL DEFAULT '' COMMENT '录用建议: hire/no_hire',
+  `comment` text COMMENT '总体评语',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `interview_uid` (`interview_id`,`uid`),
+  KEY `application_id` (`application_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='面试评价表';
+
+CREATE TABLE IF NOT EXISTS `offers` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `application_id` int unsigned NOT NULL DEFAULT '0' COMMENT '应聘记录ID',
+  `salary` int unsigned NOT NULL DEFAULT '0' COMMENT '税前月薪(元)',
+  `level` varchar(32) NOT NULL DEFAULT '' COMMENT '职级',
+  `start_date` varchar(10) NOT NULL DEFAULT '' COMMENT '入职日期, 格式 2006-01-02',
+  `expire_time` int unsigned NOT NULL DEFAULT '0' COMMENT '候选人确认截止时间',
+  `template` varchar(64) NOT NULL DEFAULT '' COMMENT 'offer函模板名称, 为空时使用 default',
+  `status` varchar(16) NOT NULL DEFAULT 'draft' COMMENT '状态: draft/pending_approval/sent/accepted/declined/expired/withdrawn',
+  `current_step` int unsigned NOT NULL DEFAULT '0' COMMENT '当前待审批的审批人序号, 从1开始',
+  `remark` varchar(1024) NOT NULL DEFAULT '' COMMENT '备注',
+  `sent_time` int unsigned NOT NULL DEFAULT '0' COMMENT '发出时间',
+  `respond_time` int unsigned NOT NULL DEFAULT '0' COMMENT '候选人答复时间',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '创建人',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  KEY `application_id` (`application_id`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='offer表';
+
+CREATE TABLE IF NOT EXISTS `offer_approvals` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `offer_id` int unsigned NOT NULL DEFAULT '0' COMMENT 'offer ID',
+  `step` int unsigned NOT NULL DEFAULT '0' COMMENT '审批顺序, 从1开始',
+  `uid` int unsigned NOT NULL DEFAULT '0' COMMENT '审批人用户ID',
+  `status` varchar(16) NOT NULL DEFAULT 'pending' COMMENT '审批状态: pending/approved/rejected',
+  `comment` varchar(1024) NOT NULL DEFAULT '' COMMENT '审批意见',
+  `act_time` int unsigned NOT NULL DEFAULT '0' COMMENT '审批时间',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `offer_step` (`offer_id`,`step`),
+  KEY `uid` (`uid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='offer审批表';
+
+CREATE TABLE IF NOT EXISTS `notification_preferences` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `uid` int unsigned NOT NULL DEFAULT '0' COMMENT '用户ID',
+  `event` varchar(32) NOT NULL DEFAULT '' COMMENT '通知事件: resume_uploaded/analysis_done/stage_changed/interview_scheduled/offer_approval',
+  `enabled` tinyint(1) NOT NULL DEFAULT '1' COMMENT '是否接收邮件通知',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uid_event` (`uid`,`event`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='通知偏好表, 没有记录的事件默认接收';
+
+CREATE TABLE IF NOT EXISTS `webhooks` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `job_id` int unsigned NOT NULL DEFAULT '0' COMMENT '招聘需求ID',
+  `name` varchar(64) NOT NULL DEFAULT '' COMMENT '名称',
+  `kind` varchar(16) NOT NULL DEFAULT 'generic' COMMENT '类型: teams/generic',
+  `url` varchar(1024) NOT NULL DEFAULT '' COMMENT '接收地址',
+  `secret` varchar(128) NOT NULL DEFAULT '' COMMENT 'generic 请求的 HMAC-SHA256 签名密钥',
+  `events` varchar(255) NOT NULL DEFAULT '' COMMENT '订阅的事件, 逗号分隔: high_match_score/new_candidate/stage_changed',
+  `min_score` int unsigned NOT NULL DEFAULT '80' COMMENT 'high_match_score 事件的匹配度阈值',
+  `enabled` tinyint(1) NOT NULL DEFAULT '1' COMMENT '是否启用',
+  `create_uid` int unsigned NOT NULL DEFAULT '0' COMMENT '创建人',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '修改时间',
+  PRIMARY KEY (`id`),
+  KEY `job_id` (`job_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook订阅表';
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `webhook_id` int unsigned NOT NULL DEFAULT '0' COMMENT 'webhook ID',
+  `event` varchar(32) NOT NULL DEFAULT '' COMMENT '事件',
+  `event_id` varchar(32) NOT NULL DEFAULT '' COMMENT '事件ID, 同一事件投递给多个订阅时相同',
+  `payload` mediumtext COMMENT '请求体',
+  `status` varchar(16) NOT NULL DEFAULT 'pending' COMMENT '状态: pending/retrying/success/failed',
+  `attempts` int unsigned NOT NULL DEFAULT '0' COMMENT '已尝试次数',
+  `response_code` int unsigned NOT NULL DEFAULT '0' COMMENT '最近一次响应的HTTP状态码',
+  `response_body` varchar(1024) NOT NULL DEFAULT '' COMMENT '最近一次响应内容, 最多1KB',
+  `error` varchar(1024) NOT NULL DEFAULT '' COMMENT '最近一次失败原因',
+  `next_retry_time` int unsigned NOT NULL DEFAULT '0' COMMENT '下次重试时间',
+  `create_time` int unsigned NOT NULL DEFAULT '0' COMMENT '创建时间',
+  `update_time` int unsigned NOT NULL DEFAULT '0' COMMENT '最近一次尝试时间',
+  PRIMARY KEY (`id`),
+  KEY `webhook_id` (`webhook_id`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook投递记录表';
+
+CREATE TABLE IF NOT EXISTS `resume_minhash_bands` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `resume_id` int unsigned NOT NULL DEFAULT '0' COMMENT '简历ID',
+  `band` bigint NOT NULL DEFAULT '0' COMMENT 'MinHash签名LSH分段哈希',
+  PRIMARY KEY (`id`),
+  KEY `band` (`band`),
+  KEY `resume_id` (`resume_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='简历MinHash分段表, 用于近似重复检测';
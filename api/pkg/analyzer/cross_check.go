@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"hr-api/pkg/client"
+	"hr-api/pkg/parser"
+)
+
+// yoeTolerance 模型与规则估算的工作年限相差超过该值时记录差异
+const yoeTolerance = 2
+
+// crossCheck 用规则提取结果校对模型输出：模型遗漏的字段由规则补全，
+// 模型给出的邮箱、手机号在原文中找不到时视为编造，改用规则结果，所有差异记录到 Metadata.CrossCheck
+func crossCheck(analysis, rules *client.ResumeAnalysis, resumeText string) {
+	info, ruleInfo := &analysis.PersonalInfo, rules.PersonalInfo
+	var diffs []client.FieldError
+
+	mismatch := func(field, got, want string) {
+		diffs = append(diffs, client.FieldError{
+			Field:  field,
+			Reason: fmt.Sprintf("模型结果为%q，规则提取为%q", got, want),
+		})
+	}
+
+	if ruleInfo.Name != "" && info.Name != ruleInfo.Name {
+		if info.Name == "" {
+			info.Name = ruleInfo.Name
+		} else {
+			mismatch("personal_info.name", info.Name, ruleInfo.Name)
+		}
+	}
+
+	if ruleInfo.Email != "" && !strings.EqualFold(info.Email, ruleInfo.Email) {
+		if info.Email != "" {
+			mismatch("personal_info.email", info.Email, ruleInfo.Email)
+		}
+		if info.Email == "" || !strings.Contains(strings.ToLower(resumeText), strings.ToLower(info.Email)) {
+			info.Email = ruleInfo.Email
+		}
+	}
+
+	if ruleInfo.Phone != "" && parser.ExtractPhone(info.Phone) != ruleInfo.Phone {
+		if info.Phone != "" {
+			mismatch("personal_info.phone", info.Phone, ruleInfo.Phone)
+		}
+		if info.Phone == "" || !strings.Contains(digitsOnly(resumeText), parser.ExtractPhone(info.Phone)) {
+			info.Phone = ruleInfo.Phone
+		}
+	}
+
+	if info.Age == 0 {
+		info.Age = ruleInfo.Age
+	}
+	if info.Gender == "" {
+		info.Gender = ruleInfo.Gender
+	}
+
+	if len(analysis.WorkExperience) == 0 && len(rules.WorkExperience) > 0 {
+		analysis.WorkExperience = rules.WorkExperience
+	}
+	if len(analysis.Education) == 0 && len(rules.Education) > 0 {
+		analysis.Education = rules.Education
+	}
+
+	if yoe := rules.Metadata.EstimatedYOE; yoe > 0 {
+		if diff := analysis.Metadata.EstimatedYOE - yoe; diff > yoeTolerance || diff < -yoeTolerance {
+			diffs = append(diffs, client.FieldError{
+				Field:  "metadata.estimated_yoe",
+				Reason: fmt.Sprintf("模型结果为%d，规则提取为%d", analysis.Metadata.EstimatedYOE, yoe),
+			})
+		}
+	}
+
+	analysis.Metadata.CrossCheck = diffs
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -11,23 +12,48 @@ import (
 
 	"hr-api/pkg/client"
 	"hr-api/pkg/parser"
+	"hr-api/pkg/prompt"
+	"hr-api/pkg/redact"
 )
 
 // ResumeAnalyzer 简历分析器主类
 type ResumeAnalyzer struct {
-	aiClient *client.AzureOpenAIClient
+	aiClient client.ResumeLLM
 	config   *AnalyzerConfig
+	template *prompt.Template
+	redactor *redact.Redactor
 }
 
 type AnalyzerConfig struct {
 	OutputFormat string
 	OutputDir    string
 	SaveToFile   bool
+	// PromptTemplate 提示词模板名称，为空时使用 default
+	PromptTemplate string
+	// FallbackToRules 模型分析失败时返回规则提取的部分结果而不是错误
+	FallbackToRules bool
+	// RedactCategories 发送给模型前脱敏的个人信息类别，为空时不脱敏
+	RedactCategories []string
 }
 
 func NewResumeAnalyzer(analyzerConfig *AnalyzerConfig) (*ResumeAnalyzer, error) {
-	// 创建AI客户端
-	aiClient, err := client.NewAzureOpenAIClient()
+	// 根据配置创建AI客户端
+	aiClient, err := client.NewResumeLLM()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResumeAnalyzerWithLLM(aiClient, analyzerConfig)
+}
+
+// NewResumeAnalyzerWithLLM 使用指定的大模型客户端创建分析器
+func NewResumeAnalyzerWithLLM(aiClient client.ResumeLLM, analyzerConfig *AnalyzerConfig) (*ResumeAnalyzer, error) {
+	template, err := prompt.Load(analyzerConfig.PromptTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := redact.New(analyzerConfig.RedactCategories)
 	if err != nil {
 		return nil, err
 	}
@@ -35,42 +61,136 @@ func NewResumeAnalyzer(analyzerConfig *AnalyzerConfig) (*ResumeAnalyzer, error)
 	return &ResumeAnalyzer{
 		aiClient: aiClient,
 		config:   analyzerConfig,
+		template: template,
+		redactor: redactor,
 	}, nil
 }
 
-// AnalyzeFile 分析单个简历文件
+// Deployment 返回分析所用的模型部署名称
+func (ra *ResumeAnalyzer) Deployment() string {
+	return ra.aiClient.Deployment()
+}
+
+// PromptVersion 返回分析所用的提示词版本
+func (ra *ResumeAnalyzer) PromptVersion() string {
+	return ra.template.Version
+}
+
+// AnalyzeFile 分析单个简历文件，返回的 usage 为模型消耗的 token，未调用模型时为 nil
 func (ra *ResumeAnalyzer) AnalyzeFile(ctx context.Context, jobTitle string,
 	jobRequirements string,
-	jobDescription string, filePath string) (*client.ResumeAnalysis, error) {
-	// 1. 解析文件内容
-	log.Printf("正在解析文件: %s", filePath)
+	jobDescription string, filePath string) (*client.ResumeAnalysis, *client.Usage, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析简历文件失败: %v", err)
+	}
+	defer f.Close()
 
-	parserFactory := parser.NewUnifiedResumeParser()
-	resumeText, err := parserFactory.Parse(filePath)
+	return ra.AnalyzeReader(ctx, jobTitle, jobRequirements, jobDescription, f, filePath, "")
+}
+
+// AnalyzeReader 分析从流中读取的简历（如 Blob 下载流），filename 与 contentType 用于辅助识别格式
+func (ra *ResumeAnalyzer) AnalyzeReader(ctx context.Context, jobTitle string,
+	jobRequirements string,
+	jobDescription string, r io.Reader, filename string, contentType string) (*client.ResumeAnalysis, *client.Usage, error) {
+	// 1. 解析文件内容
+	resumeText, err := parseReader(ctx, r, filename, contentType)
 	if err != nil {
-		return nil, fmt.Errorf("解析简历文件失败: %v", err)
+		return nil, nil, err
 	}
+	rules := parser.ExtractFields(resumeText)
 
-	log.Printf("成功提取文本，长度: %d 字符", len(resumeText))
+	// 个人信息替换为占位符后再发送给模型，对应关系只保留在本地。
+	// 只有“姓名：”标签给出的姓名可靠，猜测的姓名可能是职位等普通词，不做替换
+	labeledName := parser.ExtractLabeledName(resumeText)
+	llmText, mapping := ra.redactor.Redact(resumeText, labeledName)
 
 	// 2. 使用AI分析内容
-	log.Printf("正在使用Azure OpenAI分析简历...")
+	log.Printf("正在使用 %s 分析简历...", ra.aiClient.Deployment())
+
+	job := client.JobInfo{
+		Title:        jobTitle,
+		Requirements: jobRequirements,
+		Description:  jobDescription,
+	}
+	job.Prompt, err = ra.template.Render(job, llmText)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	analysis, err := ra.aiClient.AnalyzeResume(ctx, jobTitle, jobRequirements, jobDescription, resumeText)
+	analysis, usage, err := ra.aiClient.AnalyzeResume(ctx, job, llmText)
 	if err != nil {
-		return nil, fmt.Errorf("AI分析失败: %v", err)
+		if ra.config.FallbackToRules {
+			log.Printf("AI分析失败，使用规则提取结果: %v", err)
+			client.Report(ctx, client.StageFallback, err.Error(), nil)
+			rules.Metadata.Redacted = mapping.Counts()
+			rules.ResumeText = resumeText
+			return rules, usage, nil
+		}
+		return nil, usage, fmt.Errorf("AI分析失败: %v", err)
+	}
+	analysis.Metadata.Source = client.SourceLLM
+	analysis.Metadata.Redacted = mapping.Counts()
+	ra.rehydrate(analysis, rules, labeledName, mapping)
+	crossCheck(analysis, rules, resumeText)
+	analysis.ResumeText = resumeText
+	if len(analysis.Metadata.CrossCheck) > 0 {
+		log.Printf("模型结果与规则提取不一致: %v", analysis.Metadata.CrossCheck)
 	}
 
 	// 3. 生成输出
 	if ra.config.SaveToFile {
-		if err := ra.saveAnalysis(filePath, analysis); err != nil {
+		if err := ra.saveAnalysis(filename, analysis); err != nil {
 			log.Printf("保存分析结果失败: %v", err)
 		}
 	}
 
+	return analysis, usage, nil
+}
+
+// rehydrate 还原模型输出中的占位符，被脱敏的联系方式以本地提取结果为准；
+// 姓名只有来自“姓名：”标签（labeledName）时才覆盖模型结果
+func (ra *ResumeAnalyzer) rehydrate(analysis, rules *client.ResumeAnalysis, labeledName string, mapping *redact.Mapping) {
+	mapping.RestoreAll(analysis)
+
+	info, local := &analysis.PersonalInfo, rules.PersonalInfo
+	if ra.redactor.Enabled(redact.CategoryName) && labeledName != "" {
+		info.Name = labeledName
+	}
+	if ra.redactor.Enabled(redact.CategoryPhone) && local.Phone != "" {
+		info.Phone = local.Phone
+	}
+	if ra.redactor.Enabled(redact.CategoryEmail) && local.Email != "" {
+		info.Email = local.Email
+	}
+}
+
+// ExtractReader 不调用模型，仅用规则从简历中提取基础字段，用于模型不可用或预算用完时
+func ExtractReader(ctx context.Context, r io.Reader, filename string, contentType string) (*client.ResumeAnalysis, error) {
+	resumeText, err := parseReader(ctx, r, filename, contentType)
+	if err != nil {
+		return nil, err
+	}
+	analysis := parser.ExtractFields(resumeText)
+	analysis.ResumeText = resumeText
 	return analysis, nil
 }
 
+func parseReader(ctx context.Context, r io.Reader, filename string, contentType string) (string, error) {
+	log.Printf("正在解析文件: %s", filename)
+	client.Report(ctx, client.StageParse, "", nil)
+
+	parserFactory := parser.NewUnifiedResumeParser()
+	resumeText, err := parserFactory.ParseReader(ctx, r, filename, contentType)
+	if err != nil {
+		return "", fmt.Errorf("解析简历文件失败: %v", err)
+	}
+
+	log.Printf("成功提取文本，长度: %d 字符", len(resumeText))
+	client.Report(ctx, client.StageParsed, "", map[string]interface{}{"length": len([]rune(resumeText))})
+	return resumeText, nil
+}
+
 // saveAnalysis 保存分析结果
 func (ra *ResumeAnalyzer) saveAnalysis(originalPath string, analysis *client.ResumeAnalysis) error {
 	// 创建输出目录
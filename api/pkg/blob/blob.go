@@ -0,0 +1,77 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+
+	"hr-api/pkg/keyvault"
+)
+
+// NewClient 使用 Key Vault 中的 BLOB-* 配置创建 Blob 客户端
+func NewClient() (*azblob.Client, error) {
+	conf, err := keyvault.GetKeyVaultConf()
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(conf.BlobAccountName, conf.BlobAccessKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob credential: %v", err)
+	}
+
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", conf.BlobAccountName)
+	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob client: %v", err)
+	}
+
+	return client, nil
+}
+
+// Object 正在下载的 Blob，调用方读取完毕后需关闭 Body
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// Open 根据上传接口返回的 Blob URL 打开下载流，内容边下载边读取，不落盘
+func Open(ctx context.Context, rawURL string) (*Object, error) {
+	parts, err := azblob.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob url '%s': %v", rawURL, err)
+	}
+
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.DownloadStream(ctx, parts.ContainerName, parts.BlobName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download blob '%s': %v", parts.BlobName, err)
+	}
+
+	obj := &Object{Body: resp.Body}
+	if resp.ContentType != nil {
+		obj.ContentType = *resp.ContentType
+	}
+	if resp.ContentLength != nil {
+		obj.Size = *resp.ContentLength
+	}
+	return obj, nil
+}
+
+// Download 根据上传接口返回的 Blob URL 下载文件内容
+func Download(ctx context.Context, rawURL string) ([]byte, error) {
+	obj, err := Open(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Body.Close()
+
+	return io.ReadAll(obj.Body)
+}
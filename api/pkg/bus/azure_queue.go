@@ -0,0 +1,151 @@
+package bus
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+
+	"hr-api/pkg/setting"
+)
+
+// AzureQueue 基于 Azure Service Bus 的队列实现
+type AzureQueue struct {
+	name     string
+	sender   *Sender
+	receiver *Receiver
+}
+
+func NewAzureQueue(client *Client, name string) (*AzureQueue, error) {
+	sender, err := client.NewQueueSender(name)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver, err := client.NewQueueReceiver(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzureQueue{
+		name:     name,
+		sender:   sender,
+		receiver: receiver,
+	}, nil
+}
+
+func (q *AzureQueue) Name() string {
+	return q.name
+}
+
+func (q *AzureQueue) Send(ctx context.Context, body []byte) error {
+	return q.sender.Send(ctx, body)
+}
+
+func (q *AzureQueue) SendScheduled(ctx context.Context, body []byte, at time.Time) error {
+	return q.sender.SendScheduled(ctx, body, at)
+}
+
+// Receive 逐条处理拉取到的一批消息。处理期间定期续期尚未完成的消息锁，
+// 否则批量中靠后的消息等待时锁已过期，会被重复投递
+func (q *AzureQueue) Receive(ctx context.Context, handler func(*Message) error) error {
+	batchSize := setting.QueueSetting.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	msgs, err := q.receiver.raw.ReceiveMessages(ctx, batchSize, nil)
+	if err != nil {
+		return err
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	var mu sync.Mutex
+	pending := make(map[*azservicebus.ReceivedMessage]bool, len(msgs))
+	for _, m := range msgs {
+		pending[m] = true
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go q.renewLocks(ctx, &mu, pending, lockRenewInterval(msgs[0]), stop)
+
+	// worker 停止时 ctx 已取消，处理结果仍需写回
+	settleCtx := context.WithoutCancel(ctx)
+	for _, m := range msgs {
+		msg := &Message{Body: m.Body, DeliveryCount: int(m.DeliveryCount)}
+		handleErr := handler(msg)
+
+		mu.Lock()
+		delete(pending, m)
+		mu.Unlock()
+
+		if err := q.settle(settleCtx, m, msg, handleErr); err != nil {
+			log.Printf("[bus] settle message %s of %s failed: %v", m.MessageID, q.name, err)
+		}
+	}
+
+	return nil
+}
+
+// settle 处理成功则完成消息，失败则放回重试或转入死信队列
+func (q *AzureQueue) settle(ctx context.Context, m *azservicebus.ReceivedMessage, msg *Message, handleErr error) error {
+	if handleErr == nil {
+		return q.receiver.raw.CompleteMessage(ctx, m, nil)
+	}
+	if ShouldDeadLetter(handleErr, msg.DeliveryCount) {
+		log.Printf("[bus] message %s moved to %s: %v", m.MessageID, DeadLetterQueue(q.name), handleErr)
+		return q.receiver.raw.DeadLetterMessage(ctx, m, &azservicebus.DeadLetterOptions{
+			Reason:           to.Ptr("ProcessingFailed"),
+			ErrorDescription: to.Ptr(handleErr.Error()),
+		})
+	}
+	return q.receiver.raw.AbandonMessage(ctx, m, nil)
+}
+
+// renewLocks 每隔 interval 续期 pending 中的消息锁，直到 stop 关闭
+func (q *AzureQueue) renewLocks(ctx context.Context, mu *sync.Mutex, pending map[*azservicebus.ReceivedMessage]bool, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		mu.Lock()
+		for m := range pending {
+			if err := q.receiver.raw.RenewMessageLock(ctx, m, nil); err != nil {
+				log.Printf("[bus] renew lock of message %s failed: %v", m.MessageID, err)
+			}
+		}
+		mu.Unlock()
+	}
+}
+
+// lockRenewInterval 在锁剩余时间过半时续期
+func lockRenewInterval(m *azservicebus.ReceivedMessage) time.Duration {
+	interval := 30 * time.Second
+	if m.LockedUntil != nil {
+		interval = time.Until(*m.LockedUntil) / 2
+	}
+	if interval < 5*time.Second {
+		interval = 5 * time.Second
+	}
+	return interval
+}
+
+func (q *AzureQueue) Close(ctx context.Context) error {
+	if err := q.receiver.raw.Close(ctx); err != nil {
+		return err
+	}
+	return q.sender.raw.Close(ctx)
+}
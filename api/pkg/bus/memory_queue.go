@@ -0,0 +1,85 @@
+package bus
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// MemoryQueue 进程内队列实现，仅在 API 与 worker 运行于同一进程时使用
+type MemoryQueue struct {
+	name     string
+	messages chan *Message
+
+	mu   sync.Mutex
+	dead []*Message
+}
+
+func NewMemoryQueue(name string) *MemoryQueue {
+	return &MemoryQueue{
+		name:     name,
+		messages: make(chan *Message, 1024),
+	}
+}
+
+func (q *MemoryQueue) Name() string {
+	return q.name
+}
+
+func (q *MemoryQueue) Send(ctx context.Context, body []byte) error {
+	return q.enqueue(ctx, &Message{Body: body})
+}
+
+func (q *MemoryQueue) SendScheduled(ctx context.Context, body []byte, at time.Time) error {
+	q.schedule(&Message{Body: body}, at)
+	return nil
+}
+
+func (q *MemoryQueue) Receive(ctx context.Context, handler func(*Message) error) error {
+	select {
+	case msg := <-q.messages:
+		msg.DeliveryCount++
+		if err := handler(msg); err != nil {
+			if ShouldDeadLetter(err, msg.DeliveryCount) {
+				log.Printf("[bus] message moved to %s: %v", DeadLetterQueue(q.name), err)
+				q.mu.Lock()
+				q.dead = append(q.dead, msg)
+				q.mu.Unlock()
+				return nil
+			}
+			// 与 redis 驱动一样按投递次数退避后重新入队，避免失败的消息被立即反复处理
+			q.schedule(msg, time.Now().Add(retryDelay(msg.DeliveryCount)))
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DeadLetters 返回已转入死信队列的消息
+func (q *MemoryQueue) DeadLetters() []*Message {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]*Message(nil), q.dead...)
+}
+
+func (q *MemoryQueue) Close(ctx context.Context) error {
+	return nil
+}
+
+// schedule 到 at 时把消息放回队列，重试的消息保留已投递次数
+func (q *MemoryQueue) schedule(msg *Message, at time.Time) {
+	time.AfterFunc(time.Until(at), func() {
+		q.enqueue(context.Background(), msg)
+	})
+}
+
+func (q *MemoryQueue) enqueue(ctx context.Context, msg *Message) error {
+	select {
+	case q.messages <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
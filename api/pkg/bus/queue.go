@@ -0,0 +1,91 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-api/pkg/setting"
+)
+
+const (
+	DriverAzure  = "azure"
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+)
+
+// ErrPoison 处理函数返回该错误（可用 %w 包装）时消息直接转入死信队列，不再重试
+var ErrPoison = errors.New("poison message")
+
+// Message 从队列中接收到的消息
+type Message struct {
+	Body []byte
+	// DeliveryCount 消息已被投递的次数，首次投递为1
+	DeliveryCount int
+}
+
+// Queue 消息队列抽象，Azure Service Bus 之外还提供 Redis 与进程内实现便于本地运行
+type Queue interface {
+	Name() string
+	Send(ctx context.Context, body []byte) error
+	SendScheduled(ctx context.Context, body []byte, at time.Time) error
+	// Receive 拉取一批消息交给 handler 处理：成功则完成，失败则放回重试，
+	// 返回 ErrPoison 或超过最大投递次数时转入 DeadLetterQueue
+	Receive(ctx context.Context, handler func(*Message) error) error
+	Close(ctx context.Context) error
+}
+
+// NewQueue 根据 [queue] 配置的 Driver 创建队列
+func NewQueue(name string) (Queue, error) {
+	conf := setting.QueueSetting
+	switch strings.ToLower(conf.Driver) {
+	case "", DriverAzure:
+		var (
+			client *Client
+			err    error
+		)
+		if conf.ConnectionString != "" {
+			client, err = NewFromConnStr(conf.ConnectionString)
+		} else {
+			client, err = NewBusClient(conf.Namespace)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return NewAzureQueue(client, name)
+	case DriverRedis:
+		return NewRedisQueue(name)
+	case DriverMemory:
+		return NewMemoryQueue(name), nil
+	default:
+		return nil, fmt.Errorf("unsupported queue driver: %s", conf.Driver)
+	}
+}
+
+// ShouldDeadLetter 判断处理失败的消息是否应转入死信队列，为 true 时各驱动都不会再重新投递
+func ShouldDeadLetter(err error, deliveryCount int) bool {
+	if errors.Is(err, ErrPoison) {
+		return true
+	}
+	return setting.QueueSetting.MaxDeliveryCount > 0 && deliveryCount >= setting.QueueSetting.MaxDeliveryCount
+}
+
+// maxRetryDelay 重试等待时间的上限
+const maxRetryDelay = time.Hour
+
+// retryDelay 第 deliveryCount 次投递失败后的等待时间，从 RetryDelay 起按次数翻倍，redis 与 memory 驱动共用
+func retryDelay(deliveryCount int) time.Duration {
+	delay := setting.QueueSetting.RetryDelay
+	if delay <= 0 {
+		delay = 5 * time.Second
+	}
+	for i := 1; i < deliveryCount && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,242 @@
+package bus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"hr-api/pkg/cache"
+	"hr-api/pkg/setting"
+)
+
+// RedisQueue 基于 Redis 的队列实现，供本地开发在没有 Azure 时使用。
+// 就绪消息保存在列表中，计划投递与等待重试的消息保存在按投递时间排序的有序集合中；
+// 取出的消息移入处理中列表并记录锁定期限，处理完成后才删除，worker 崩溃时超时的消息重新入队
+type RedisQueue struct {
+	name  string
+	cache *cache.RedisCache
+
+	mu       sync.Mutex
+	reapedAt time.Time
+}
+
+// redisEnvelope Redis 中保存的消息结构，Id 保证内容相同的消息在有序集合中不会合并
+type redisEnvelope struct {
+	Id            string `json:"id"`
+	Body          []byte `json:"body"`
+	DeliveryCount int    `json:"delivery_count"`
+	NotBefore     int64  `json:"not_before"`
+}
+
+const (
+	// redisPromoteLimit 每次接收前最多转入就绪列表的到期消息数
+	redisPromoteLimit = 100
+	// redisReapInterval 检查锁定超时消息的间隔
+	redisReapInterval = time.Minute
+)
+
+// redisPromoteScript 把到期的计划消息按投递时间先后转入就绪列表
+const redisPromoteScript = `
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, m in ipairs(due) do
+	redis.call('ZREM', KEYS[1], m)
+	redis.call('LPUSH', KEYS[2], m)
+end
+return #due`
+
+// redisSettleScript 从处理中列表删除消息，失败的消息按 ARGV[2] 转入重试集合或死信队列
+const redisSettleScript = `
+redis.call('LREM', KEYS[1], 1, ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+if ARGV[2] == 'retry' then
+	redis.call('ZADD', KEYS[3], ARGV[4], ARGV[3])
+elseif ARGV[2] == 'dead' then
+	redis.call('LPUSH', KEYS[3], ARGV[3])
+end
+return 1`
+
+// redisReapScript 锁定超时的消息重新入队并计入投递次数，使反复导致崩溃的消息最终转入死信队列；
+// 还没有锁定期限的消息（取出后未来得及记录）先补记一个期限
+const redisReapScript = `
+local requeued = 0
+for _, m in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+	local deadline = redis.call('ZSCORE', KEYS[2], m)
+	if not deadline then
+		redis.call('ZADD', KEYS[2], ARGV[2], m)
+	elseif tonumber(deadline) <= tonumber(ARGV[1]) then
+		redis.call('LREM', KEYS[1], 1, m)
+		redis.call('ZREM', KEYS[2], m)
+		local ok, env = pcall(cjson.decode, m)
+		if ok and type(env) == 'table' then
+			env.delivery_count = (tonumber(env.delivery_count) or 0) + 1
+			m = cjson.encode(env)
+		end
+		redis.call('LPUSH', KEYS[3], m)
+		requeued = requeued + 1
+	end
+end
+return requeued`
+
+func NewRedisQueue(name string) (*RedisQueue, error) {
+	rd, err := cache.GetInstance()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisQueue{name: name, cache: rd}, nil
+}
+
+func (q *RedisQueue) Name() string {
+	return q.name
+}
+
+func (q *RedisQueue) key() string {
+	return "QUEUE_" + q.name
+}
+
+func (q *RedisQueue) delayedKey() string {
+	return q.key() + "_DELAYED"
+}
+
+func (q *RedisQueue) processingKey() string {
+	return q.key() + "_PROCESSING"
+}
+
+func (q *RedisQueue) leaseKey() string {
+	return q.key() + "_LEASES"
+}
+
+func (q *RedisQueue) deadLetterKey() string {
+	return "QUEUE_" + DeadLetterQueue(q.name)
+}
+
+func (q *RedisQueue) Send(ctx context.Context, body []byte) error {
+	data, err := json.Marshal(redisEnvelope{Id: newEnvelopeId(), Body: body})
+	if err != nil {
+		return err
+	}
+	return q.cache.LPush(ctx, q.key(), data)
+}
+
+func (q *RedisQueue) SendScheduled(ctx context.Context, body []byte, at time.Time) error {
+	if !at.After(time.Now()) {
+		return q.Send(ctx, body)
+	}
+
+	data, err := json.Marshal(redisEnvelope{Id: newEnvelopeId(), Body: body, NotBefore: at.Unix()})
+	if err != nil {
+		return err
+	}
+	return q.cache.ZAdd(ctx, q.delayedKey(), float64(at.Unix()), data)
+}
+
+func (q *RedisQueue) Receive(ctx context.Context, handler func(*Message) error) error {
+	q.reap(ctx)
+	if _, err := q.cache.Eval(ctx, redisPromoteScript, []string{q.delayedKey(), q.key()}, time.Now().Unix(), redisPromoteLimit); err != nil {
+		return err
+	}
+
+	raw, err := q.cache.BLMove(ctx, q.key(), q.processingKey(), q.pollInterval())
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(q.lockDuration()).Unix()
+	if err := q.cache.ZAdd(ctx, q.leaseKey(), float64(deadline), raw); err != nil {
+		return err
+	}
+
+	// worker 停止时 ctx 已取消，结果仍需写回，否则消息要等锁定超时才会重新入队
+	settleCtx := context.WithoutCancel(ctx)
+
+	var env redisEnvelope
+	if err := json.Unmarshal([]byte(raw), &env); err != nil {
+		log.Printf("[bus] message moved to %s: %v", DeadLetterQueue(q.name), err)
+		return q.settle(settleCtx, raw, "dead", q.deadLetterKey(), raw, 0)
+	}
+
+	env.DeliveryCount++
+	msg := &Message{Body: env.Body, DeliveryCount: env.DeliveryCount}
+	handleErr := handler(msg)
+	if handleErr == nil {
+		return q.settle(settleCtx, raw, "ack", q.delayedKey(), "", 0)
+	}
+
+	if ShouldDeadLetter(handleErr, msg.DeliveryCount) {
+		log.Printf("[bus] message moved to %s: %v", DeadLetterQueue(q.name), handleErr)
+		data, err := json.Marshal(env)
+		if err != nil {
+			return err
+		}
+		return q.settle(settleCtx, raw, "dead", q.deadLetterKey(), string(data), 0)
+	}
+
+	at := time.Now().Add(retryDelay(msg.DeliveryCount))
+	env.NotBefore = at.Unix()
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return q.settle(settleCtx, raw, "retry", q.delayedKey(), string(data), at.Unix())
+}
+
+func (q *RedisQueue) Close(ctx context.Context) error {
+	return nil
+}
+
+// settle 记录处理结果：ack 删除消息，retry 放入重试集合，dead 转入死信队列
+func (q *RedisQueue) settle(ctx context.Context, raw, mode, destination, data string, score int64) error {
+	keys := []string{q.processingKey(), q.leaseKey(), destination}
+	_, err := q.cache.Eval(ctx, redisSettleScript, keys, raw, mode, data, score)
+	return err
+}
+
+// reap 定期把锁定超时的消息重新入队，多个 worker 同时执行也不会重复入队
+func (q *RedisQueue) reap(ctx context.Context) {
+	q.mu.Lock()
+	if time.Since(q.reapedAt) < redisReapInterval {
+		q.mu.Unlock()
+		return
+	}
+	q.reapedAt = time.Now()
+	q.mu.Unlock()
+
+	now := time.Now()
+	keys := []string{q.processingKey(), q.leaseKey(), q.key()}
+	requeued, err := q.cache.Eval(ctx, redisReapScript, keys, now.Unix(), now.Add(q.lockDuration()).Unix())
+	if err != nil {
+		log.Printf("[bus] reap %s failed: %v", q.processingKey(), err)
+		return
+	}
+	if n, ok := requeued.(int64); ok && n > 0 {
+		log.Printf("[bus] %d messages of %s requeued after lock expired", n, q.name)
+	}
+}
+
+// pollInterval 就绪列表为空时阻塞等待的时间，模拟 Service Bus 的阻塞接收
+func (q *RedisQueue) pollInterval() time.Duration {
+	if interval := setting.QueueSetting.PollInterval; interval > 0 {
+		return interval
+	}
+	return time.Second
+}
+
+func (q *RedisQueue) lockDuration() time.Duration {
+	if d := setting.QueueSetting.LockDuration; d > 0 {
+		return d
+	}
+	return 10 * time.Minute
+}
+
+func newEnvelopeId() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -233,6 +233,15 @@ func (r *RedisCache) HGetAll(ctx context.Context, key string) (map[string]string
 	return result, nil
 }
 
+func (r *RedisCache) HIncrBy(ctx context.Context, key, field string, incr int64) (int64, error) {
+	val, err := r.client.HIncrBy(ctx, key, field, incr).Result()
+	if err != nil {
+		return 0, fmt.Errorf("哈希字段自增失败[key=%s, field=%s]: %w", key, field, err)
+	}
+
+	return val, nil
+}
+
 // 集合操作
 func (r *RedisCache) SAdd(ctx context.Context, key string, members ...interface{}) error {
 	err := r.client.SAdd(ctx, key, members...).Err()
@@ -262,6 +271,29 @@ func (r *RedisCache) LPush(ctx context.Context, key string, values ...interface{
 	return nil
 }
 
+// BLMove 阻塞地从 source 右端取出一个元素放入 destination 左端，超时返回 ErrCacheMiss
+func (r *RedisCache) BLMove(ctx context.Context, source, destination string, timeout time.Duration) (string, error) {
+	val, err := r.client.BLMove(ctx, source, destination, "RIGHT", "LEFT", timeout).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", ErrCacheMiss
+		}
+		return "", fmt.Errorf("列表移动失败[source=%s, destination=%s]: %w", source, destination, err)
+	}
+
+	return val, nil
+}
+
+// 有序集合操作
+func (r *RedisCache) ZAdd(ctx context.Context, key string, score float64, member interface{}) error {
+	err := r.client.ZAdd(ctx, key, &redis.Z{Score: score, Member: member}).Err()
+	if err != nil {
+		return fmt.Errorf("添加有序集合成员失败[key=%s]: %w", key, err)
+	}
+
+	return nil
+}
+
 // 批量获取
 func (r *RedisCache) MGet(ctx context.Context, keys []string) ([]interface{}, error) {
 	if len(keys) == 0 {
@@ -295,6 +327,16 @@ func (r *RedisCache) Decr(ctx context.Context, key string) (int64, error) {
 	return val, nil
 }
 
+// 脚本，多个 key 需要原子修改时使用
+func (r *RedisCache) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	val, err := r.client.Eval(ctx, script, keys, args...).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("执行脚本失败[keys=%v]: %w", keys, err)
+	}
+
+	return val, nil
+}
+
 // 分布式锁
 func (r *RedisCache) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
 	// 使用SET NX EX实现分布式锁
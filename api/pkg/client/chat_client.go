@@ -0,0 +1,277 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/openai/openai-go/v2"
+	"hr-api/pkg/setting"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout = 60 * time.Second
+	maxRetry       = 3
+)
+
+// chatClient 基于 Chat Completions API 的简历分析实现，Azure 与 OpenAI 兼容接口共用
+type chatClient struct {
+	client *openai.Client
+	model  string
+}
+
+// ================= Public API =================
+
+// Deployment 返回当前使用的模型（部署）名称
+func (c *chatClient) Deployment() string {
+	return c.model
+}
+
+func (c *chatClient) AnalyzeResume(
+	ctx context.Context,
+	job JobInfo,
+	resumeText string,
+) (*ResumeAnalysis, *Usage, error) {
+
+	usage := &Usage{Deployment: c.model}
+
+	if strings.TrimSpace(resumeText) == "" {
+		return nil, usage, errors.New("简历内容不能为空")
+	}
+	prompt := job.Prompt
+	if prompt == nil {
+		return nil, usage, errors.New("提示词不能为空")
+	}
+
+	ctx, cancel := normalizeContext(ctx)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { usage.Latency = time.Since(start) }()
+
+	var lastErr error
+	for attempt := 1; attempt <= maxRetry; attempt++ {
+		usage.Retries = attempt - 1
+		Report(ctx, StageModel, "", map[string]interface{}{"deployment": c.model, "attempt": attempt})
+
+		result, err := c.callOnce(ctx, prompt, usage)
+		if err == nil {
+			return result, usage, nil
+		}
+
+		lastErr = err
+		log.Printf("[AnalyzeResume] attempt=%d failed: %v", attempt, err)
+		wait := time.Duration(attempt*attempt) * time.Second
+		if attempt < maxRetry {
+			Report(ctx, StageRetry, err.Error(), map[string]interface{}{"attempt": attempt, "wait_seconds": int(wait.Seconds())})
+		}
+		time.Sleep(wait)
+	}
+
+	return nil, usage, fmt.Errorf("AI分析失败（重试%d次）：%w", maxRetry, lastErr)
+}
+
+func normalizeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, defaultTimeout)
+}
+
+// ================= Internal =================
+
+func (c *chatClient) callOnce(
+	ctx context.Context,
+	prompt *Prompt,
+	usage *Usage,
+) (*ResumeAnalysis, error) {
+
+	messages := []openai.ChatCompletionMessageParamUnion{
+		openai.SystemMessage(prompt.System),
+		openai.UserMessage(prompt.User),
+	}
+
+	var lastErr error
+
+	for attempt := 0; attempt < 2; attempt++ {
+
+		raw, err := c.complete(ctx, messages, analysisResponseFormat(), usage)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var doc map[string]interface{}
+		if err := tryParseJSON(raw, &doc); err != nil {
+			lastErr = err
+			log.Println("⚠️ JSON 解析失败，重试一次")
+			log.Println(raw)
+			continue
+		}
+
+		result, invalid := decodeAnalysis(doc)
+		if len(invalid) == 0 {
+			return result, nil
+		}
+
+		log.Printf("⚠️ 分析结果存在不合法字段，请求模型修复: %v", invalid)
+		Report(ctx, StageRepair, "", invalid)
+		result, err = c.repair(ctx, messages, raw, doc, invalid, usage)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("模型多次返回非法 JSON: %w", lastErr)
+}
+
+// repair 把不合法的字段告知模型，只要求返回这些字段修正后的值，再合并回原结果
+func (c *chatClient) repair(
+	ctx context.Context,
+	messages []openai.ChatCompletionMessageParamUnion,
+	raw string,
+	doc map[string]interface{},
+	invalid []FieldError,
+	usage *Usage,
+) (*ResumeAnalysis, error) {
+
+	var items []string
+	for _, f := range invalid {
+		items = append(items, "- "+f.String())
+	}
+	repairPrompt := fmt.Sprintf(`上述 JSON 中以下字段不符合要求：
+%s
+
+请只修正这些字段，返回仅包含这些字段的 JSON 对象，并保持原有的嵌套结构（例如 {"analysis": {"match_score": 80}}），不要返回其他字段。`, strings.Join(items, "\n"))
+
+	repairMessages := append(append([]openai.ChatCompletionMessageParamUnion{}, messages...),
+		openai.AssistantMessage(raw),
+		openai.UserMessage(repairPrompt),
+	)
+
+	patchRaw, err := c.complete(ctx, repairMessages, openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONObject: &openai.ResponseFormatJSONObjectParam{},
+	}, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch map[string]interface{}
+	if err := tryParseJSON(patchRaw, &patch); err != nil {
+		return nil, fmt.Errorf("修复结果不是合法 JSON: %w", err)
+	}
+
+	mergeJSON(doc, patch)
+	result, invalid := decodeAnalysis(doc)
+	if len(invalid) > 0 {
+		return nil, &ValidationError{Fields: invalid}
+	}
+
+	return result, nil
+}
+
+// complete 调用一次 Chat Completions 接口并返回文本内容，消耗的 token 累加到 usage
+func (c *chatClient) complete(
+	ctx context.Context,
+	messages []openai.ChatCompletionMessageParamUnion,
+	responseFormat openai.ChatCompletionNewParamsResponseFormatUnion,
+	usage *Usage,
+) (string, error) {
+
+	params := openai.ChatCompletionNewParams{
+		Model:          c.model,
+		Messages:       messages,
+		ResponseFormat: responseFormat,
+		//Temperature:         openai.Float(0.7),
+		MaxCompletionTokens: openai.Int(50000),
+	}
+
+	if progressFrom(ctx) != nil {
+		return c.completeStream(ctx, params, usage)
+	}
+
+	resp, err := c.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	usage.add(resp.Usage)
+
+	if len(resp.Choices) == 0 {
+		return "", errors.New("空 choices")
+	}
+
+	return resp.Choices[0].Message.Content, nil
+}
+
+// completeStream 以流式方式请求，模型输出的每一段都作为 token 事件上报
+func (c *chatClient) completeStream(
+	ctx context.Context,
+	params openai.ChatCompletionNewParams,
+	usage *Usage,
+) (string, error) {
+
+	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{
+		IncludeUsage: openai.Bool(true),
+	}
+
+	stream := c.client.Chat.Completions.NewStreaming(ctx, params)
+	defer stream.Close()
+
+	acc := openai.ChatCompletionAccumulator{}
+	for stream.Next() {
+		chunk := stream.Current()
+		acc.AddChunk(chunk)
+
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			Report(ctx, StageToken, chunk.Choices[0].Delta.Content, nil)
+		}
+	}
+	// 中途失败的请求同样计入调用次数，已收到的用量（通常为 0）一并累计
+	usage.add(acc.Usage)
+	if err := stream.Err(); err != nil {
+		return "", err
+	}
+
+	if len(acc.Choices) == 0 {
+		return "", errors.New("空 choices")
+	}
+
+	return acc.Choices[0].Message.Content, nil
+}
+
+// analysisResponseFormat 使用由 ResumeAnalysis 生成的 JSON Schema 约束输出，
+// 不支持 Structured Outputs 的模型可通过配置退回 JSON 模式
+func analysisResponseFormat() openai.ChatCompletionNewParamsResponseFormatUnion {
+	if !setting.LLMSetting.StructuredOutput {
+		return openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONObject: &openai.ResponseFormatJSONObjectParam{},
+		}
+	}
+
+	return openai.ChatCompletionNewParamsResponseFormatUnion{
+		OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{
+			JSONSchema: openai.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:   "resume_analysis",
+				Strict: openai.Bool(true),
+				Schema: resumeAnalysisSchema,
+			},
+		},
+	}
+}
+
+func tryParseJSON[T any](raw string, out *T) error {
+	raw = strings.TrimSpace(raw)
+	// 去掉常见包裹
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+	raw = strings.TrimSpace(raw)
+	return json.Unmarshal([]byte(raw), out)
+}
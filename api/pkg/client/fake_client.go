@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	fakeEmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	fakePhonePattern = regexp.MustCompile(`1[3-9]\d{9}`)
+)
+
+// FakeResumeLLM 不调用任何模型的确定性实现，用于单元测试与本地开发：
+// 匹配度按招聘要求中的关键词在简历中出现的比例计算
+type FakeResumeLLM struct{}
+
+func NewFakeResumeLLM() *FakeResumeLLM {
+	return &FakeResumeLLM{}
+}
+
+func (f *FakeResumeLLM) Deployment() string {
+	return ProviderFake
+}
+
+func (f *FakeResumeLLM) AnalyzeResume(ctx context.Context, job JobInfo, resumeText string) (*ResumeAnalysis, *Usage, error) {
+	usage := &Usage{Deployment: ProviderFake}
+	if strings.TrimSpace(resumeText) == "" {
+		return nil, usage, errors.New("简历内容不能为空")
+	}
+
+	lowerResume := strings.ToLower(resumeText)
+	var matched, missing []string
+	for _, keyword := range fakeKeywords(job.Requirements + " " + job.Description) {
+		if strings.Contains(lowerResume, keyword) {
+			matched = append(matched, keyword)
+		} else {
+			missing = append(missing, keyword)
+		}
+	}
+
+	score := 0
+	if total := len(matched) + len(missing); total > 0 {
+		score = len(matched) * 100 / total
+	}
+
+	return &ResumeAnalysis{
+		PersonalInfo: PersonalInfo{
+			Email: fakeEmailPattern.FindString(resumeText),
+			Phone: fakePhonePattern.FindString(resumeText),
+			Links: []string{},
+		},
+		WorkExperience: []WorkExperience{},
+		Education:      []Education{},
+		Skills: Skills{
+			Technical:      matched,
+			Soft:           []string{},
+			Languages:      []Language{},
+			Certifications: []string{},
+		},
+		Analysis: JobAnalysis{
+			Strengths:       matched,
+			Weaknesses:      missing,
+			Recommendations: []string{},
+			MatchScore:      score,
+		},
+		Metadata: AnalysisMetadata{
+			AnalysisDate: time.Now().Format("2006-01-02"),
+			WordCount:    len([]rune(resumeText)),
+		},
+	}, usage, nil
+}
+
+// fakeKeywords 提取招聘需求中的英文/数字关键词（如 c++、golang、mysql），去重并保持顺序
+func fakeKeywords(text string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#'))
+	})
+	for _, field := range fields {
+		if len(field) < 2 || seen[field] {
+			continue
+		}
+		seen[field] = true
+		keywords = append(keywords, field)
+	}
+	return keywords
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"hr-api/pkg/setting"
+)
+
+const (
+	ProviderAzure  = "azure"
+	ProviderOpenAI = "openai"
+	ProviderFake   = "fake"
+)
+
+// JobInfo 简历分析所依据的招聘需求
+type JobInfo struct {
+	Title        string
+	Requirements string
+	Description  string
+	// Prompt 由职位的提示词模板渲染出的请求，调用模型的实现必须设置
+	Prompt *Prompt
+}
+
+// Prompt 由提示词模板渲染出的一次分析请求
+type Prompt struct {
+	// Version 模板版本，随分析结果一同保存
+	Version string
+	System  string
+	User    string
+}
+
+// ResumeLLM 简历分析所需的大模型能力
+type ResumeLLM interface {
+	// AnalyzeResume 分析失败时也返回已消耗的 token，便于统计成本
+	AnalyzeResume(ctx context.Context, job JobInfo, resumeText string) (*ResumeAnalysis, *Usage, error)
+	// Deployment 返回模型（部署）名称，随分析结果一同保存
+	Deployment() string
+}
+
+// NewResumeLLM 根据 [llm] 配置的 Provider 创建大模型客户端
+func NewResumeLLM() (ResumeLLM, error) {
+	switch strings.ToLower(setting.LLMSetting.Provider) {
+	case "", ProviderAzure:
+		return NewAzureOpenAIClient()
+	case ProviderOpenAI:
+		return NewOpenAICompatibleClient(setting.LLMSetting.BaseURL, setting.LLMSetting.Model, setting.LLMSetting.ApiKey)
+	case ProviderFake:
+		return NewFakeResumeLLM(), nil
+	default:
+		return nil, fmt.Errorf("unsupported llm provider: %s", setting.LLMSetting.Provider)
+	}
+}
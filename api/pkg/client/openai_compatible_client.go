@@ -0,0 +1,39 @@
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/openai/openai-go/v2"
+	"github.com/openai/openai-go/v2/option"
+)
+
+// OpenAICompatibleClient 适用于任意 OpenAI 兼容接口（OpenAI、vLLM、Ollama 等）
+type OpenAICompatibleClient struct {
+	*chatClient
+	baseURL string
+}
+
+func NewOpenAICompatibleClient(baseURL, model, apiKey string) (*OpenAICompatibleClient, error) {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		return nil, errors.New("OpenAI 兼容接口 BaseURL 不能为空")
+	}
+	if strings.TrimSpace(model) == "" {
+		return nil, errors.New("OpenAI 兼容接口 Model 不能为空")
+	}
+
+	opts := []option.RequestOption{option.WithBaseURL(baseURL)}
+	if apiKey != "" {
+		opts = append(opts, option.WithAPIKey(apiKey))
+	}
+	client := openai.NewClient(opts...)
+
+	return &OpenAICompatibleClient{
+		chatClient: &chatClient{
+			client: &client,
+			model:  model,
+		},
+		baseURL: baseURL,
+	}, nil
+}
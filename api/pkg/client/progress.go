@@ -0,0 +1,50 @@
+package client
+
+import "context"
+
+// 分析过程中上报的阶段
+const (
+	StageDownload = "download"
+	StageParse    = "parse"
+	StageParsed   = "parsed"
+	StageModel    = "model"
+	StageToken    = "token"
+	StageRetry    = "retry"
+	StageRepair   = "repair"
+	StageFallback = "fallback"
+	StageResult   = "result"
+	StageError    = "error"
+)
+
+// Event 分析进度事件
+type Event struct {
+	Stage   string      `json:"stage"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ProgressFunc 接收分析进度事件
+type ProgressFunc func(event Event)
+
+type progressKey struct{}
+
+// WithProgress 返回携带进度回调的 context，分析各阶段通过 Report 上报；
+// 带有进度回调时模型请求改为流式，逐段上报模型输出
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// Report 上报一个进度事件，context 中没有进度回调时忽略
+func Report(ctx context.Context, stage string, message string, data interface{}) {
+	if fn := progressFrom(ctx); fn != nil {
+		fn(Event{Stage: stage, Message: message, Data: data})
+	}
+}
+
+func progressFrom(ctx context.Context) ProgressFunc {
+	if ctx == nil {
+		return nil
+	}
+	fn, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return fn
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// resumeAnalysisSchema ResumeAnalysis 对应的 JSON Schema，用于 response_format 与结果校验
+var resumeAnalysisSchema = JSONSchemaFor(ResumeAnalysis{})
+
+// JSONSchemaFor 根据结构体的 json 标签生成满足 Structured Outputs strict 模式的 JSON Schema：
+// 所有字段必填且不允许额外字段。数值范围可通过 `jsonschema:"minimum=0,maximum=100"` 标签声明，
+// 标记为 `jsonschema:"-"` 的字段不由模型返回
+func JSONSchemaFor(v interface{}) map[string]interface{} {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+func schemaForType(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if !field.IsExported() || name == "-" || field.Tag.Get("jsonschema") == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			prop := schemaForType(field.Type)
+			applySchemaTag(prop, field.Tag.Get("jsonschema"))
+			properties[name] = prop
+			required = append(required, name)
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func applySchemaTag(prop map[string]interface{}, tag string) {
+	for _, item := range strings.Split(tag, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if n, err := strconv.Atoi(kv[1]); err == nil {
+			prop[kv[0]] = n
+		} else {
+			prop[kv[0]] = kv[1]
+		}
+	}
+}
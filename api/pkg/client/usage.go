@@ -0,0 +1,29 @@
+package client
+
+import (
+	"time"
+
+	"github.com/openai/openai-go/v2"
+)
+
+// Usage 一次简历分析消耗的 token，包含重试与修复请求
+type Usage struct {
+	Deployment       string
+	PromptTokens     int
+	CompletionTokens int
+	// Calls 实际发起的模型请求次数
+	Calls int
+	// Retries AnalyzeResume 的重试次数，首次即成功为0
+	Retries int
+	Latency time.Duration
+}
+
+func (u *Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+func (u *Usage) add(usage openai.CompletionUsage) {
+	u.Calls++
+	u.PromptTokens += int(usage.PromptTokens)
+	u.CompletionTokens += int(usage.CompletionTokens)
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// FieldError 分析结果中不合法的字段
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func (e FieldError) String() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationError 分析结果校验失败
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	var items []string
+	for _, f := range e.Fields {
+		items = append(items, f.String())
+	}
+	return "分析结果不合法: " + strings.Join(items, "; ")
+}
+
+// Validate 检查分析结果的业务约束。简历中可能没有姓名，姓名也可能已脱敏，因此允许为空
+func (a *ResumeAnalysis) Validate() []FieldError {
+	var errs []FieldError
+
+	if a.Analysis.MatchScore < 0 || a.Analysis.MatchScore > 100 {
+		errs = append(errs, FieldError{"analysis.match_score", fmt.Sprintf("必须在0-100之间，当前为%d", a.Analysis.MatchScore)})
+	}
+	if strings.TrimSpace(a.Summary) == "" {
+		errs = append(errs, FieldError{"summary", "不能为空"})
+	}
+	if a.Metadata.EstimatedYOE < 0 || a.Metadata.EstimatedYOE > 60 {
+		errs = append(errs, FieldError{"metadata.estimated_yoe", fmt.Sprintf("必须在0-60之间，当前为%d", a.Metadata.EstimatedYOE)})
+	}
+	for i, lang := range a.Skills.Languages {
+		if strings.TrimSpace(lang.Name) == "" {
+			errs = append(errs, FieldError{fmt.Sprintf("skills.languages[%d].name", i), "不能为空"})
+		}
+	}
+
+	return errs
+}
+
+// decodeAnalysis 解析模型返回的 JSON 并按 schema 与业务约束校验，
+// 字段类型错误时仍返回尽量解码的结果，便于只修复不合法的字段
+func decodeAnalysis(doc map[string]interface{}) (*ResumeAnalysis, []FieldError) {
+	errs := checkSchema(doc, resumeAnalysisSchema, "")
+
+	var result ResumeAnalysis
+	data, _ := json.Marshal(doc)
+	json.Unmarshal(data, &result)
+
+	if len(errs) == 0 {
+		errs = result.Validate()
+	}
+	return &result, errs
+}
+
+// checkSchema 检查必填字段与字段类型
+func checkSchema(value interface{}, schema map[string]interface{}, path string) []FieldError {
+	var errs []FieldError
+	field := path
+	if field == "" {
+		field = "$"
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []FieldError{{field, "应为对象"}}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			child := joinField(path, name)
+			v, exists := obj[name]
+			if !exists || v == nil {
+				errs = append(errs, FieldError{child, "缺少字段"})
+				continue
+			}
+			if prop, ok := properties[name].(map[string]interface{}); ok {
+				errs = append(errs, checkSchema(v, prop, child)...)
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return []FieldError{{field, "应为数组"}}
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for i, v := range arr {
+			errs = append(errs, checkSchema(v, items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			errs = append(errs, FieldError{field, "应为字符串"})
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			errs = append(errs, FieldError{field, "应为整数"})
+		}
+	}
+
+	return errs
+}
+
+// mergeJSON 将修复后的字段深度合并到原始结果中
+func mergeJSON(dst, patch map[string]interface{}) {
+	for k, v := range patch {
+		if pv, ok := v.(map[string]interface{}); ok {
+			if dv, ok := dst[k].(map[string]interface{}); ok {
+				mergeJSON(dv, pv)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
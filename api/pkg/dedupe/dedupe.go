@@ -0,0 +1,173 @@
+package dedupe
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// NumHashes MinHash 签名长度
+	NumHashes = 64
+	// bandRows LSH 每个分段包含的签名行数，64/4 = 16 个分段，
+	// 相似度 0.8 的两份简历至少一个分段相同的概率约为 0.9998
+	bandRows = 4
+	// shingleSize 按字符切分的 shingle 长度，中英文混排时比按词切分更稳定
+	shingleSize = 5
+
+	// SimilarThreshold 估算相似度达到该值时视为近似重复
+	SimilarThreshold = 0.85
+)
+
+// seeds 每个哈希函数的种子，由固定起点生成，保证签名跨进程可比较
+var seeds = func() [NumHashes]uint64 {
+	var s [NumHashes]uint64
+	x := uint64(0x9E3779B97F4A7C15)
+	for i := range s {
+		x = splitmix64(x)
+		s[i] = x
+	}
+	return s
+}()
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// NewHasher 返回计算文件内容哈希的 hash.Hash，上传与分析时使用同一算法
+func NewHasher() hash.Hash {
+	return sha256.New()
+}
+
+// ContentHash 文件内容的 SHA-256，十六进制表示
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizePhone 只保留数字并去掉 86 国家码，无法识别为手机号时返回空
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := strings.TrimPrefix(b.String(), "86")
+	if len(digits) != 11 || digits[0] != '1' {
+		return ""
+	}
+	return digits
+}
+
+// NormalizeEmail 去掉首尾空白并转为小写
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !strings.Contains(email, "@") {
+		return ""
+	}
+	return email
+}
+
+// shingles 将文本规范化（小写、去掉空白与标点）后按 shingleSize 个字符滑动切分
+func shingles(text string) map[uint64]struct{} {
+	var runes []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+
+	set := make(map[uint64]struct{})
+	for i := 0; i+shingleSize <= len(runes); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(string(runes[i : i+shingleSize])))
+		set[h.Sum64()] = struct{}{}
+	}
+	return set
+}
+
+// Signature 计算文本的 MinHash 签名，文本过短时返回 nil
+func Signature(text string) []uint32 {
+	set := shingles(text)
+	if len(set) == 0 {
+		return nil
+	}
+
+	sig := make([]uint32, NumHashes)
+	for i := range sig {
+		sig[i] = ^uint32(0)
+	}
+	for x := range set {
+		for i, seed := range seeds {
+			if v := uint32(splitmix64(x ^ seed)); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Similarity 两个签名相同位置取值相等的比例，即 Jaccard 相似度的估计
+func Similarity(a, b []uint32) float64 {
+	if len(a) != NumHashes || len(b) != NumHashes {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / NumHashes
+}
+
+// Bands 将签名按 bandRows 分段后的哈希，任一分段相同的简历才需要比较完整签名
+func Bands(sig []uint32) []int64 {
+	if len(sig) != NumHashes {
+		return nil
+	}
+	bands := make([]int64, 0, NumHashes/bandRows)
+	buf := make([]byte, 4)
+	for i := 0; i < NumHashes; i += bandRows {
+		h := fnv.New64a()
+		// 分段序号参与哈希，不同分段的相同取值不会互相命中
+		h.Write([]byte{byte(i / bandRows)})
+		for _, v := range sig[i : i+bandRows] {
+			binary.BigEndian.PutUint32(buf, v)
+			h.Write(buf)
+		}
+		bands = append(bands, int64(h.Sum64()))
+	}
+	return bands
+}
+
+// EncodeSignature 将签名编码为十六进制字符串保存
+func EncodeSignature(sig []uint32) string {
+	buf := make([]byte, 4*len(sig))
+	for i, v := range sig {
+		binary.BigEndian.PutUint32(buf[4*i:], v)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// DecodeSignature 解析 EncodeSignature 的结果
+func DecodeSignature(s string) ([]uint32, error) {
+	buf, err := hex.DecodeString(s)
+	if err != nil || len(buf) != 4*NumHashes {
+		return nil, fmt.Errorf("invalid minhash signature")
+	}
+	sig := make([]uint32, NumHashes)
+	for i := range sig {
+		sig[i] = binary.BigEndian.Uint32(buf[4*i:])
+	}
+	return sig, nil
+}
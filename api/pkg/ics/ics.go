@@ -0,0 +1,138 @@
+package ics
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 日历方法，REQUEST 为邀请或更新，CANCEL 为取消
+const (
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// 事件状态
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	prodID = "-//hr-api//Interview Scheduler//ZH"
+	// lineLimit RFC 5545 3.1 每行最多 75 个八位字节（不含 CRLF）
+	lineLimit = 75
+	// utcLayout RFC 5545 3.3.5 UTC 时间格式
+	utcLayout = "20060102T150405Z"
+)
+
+// Person 组织者或参会人
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event 一个日程，Sequence 在每次修改后递增，日历客户端据此用新邀请覆盖旧邀请
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string
+	Organizer   Person
+	Attendees   []Person
+}
+
+// Calendar 生成只包含一个事件的 iCalendar 文件内容，method 为空时使用 REQUEST
+func Calendar(method string, event Event, stamp time.Time) []byte {
+	if method == "" {
+		method = MethodRequest
+	}
+	if event.Status == "" {
+		event.Status = StatusConfirmed
+	}
+
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + prodID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:" + method)
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + event.UID)
+	w.line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+	w.line("DTSTAMP:" + stamp.UTC().Format(utcLayout))
+	w.line("DTSTART:" + event.Start.UTC().Format(utcLayout))
+	w.line("DTEND:" + event.End.UTC().Format(utcLayout))
+	w.line("SUMMARY:" + escapeText(event.Summary))
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(event.Description))
+	}
+	if event.Location != "" {
+		w.line("LOCATION:" + escapeText(event.Location))
+	}
+	if event.URL != "" {
+		w.line("URL:" + event.URL)
+	}
+	w.line("STATUS:" + event.Status)
+	if event.Organizer.Email != "" {
+		w.line("ORGANIZER" + cnParam(event.Organizer.Name) + ":mailto:" + event.Organizer.Email)
+	}
+	for _, attendee := range event.Attendees {
+		if attendee.Email == "" {
+			continue
+		}
+		w.line("ATTENDEE" + cnParam(attendee.Name) +
+			";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:" + attendee.Email)
+	}
+	w.line("END:VEVENT")
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// escapeText 按 RFC 5545 3.3.11 转义 TEXT 类型的值
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// cnParam 参会人显示名，含特殊字符时加引号，参数值中不允许出现双引号
+func cnParam(name string) string {
+	if name == "" {
+		return ""
+	}
+	name = strings.ReplaceAll(name, `"`, "'")
+	if strings.ContainsAny(name, ";:,") {
+		name = `"` + name + `"`
+	}
+	return ";CN=" + name
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line 写入一行内容，超过 75 个八位字节时按 RFC 5545 3.1 折行，
+// 续行以一个空格开头，且不在多字节字符中间断开
+func (w *writer) line(s string) {
+	limit := lineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// 续行开头的空格占用一个八位字节
+		limit = lineLimit - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
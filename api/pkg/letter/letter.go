@@ -0,0 +1,242 @@
+package letter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"hr-api/pkg/setting"
+)
+
+const (
+	// DefaultName 未指定模板时使用的模板名称，目录中没有时使用内置 HTML 模板
+	DefaultName = "default"
+
+	extHTML = ".html"
+	extDOCX = ".docx"
+
+	ContentTypeHTML = "text/html; charset=utf-8"
+	ContentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// placeholderRegexp 匹配 {{key}} 占位符。Word 经常把一段文字拆成多个 run，
+// 占位符的花括号与名称之间可能夹着 XML 标签，匹配时一并跳过
+var placeholderRegexp = regexp.MustCompile(`\{(?:<[^>]*>)*\{((?:<[^>]*>|[^<{}])*)\}(?:<[^>]*>)*\}`)
+
+var tagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// Template offer 函模板
+type Template struct {
+	Name    string `json:"name"`
+	Format  string `json:"format"`
+	Builtin bool   `json:"builtin"`
+
+	path string
+}
+
+// Letter 渲染后的 offer 函
+type Letter struct {
+	Filename    string
+	ContentType string
+	Body        []byte
+}
+
+// Dir 模板所在目录，位于运行目录下
+func Dir() string {
+	return filepath.Join(setting.AppSetting.RuntimeRootPath, setting.AppSetting.OfferTemplateDir)
+}
+
+// ValidName 模板名称只允许字母、数字、下划线与中划线，避免越出模板目录
+func ValidName(name string) bool {
+	return nameRegexp.MatchString(name)
+}
+
+// Load 按名称查找模板，同名的 .docx 优先于 .html
+func Load(name string) (*Template, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	if !ValidName(name) {
+		return nil, fmt.Errorf("offer 模板名称不合法: %s", name)
+	}
+
+	for _, ext := range []string{extDOCX, extHTML} {
+		path := filepath.Join(Dir(), name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return &Template{Name: name, Format: strings.TrimPrefix(ext, "."), path: path}, nil
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if name == DefaultName {
+		return &Template{Name: DefaultName, Format: "html", Builtin: true}, nil
+	}
+	return nil, fmt.Errorf("offer 模板不存在: %s", name)
+}
+
+// List 列出模板目录下的全部模板，目录中没有 default 模板时附带内置模板
+func List() ([]*Template, error) {
+	entries, err := os.ReadDir(Dir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var templates []*Template
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+		if entry.IsDir() || (ext != extHTML && ext != extDOCX) || !ValidName(name) || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		t, err := Load(name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	if !seen[DefaultName] {
+		t, _ := Load(DefaultName)
+		templates = append(templates, t)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// Render 用 fields 填充模板中的 {{key}} 占位符，模板中出现 fields 未提供的占位符时报错
+func (t *Template) Render(filename string, fields map[string]string) (*Letter, error) {
+	if t.Format == "docx" {
+		content, err := os.ReadFile(t.path)
+		if err != nil {
+			return nil, err
+		}
+		body, err := renderDOCX(content, fields)
+		if err != nil {
+			return nil, fmt.Errorf("渲染 offer 模板 %s 失败: %w", t.Name, err)
+		}
+		return &Letter{Filename: filename + extDOCX, ContentType: ContentTypeDOCX, Body: body}, nil
+	}
+
+	content := builtinTemplate
+	if !t.Builtin {
+		data, err := os.ReadFile(t.path)
+		if err != nil {
+			return nil, err
+		}
+		content = string(data)
+	}
+	body, err := fill(content, fields, html.EscapeString)
+	if err != nil {
+		return nil, fmt.Errorf("渲染 offer 模板 %s 失败: %w", t.Name, err)
+	}
+	return &Letter{Filename: filename + extHTML, ContentType: ContentTypeHTML, Body: []byte(body)}, nil
+}
+
+// fill 替换占位符，取值经 escape 转义后写入
+func fill(content string, fields map[string]string, escape func(string) string) (string, error) {
+	var missing []string
+	result := placeholderRegexp.ReplaceAllStringFunc(content, func(match string) string {
+		sub := placeholderRegexp.FindStringSubmatch(match)
+		key := strings.TrimSpace(tagRegexp.ReplaceAllString(sub[1], ""))
+		value, ok := fields[key]
+		if !ok {
+			missing = append(missing, key)
+			return match
+		}
+		return escape(value)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("未知的占位符: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// renderDOCX 替换正文、页眉与页脚中的占位符，其余文件原样复制
+func renderDOCX(content []byte, fields map[string]string) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if isDocumentPart(f.Name) {
+			filled, err := fill(string(data), fields, xmlEscape)
+			if err != nil {
+				return nil, err
+			}
+			data = []byte(filled)
+		}
+
+		header := f.FileHeader
+		out, err := w.CreateHeader(&header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := out.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isDocumentPart(name string) bool {
+	if name == "word/document.xml" {
+		return true
+	}
+	return strings.HasPrefix(name, "word/") && strings.HasSuffix(name, ".xml") &&
+		(strings.HasPrefix(name, "word/header") || strings.HasPrefix(name, "word/footer"))
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+const builtinTemplate = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head><meta charset="utf-8"><title>录用通知书</title></head>
+<body>
+<h2>录用通知书</h2>
+<p>尊敬的 {{candidate_name}}：</p>
+<p>感谢您对公司的关注。经过面试评估，我们很高兴地通知您，公司决定录用您担任 <strong>{{job_name}}</strong> 职位，职级为 {{level}}。</p>
+<ul>
+  <li>月薪（税前）：{{salary}} 元</li>
+  <li>入职日期：{{start_date}}</li>
+</ul>
+<p>请于 {{expire_date}} 前确认是否接受本录用通知，逾期未确认视为放弃。</p>
+<p>期待您的加入！</p>
+<p>{{today}}</p>
+</body>
+</html>
+`
@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// 触发通知的事件，同时也是通知模板的名称
+const (
+	EventResumeUploaded     = "resume_uploaded"
+	EventAnalysisDone       = "analysis_done"
+	EventStageChanged       = "stage_changed"
+	EventInterviewScheduled = "interview_scheduled"
+	EventOfferApproval      = "offer_approval"
+)
+
+// Events 全部通知事件
+var Events = []string{
+	EventResumeUploaded,
+	EventAnalysisDone,
+	EventStageChanged,
+	EventInterviewScheduled,
+	EventOfferApproval,
+}
+
+// ValidEvent 判断 event 是否为已知的通知事件
+func ValidEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Attachment 邮件附件，如面试的日历邀请
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// Message 一封通知邮件，Text 与 HTML 作为 multipart/alternative 的两个版本同时发送
+type Message struct {
+	To          []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Transport 邮件发送方式
+type Transport interface {
+	Send(ctx context.Context, from mail.Address, msg *Message) error
+}
+
+// Bytes 生成 RFC 5322 格式的邮件内容，正文使用 quoted-printable，附件使用 base64
+func (m *Message) Bytes(from mail.Address, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	to := make([]string, 0, len(m.To))
+	for _, addr := range m.To {
+		to = append(to, (&mail.Address{Address: addr}).String())
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("From", from.String())
+	header.Set("To", strings.Join(to, ", "))
+	header.Set("Subject", mime.BEncoding.Encode("utf-8", m.Subject))
+	header.Set("Date", now.Format(time.RFC1123Z))
+	header.Set("MIME-Version", "1.0")
+
+	body := multipart.NewWriter(&buf)
+	if len(m.Attachments) == 0 {
+		header.Set("Content-Type", "multipart/alternative; boundary="+body.Boundary())
+		writeHeader(&buf, header)
+		if err := m.writeAlternative(body); err != nil {
+			return nil, err
+		}
+		if err := body.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	header.Set("Content-Type", "multipart/mixed; boundary="+body.Boundary())
+	writeHeader(&buf, header)
+
+	// 正文与附件并列，正文本身又是一个 multipart/alternative
+	var alternative bytes.Buffer
+	inner := multipart.NewWriter(&alternative)
+	if err := m.writeAlternative(inner); err != nil {
+		return nil, err
+	}
+	if err := inner.Close(); err != nil {
+		return nil, err
+	}
+	part, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + inner.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(alternative.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, a := range m.Attachments {
+		part, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *Message) writeAlternative(w *multipart.Writer) error {
+	for _, p := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		if p.content == "" {
+			continue
+		}
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write([]byte(p.content)); err != nil {
+			return err
+		}
+		if err := qp.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "MIME-Version", "Content-Type"} {
+		fmt.Fprintf(buf, "%s: %s\r\n", key, header.Get(key))
+	}
+	buf.WriteString("\r\n")
+}
+
+// writeBase64 按 RFC 2045 每行 76 个字符写入 base64 内容
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := 76
+		if len(encoded) < n {
+			n = len(encoded)
+		}
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:n]); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"hr-api/pkg/setting"
+)
+
+// TLS 模式
+const (
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+	TLSNone     = "none"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// SMTP 通过 SMTP 服务器发送邮件
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// TLS starttls（默认，服务器支持时升级）、tls（隐式 TLS）或 none
+	TLS     string
+	Timeout time.Duration
+}
+
+// NewSMTP 按 [mail] 配置创建 SMTP 发送方式
+func NewSMTP() *SMTP {
+	conf := setting.MailSetting
+	return &SMTP{
+		Host:     conf.Host,
+		Port:     conf.Port,
+		Username: conf.Username,
+		Password: conf.Password,
+		TLS:      strings.ToLower(conf.TLS),
+		Timeout:  conf.Timeout,
+	}
+}
+
+// Enabled 是否配置了 SMTP 服务器
+func Enabled() bool {
+	return setting.MailSetting.Host != ""
+}
+
+// Sender 配置中的发件人
+func Sender() mail.Address {
+	return mail.Address{Name: setting.MailSetting.FromName, Address: setting.MailSetting.From}
+}
+
+// IsPermanent 判断发送失败是否为永久错误（SMTP 5xx，如收件人不存在），永久错误重试无意义
+func IsPermanent(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code >= 500
+}
+
+func (s *SMTP) Send(ctx context.Context, from mail.Address, msg *Message) error {
+	if len(msg.To) == 0 {
+		return errors.New("邮件没有收件人")
+	}
+	body, err := msg.Bytes(from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.TLS != TLSImplicit && s.TLS != TLSNone {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if s.Username != "" {
+		// PlainAuth 只允许在 TLS 连接或 localhost 上发送密码
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *SMTP) dial(ctx context.Context) (*smtp.Client, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	port := s.Port
+	if port <= 0 {
+		port = 587
+		if s.TLS == TLSImplicit {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+
+	dialer := &net.Dialer{Deadline: deadline}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.TLS == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("连接 SMTP 服务器 %s 失败: %w", addr, err)
+	}
+	// 整个会话共用一个截止时间，避免服务器无响应时一直阻塞
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"hr-api/pkg/setting"
+)
+
+const fileExt = ".tmpl"
+
+// Template 一个事件的通知模板，文件中需定义 subject、text 与 html 三个子模板，
+// html 使用 html/template 解析，字段值会按上下文转义
+type Template struct {
+	Event   string
+	Builtin bool
+
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+// Dir 通知模板所在目录，位于运行目录下
+func Dir() string {
+	return filepath.Join(setting.AppSetting.RuntimeRootPath, setting.MailSetting.TemplateDir)
+}
+
+// Load 加载事件的通知模板，模板目录中有 <event>.tmpl 时覆盖内置模板；每次调用都会重新读取文件
+func Load(event string) (*Template, error) {
+	if !ValidEvent(event) {
+		return nil, fmt.Errorf("未知的通知事件: %s", event)
+	}
+
+	content, err := os.ReadFile(filepath.Join(Dir(), event+fileExt))
+	if os.IsNotExist(err) {
+		t, err := parse(event, builtinTemplates[event])
+		if err != nil {
+			return nil, err
+		}
+		t.Builtin = true
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parse(event, string(content))
+}
+
+// Render 用 fields 渲染出邮件主题与正文，模板引用了 fields 中没有的字段时报错
+func (t *Template) Render(fields map[string]string) (*Message, error) {
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", fields); err != nil {
+		return nil, fmt.Errorf("渲染通知模板 %s 失败: %w", t.Event, err)
+	}
+	if err := t.text.ExecuteTemplate(&text, "text", fields); err != nil {
+		return nil, fmt.Errorf("渲染通知模板 %s 失败: %w", t.Event, err)
+	}
+	if err := t.html.ExecuteTemplate(&html, "html", fields); err != nil {
+		return nil, fmt.Errorf("渲染通知模板 %s 失败: %w", t.Event, err)
+	}
+
+	return &Message{
+		// 主题不能换行
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(text.String()),
+		HTML:    strings.TrimSpace(html.String()),
+	}, nil
+}
+
+func parse(event, content string) (*Template, error) {
+	text, err := template.New(event).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("解析通知模板 %s 失败: %w", event, err)
+	}
+	html, err := htmltemplate.New(event).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("解析通知模板 %s 失败: %w", event, err)
+	}
+
+	for _, block := range []string{"subject", "text", "html"} {
+		if text.Lookup(block) == nil {
+			return nil, fmt.Errorf("通知模板 %s 缺少 {{define \"%s\"}} 定义", event, block)
+		}
+	}
+
+	return &Template{Event: event, text: text, html: html}, nil
+}
+
+var builtinTemplates = map[string]string{
+	EventResumeUploaded: `{{define "subject"}}【{{.job_name}}】收到新简历：{{.filename}}{{end}}
+
+{{define "text"}}
+{{.recipient}}，您好：
+
+{{.uploader}} 为您负责的职位「{{.job_name}}」上传了简历「{{.filename}}」，AI 分析完成后会再通知您。
+{{end}}
+
+{{define "html"}}
+<p>{{.recipient}}，您好：</p>
+<p>{{.uploader}} 为您负责的职位「<strong>{{.job_name}}</strong>」上传了简历「{{.filename}}」，AI 分析完成后会再通知您。</p>
+{{end}}
+`,
+	EventAnalysisDone: `{{define "subject"}}【{{.job_name}}】简历分析完成：{{.filename}}，匹配度 {{.match_score}}{{end}}
+
+{{define "text"}}
+{{.recipient}}，您好：
+
+简历「{{.filename}}」针对职位「{{.job_name}}」的 AI 分析已完成，匹配度为 {{.match_score}} 分。
+{{end}}
+
+{{define "html"}}
+<p>{{.recipient}}，您好：</p>
+<p>简历「{{.filename}}」针对职位「<strong>{{.job_name}}</strong>」的 AI 分析已完成，匹配度为 <strong>{{.match_score}}</strong> 分。</p>
+{{end}}
+`,
+	EventStageChanged: `{{define "subject"}}【{{.job_name}}】{{.candidate_name}} 进入 {{.to_stage}} 阶段{{end}}
+
+{{define "text"}}
+{{.recipient}}，您好：
+
+{{.operator}} 将候选人 {{.candidate_name}} 在职位「{{.job_name}}」的应聘阶段从 {{.from_stage}} 调整为 {{.to_stage}}。
+{{if .reason}}原因：{{.reason}}{{end}}
+{{end}}
+
+{{define "html"}}
+<p>{{.recipient}}，您好：</p>
+<p>{{.operator}} 将候选人 <strong>{{.candidate_name}}</strong> 在职位「{{.job_name}}」的应聘阶段从 {{.from_stage}} 调整为 <strong>{{.to_stage}}</strong>。</p>
+{{if .reason}}<p>原因：{{.reason}}</p>{{end}}
+{{end}}
+`,
+	EventInterviewScheduled: `{{define "subject"}}面试邀请：{{.candidate_name}}（{{.job_name}} 第 {{.round}} 轮）{{.start_time}}{{end}}
+
+{{define "text"}}
+{{.recipient}}，您好：
+
+您被安排为候选人 {{.candidate_name}} 的面试官。
+职位：{{.job_name}}，第 {{.round}} 轮
+时间：{{.start_time}} - {{.end_time}}
+{{if .location}}地点：{{.location}}
+{{end}}{{if .meeting_url}}会议链接：{{.meeting_url}}
+{{end}}
+日历邀请见附件。
+{{end}}
+
+{{define "html"}}
+<p>{{.recipient}}，您好：</p>
+<p>您被安排为候选人 <strong>{{.candidate_name}}</strong> 的面试官。</p>
+<ul>
+  <li>职位：{{.job_name}}，第 {{.round}} 轮</li>
+  <li>时间：{{.start_time}} - {{.end_time}}</li>
+  {{if .location}}<li>地点：{{.location}}</li>{{end}}
+  {{if .meeting_url}}<li>会议链接：<a href="{{.meeting_url}}">{{.meeting_url}}</a></li>{{end}}
+</ul>
+<p>日历邀请见附件。</p>
+{{end}}
+`,
+	EventOfferApproval: `{{define "subject"}}待审批 offer：{{.candidate_name}}（{{.job_name}}）{{end}}
+
+{{define "text"}}
+{{.recipient}}，您好：
+
+候选人 {{.candidate_name}} 的 offer 等待您审批（第 {{.step}} 步）。
+职位：{{.job_name}}
+职级：{{.level}}
+月薪（税前）：{{.salary}} 元
+入职日期：{{.start_date}}
+{{end}}
+
+{{define "html"}}
+<p>{{.recipient}}，您好：</p>
+<p>候选人 <strong>{{.candidate_name}}</strong> 的 offer 等待您审批（第 {{.step}} 步）。</p>
+<ul>
+  <li>职位：{{.job_name}}</li>
+  <li>职级：{{.level}}</li>
+  <li>月薪（税前）：{{.salary}} 元</li>
+  <li>入职日期：{{.start_date}}</li>
+</ul>
+{{end}}
+`,
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// charsetEncoding 按字符集名称（如 gb2312、big5、windows-1252）或 Windows 代码页（如 936）查找编码，
+// 未知字符集返回 nil
+func charsetEncoding(name string) encoding.Encoding {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), `"'`))
+	switch name {
+	case "gbk", "gb2312", "gb_2312-80", "cp936", "936", "x-gbk", "euc-cn":
+		return simplifiedchinese.GBK
+	case "gb18030", "54936":
+		return simplifiedchinese.GB18030
+	case "big5", "big5-hkscs", "cp950", "950":
+		return traditionalchinese.Big5
+	case "windows-1252", "cp1252", "1252", "iso-8859-1", "latin1", "us-ascii":
+		return charmap.Windows1252
+	case "utf-16", "utf-16le", "1200":
+		return unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)
+	case "utf-16be", "1201":
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM)
+	}
+	return nil
+}
+
+// decodeText 把字节按声明的字符集转为 UTF-8；未声明字符集时识别 BOM，
+// 不是合法 UTF-8 的按 GB18030 解码（国内招聘网站导出的文件多为 GBK）
+func decodeText(data []byte, charset string) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:])
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		charset = "utf-16"
+	}
+
+	enc := charsetEncoding(charset)
+	if enc == nil {
+		if utf8.Valid(data) {
+			return string(data)
+		}
+		enc = simplifiedchinese.GB18030
+	}
+	out, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(out)
+}
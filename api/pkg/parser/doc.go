@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	docFibIdent      = 0xA5EC
+	docFlagEncrypted = 0x0100
+	docFlagTable1    = 0x0200
+	docCompressedFC  = 0x40000000
+	docClxIndex      = 33 // fcClx/lcbClx 在 FibRgFcLcb97 中的序号
+)
+
+// parseDoc 解析 Word 97-2003 二进制文档：读取 WordDocument 流的 FIB，
+// 从 0Table/1Table 流的 Clx 取得片段表（piece table），再按片段拼出正文
+func (p *UnifiedResumeParser) parseDoc(data []byte) (string, error) {
+	return docToText(data)
+}
+
+func docToText(data []byte) (string, error) {
+	ole, err := openOLE(data)
+	if err != nil {
+		return "", err
+	}
+	word, err := ole.Stream("WordDocument")
+	if err != nil {
+		return "", err
+	}
+	le := binary.LittleEndian
+	if len(word) < 0x22 || le.Uint16(word) != docFibIdent {
+		return "", fmt.Errorf("不是有效的Word文档")
+	}
+	flags := le.Uint16(word[0x0A:])
+	if flags&docFlagEncrypted != 0 {
+		return "", fmt.Errorf("不支持加密的Word文档")
+	}
+	tableName := "0Table"
+	if flags&docFlagTable1 != 0 {
+		tableName = "1Table"
+	}
+	table, err := ole.Stream(tableName)
+	if err != nil {
+		return "", err
+	}
+
+	// FIB 由定长头、FibRgW、FibRgLw、FibRgFcLcb 依次组成，后几段长度可变
+	pos := 32
+	if pos+2 > len(word) {
+		return "", fmt.Errorf("Word文档FIB不完整")
+	}
+	pos += 2 + int(le.Uint16(word[pos:]))*2
+	if pos+2 > len(word) {
+		return "", fmt.Errorf("Word文档FIB不完整")
+	}
+	cslw := int(le.Uint16(word[pos:]))
+	rgLw := pos + 2
+	pos = rgLw + cslw*4
+	if pos+2 > len(word) || cslw < 11 {
+		return "", fmt.Errorf("Word文档FIB不完整")
+	}
+	// ccpText、ccpFtn、ccpHdd、ccpMcr、ccpAtn、ccpEdn、ccpTxbx、ccpHdrTxbx 之和为全部文本长度
+	var totalCP uint32
+	for i := 3; i <= 10; i++ {
+		totalCP += le.Uint32(word[rgLw+i*4:])
+	}
+	rgFcLcb := pos + 2
+	clxOff := rgFcLcb + docClxIndex*8
+	if int(le.Uint16(word[pos:])) <= docClxIndex || clxOff+8 > len(word) {
+		return "", fmt.Errorf("Word文档FIB不完整")
+	}
+	fcClx, lcbClx := le.Uint32(word[clxOff:]), le.Uint32(word[clxOff+4:])
+	if uint64(fcClx)+uint64(lcbClx) > uint64(len(table)) {
+		return "", fmt.Errorf("Word文档片段表越界")
+	}
+
+	text, err := docPieces(word, table[fcClx:fcClx+lcbClx], totalCP)
+	if err != nil {
+		return "", err
+	}
+	return normalizeLines(cleanDocText(text)), nil
+}
+
+// docPieces 解析 Clx：跳过 Prc，读取 Pcdt 中的 PlcPcd
+func docPieces(word, clx []byte, totalCP uint32) (string, error) {
+	le := binary.LittleEndian
+	i := 0
+	for i < len(clx) && clx[i] == 0x01 {
+		if i+3 > len(clx) {
+			return "", fmt.Errorf("Word文档片段表损坏")
+		}
+		i += 3 + int(le.Uint16(clx[i+1:]))
+	}
+	if i+5 > len(clx) || clx[i] != 0x02 {
+		return "", fmt.Errorf("Word文档缺少片段表")
+	}
+	lcb := int(le.Uint32(clx[i+1:]))
+	plc := clx[i+5:]
+	if lcb > len(plc) || lcb < 4 {
+		return "", fmt.Errorf("Word文档片段表损坏")
+	}
+	plc = plc[:lcb]
+
+	// PlcPcd：n+1 个 CP，随后 n 个 8 字节的 Pcd
+	n := (lcb - 4) / 12
+	var b strings.Builder
+	for k := 0; k < n; k++ {
+		cpStart := le.Uint32(plc[k*4:])
+		cpEnd := le.Uint32(plc[(k+1)*4:])
+		if cpStart >= totalCP && totalCP > 0 {
+			break
+		}
+		if cpEnd > totalCP && totalCP > 0 {
+			cpEnd = totalCP
+		}
+		if cpEnd <= cpStart {
+			continue
+		}
+		count := int(cpEnd - cpStart)
+		fc := le.Uint32(plc[(n+1)*4+k*8+2:])
+
+		if fc&docCompressedFC != 0 {
+			off := int(fc&^docCompressedFC) / 2
+			if off+count > len(word) {
+				return "", fmt.Errorf("Word文档片段越界")
+			}
+			out, _ := charmap.Windows1252.NewDecoder().Bytes(word[off : off+count])
+			b.Write(out)
+			continue
+		}
+		off := int(fc)
+		if off+count*2 > len(word) {
+			return "", fmt.Errorf("Word文档片段越界")
+		}
+		units := make([]uint16, count)
+		for j := range units {
+			units[j] = le.Uint16(word[off+j*2:])
+		}
+		b.WriteString(string(utf16.Decode(units)))
+	}
+	return b.String(), nil
+}
+
+// cleanDocText 转换 Word 的特殊字符：段落/单元格标记、域代码（只保留域结果）与内嵌对象占位符
+func cleanDocText(s string) string {
+	var b strings.Builder
+	// 域嵌套栈，true 表示处于域指令部分
+	var fields []bool
+	for _, r := range s {
+		switch r {
+		case 0x13:
+			fields = append(fields, true)
+			continue
+		case 0x14:
+			if n := len(fields); n > 0 {
+				fields[n-1] = false
+			}
+			continue
+		case 0x15:
+			if n := len(fields); n > 0 {
+				fields = fields[:n-1]
+			}
+			continue
+		}
+		if n := len(fields); n > 0 && fields[n-1] {
+			continue
+		}
+		switch r {
+		case '\r', 0x0B, 0x0C, 0x0E:
+			b.WriteByte('\n')
+		case 0x07:
+			b.WriteByte('\t')
+		case 0x1E:
+			b.WriteByte('-')
+		case 0x01, 0x02, 0x03, 0x04, 0x05, 0x08, 0x1F:
+		case 0xA0:
+			b.WriteByte(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	// 表格行结束时的单元格标记会留下多余的制表符
+	return strings.ReplaceAll(b.String(), "\t\t", "\t\n")
+}
@@ -0,0 +1,472 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// docxStyle styles.xml 中与结构有关的样式属性
+type docxStyle struct {
+	name     string
+	basedOn  string
+	outline  int // 大纲级别，-1 表示正文
+	numID    string
+	numLevel int
+}
+
+// docxParagraph 一个段落及其结构信息
+type docxParagraph struct {
+	text    strings.Builder
+	styleID string
+	outline int
+	numID   string
+	level   int
+}
+
+// docxReader 按确定的顺序输出 DOCX 内容：正文段落、表格（按行、单元格顺序）、页眉页脚，
+// 标题与列表转换为轻量 markdown，便于模型识别简历的分段
+type docxReader struct {
+	zr        *zip.Reader
+	styles    map[string]docxStyle
+	numFmts   map[string]map[int]string // numId -> 级别 -> numFmt
+	counters  map[string][]int
+	body      []string
+	tables    []string
+	rowCells  []string
+	tableRows []string
+}
+
+var docxHeadingNameRegexp = regexp.MustCompile(`(?i)^(heading|标题)\s*(\d)$`)
+
+func (p *UnifiedResumeParser) parseDocx(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("打开DOCX文件失败: %w", err)
+	}
+	return docxToText(zr)
+}
+
+func docxToText(zr *zip.Reader) (string, error) {
+	r := &docxReader{zr: zr, styles: map[string]docxStyle{}, numFmts: map[string]map[int]string{}, counters: map[string][]int{}}
+	if data, err := readZipEntry(zr, "word/styles.xml"); err == nil {
+		r.loadStyles(data)
+	}
+	if data, err := readZipEntry(zr, "word/numbering.xml"); err == nil {
+		r.loadNumbering(data)
+	}
+
+	document, err := readZipEntry(zr, "word/document.xml")
+	if err != nil {
+		return "", err
+	}
+	if err := r.walk(document, true); err != nil {
+		return "", fmt.Errorf("解析DOCX正文失败: %w", err)
+	}
+
+	sections := []string{strings.Join(r.body, "\n")}
+	sections = append(sections, r.tables...)
+
+	// 页眉页脚按文件名编号排序，内容相同的（首页、奇偶页）只保留一份
+	seen := map[string]bool{}
+	for _, name := range docxHeaderFooterParts(zr) {
+		data, err := readZipEntry(zr, name)
+		if err != nil {
+			continue
+		}
+		part := &docxReader{zr: zr, styles: r.styles, numFmts: r.numFmts, counters: map[string][]int{}}
+		if err := part.walk(data, false); err != nil {
+			continue
+		}
+		text := strings.TrimSpace(strings.Join(append(part.body, part.tables...), "\n"))
+		if text != "" && !seen[text] {
+			seen[text] = true
+			sections = append(sections, text)
+		}
+	}
+
+	var out []string
+	for _, s := range sections {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return normalizeLines(strings.Join(out, "\n\n")), nil
+}
+
+var docxPartRegexp = regexp.MustCompile(`^word/(header|footer)(\d*)\.xml$`)
+
+func docxHeaderFooterParts(zr *zip.Reader) []string {
+	type part struct {
+		kind  string
+		index int
+		name  string
+	}
+	var parts []part
+	for _, f := range zr.File {
+		if m := docxPartRegexp.FindStringSubmatch(f.Name); m != nil {
+			index, _ := strconv.Atoi(m[2])
+			parts = append(parts, part{m[1], index, f.Name})
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		if parts[i].kind != parts[j].kind {
+			return parts[i].kind == "header"
+		}
+		return parts[i].index < parts[j].index
+	})
+	names := make([]string, len(parts))
+	for i, p := range parts {
+		names[i] = p.name
+	}
+	return names
+}
+
+func xmlAttr(e xml.StartElement, local string) (string, bool) {
+	for _, a := range e.Attr {
+		if a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+func (r *docxReader) loadStyles(data []byte) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var cur *docxStyle
+	var curID string
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "style":
+				curID, _ = xmlAttr(t, "styleId")
+				cur = &docxStyle{outline: -1, numLevel: 0}
+			case "name", "basedOn", "outlineLvl", "numId", "ilvl":
+				if cur == nil {
+					continue
+				}
+				v, _ := xmlAttr(t, "val")
+				switch t.Name.Local {
+				case "name":
+					cur.name = v
+				case "basedOn":
+					cur.basedOn = v
+				case "outlineLvl":
+					if n, err := strconv.Atoi(v); err == nil {
+						cur.outline = n
+					}
+				case "numId":
+					cur.numID = v
+				case "ilvl":
+					cur.numLevel, _ = strconv.Atoi(v)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "style" && cur != nil {
+				r.styles[curID] = *cur
+				cur = nil
+			}
+		}
+	}
+}
+
+// loadNumbering 读取列表编号格式，用于区分项目符号列表与有序列表
+func (r *docxReader) loadNumbering(data []byte) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	abstract := map[string]map[int]string{}
+	numToAbstract := map[string]string{}
+	var absID, numID string
+	level := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		t, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch t.Name.Local {
+		case "abstractNum":
+			absID, _ = xmlAttr(t, "abstractNumId")
+			numID = ""
+			abstract[absID] = map[int]string{}
+		case "lvl":
+			v, _ := xmlAttr(t, "ilvl")
+			level, _ = strconv.Atoi(v)
+		case "numFmt":
+			if v, ok := xmlAttr(t, "val"); ok && numID == "" && abstract[absID] != nil {
+				abstract[absID][level] = v
+			}
+		case "num":
+			numID, _ = xmlAttr(t, "numId")
+		case "abstractNumId":
+			if v, ok := xmlAttr(t, "val"); ok && numID != "" {
+				numToAbstract[numID] = v
+			}
+		}
+	}
+	for num, abs := range numToAbstract {
+		r.numFmts[num] = abstract[abs]
+	}
+}
+
+// style 沿 basedOn 继承链解析段落样式
+func (r *docxReader) style(id string) docxStyle {
+	s := docxStyle{outline: -1}
+	for i := 0; id != "" && i < 10; i++ {
+		st, ok := r.styles[id]
+		if !ok {
+			break
+		}
+		if s.name == "" {
+			s.name = st.name
+		}
+		if s.outline < 0 {
+			s.outline = st.outline
+		}
+		if s.numID == "" && st.numID != "" {
+			s.numID, s.numLevel = st.numID, st.numLevel
+		}
+		id = st.basedOn
+	}
+	return s
+}
+
+// walk 流式遍历 document.xml/页眉页脚，正文段落与表格分别收集；
+// separateTables 为 false 时表格与段落一起按文档顺序输出
+func (r *docxReader) walk(data []byte, separateTables bool) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var paras []*docxParagraph
+	tableDepth := 0
+	skipDepth := 0 // mc:Fallback、w:del 等重复或已删除的内容
+	var cell []string
+	var stack []string // 当前元素路径（本地名）
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			if skipDepth > 0 {
+				skipDepth++
+				continue
+			}
+			switch t.Name.Local {
+			case "Fallback", "del", "instrText", "delText":
+				skipDepth = 1
+				continue
+			}
+			var cur *docxParagraph
+			if n := len(paras); n > 0 {
+				cur = paras[n-1]
+			}
+			switch t.Name.Local {
+			case "p":
+				paras = append(paras, &docxParagraph{outline: -1, level: -1})
+			case "pStyle":
+				if cur != nil {
+					cur.styleID, _ = xmlAttr(t, "val")
+				}
+			case "outlineLvl":
+				if cur != nil {
+					v, _ := xmlAttr(t, "val")
+					if n, err := strconv.Atoi(v); err == nil {
+						cur.outline = n
+					}
+				}
+			case "numId":
+				if cur != nil {
+					cur.numID, _ = xmlAttr(t, "val")
+				}
+			case "ilvl":
+				if cur != nil {
+					v, _ := xmlAttr(t, "val")
+					cur.level, _ = strconv.Atoi(v)
+				}
+			case "tab":
+				// 段落属性中的 w:tabs/w:tab 是制表位定义，不是字符
+				if cur != nil && !inElement(stack, "pPr") {
+					cur.text.WriteString("\t")
+				}
+			case "br", "cr":
+				if cur != nil {
+					cur.text.WriteString("\n")
+				}
+			case "noBreakHyphen":
+				if cur != nil {
+					cur.text.WriteString("-")
+				}
+			case "tbl":
+				tableDepth++
+				if tableDepth == 1 {
+					r.tableRows = nil
+				}
+			case "tr":
+				if tableDepth == 1 {
+					r.rowCells = nil
+				}
+			case "tc":
+				if tableDepth == 1 {
+					cell = nil
+				}
+			}
+		case xml.EndElement:
+			if n := len(stack); n > 0 {
+				stack = stack[:n-1]
+			}
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				n := len(paras)
+				if n == 0 {
+					continue
+				}
+				para := paras[n-1]
+				paras = paras[:n-1]
+				line := r.markdown(para)
+				if line == "" {
+					continue
+				}
+				if tableDepth > 0 {
+					cell = append(cell, line)
+				} else {
+					r.body = append(r.body, line)
+				}
+			case "tc":
+				if tableDepth == 1 {
+					r.rowCells = append(r.rowCells, strings.Join(cell, " "))
+				}
+			case "tr":
+				if tableDepth == 1 {
+					if row := docxTableRow(r.rowCells); row != "" {
+						r.tableRows = append(r.tableRows, row)
+					}
+				}
+			case "tbl":
+				tableDepth--
+				if tableDepth == 0 && len(r.tableRows) > 0 {
+					table := strings.Join(r.tableRows, "\n")
+					if separateTables {
+						r.tables = append(r.tables, table)
+					} else {
+						r.body = append(r.body, table)
+					}
+				}
+			}
+		case xml.CharData:
+			if skipDepth > 0 {
+				continue
+			}
+			// 只有 w:t 中的字符数据是正文
+			if n := len(paras); n > 0 && len(stack) > 0 && stack[len(stack)-1] == "t" {
+				paras[n-1].text.Write(t)
+			}
+		}
+	}
+}
+
+func inElement(stack []string, local string) bool {
+	for _, name := range stack {
+		if name == local {
+			return true
+		}
+	}
+	return false
+}
+
+// docxTableRow 把一行单元格转换为 markdown 表格行，连续合并产生的重复单元格只保留一个
+func docxTableRow(cells []string) string {
+	var kept []string
+	empty := true
+	for i, c := range cells {
+		c = strings.TrimSpace(strings.ReplaceAll(c, "|", "｜"))
+		if i > 0 && c != "" && c == strings.TrimSpace(cells[i-1]) {
+			continue
+		}
+		if c != "" {
+			empty = false
+		}
+		kept = append(kept, c)
+	}
+	if empty {
+		return ""
+	}
+	return "| " + strings.Join(kept, " | ") + " |"
+}
+
+// markdown 把段落转换为一行文本：标题加 #，列表项按级别缩进并加 "- " 或序号
+func (r *docxReader) markdown(para *docxParagraph) string {
+	text := strings.TrimSpace(strings.ReplaceAll(para.text.String(), "\n", " "))
+	if text == "" {
+		return ""
+	}
+
+	st := r.style(para.styleID)
+	outline := para.outline
+	if outline < 0 {
+		outline = st.outline
+	}
+	if outline < 0 {
+		if m := docxHeadingNameRegexp.FindStringSubmatch(st.name); m != nil {
+			n, _ := strconv.Atoi(m[2])
+			outline = n - 1
+		} else if strings.EqualFold(st.name, "Title") {
+			outline = 0
+		}
+	}
+	if outline >= 0 && outline < 6 {
+		return strings.Repeat("#", outline+1) + " " + text
+	}
+
+	numID, level := para.numID, para.level
+	if numID == "" {
+		numID = st.numID
+		if level < 0 {
+			level = st.numLevel
+		}
+	}
+	if level < 0 {
+		level = 0
+	}
+	if numID == "" || numID == "0" {
+		return text
+	}
+
+	indent := strings.Repeat("  ", level)
+	format := r.numFmts[numID][level]
+	if format == "" || format == "bullet" || format == "none" {
+		return indent + "- " + text
+	}
+	counters := r.counters[numID]
+	for len(counters) <= level {
+		counters = append(counters, 0)
+	}
+	counters[level]++
+	for i := level + 1; i < len(counters); i++ {
+		counters[i] = 0
+	}
+	r.counters[numID] = counters
+	return indent + strconv.Itoa(counters[level]) + ". " + text
+}
@@ -0,0 +1,432 @@
+package parser
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"hr-api/pkg/client"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// 中国大陆手机号，允许 +86 前缀与 3-4-4 分隔
+	mobilePattern = regexp.MustCompile(`(?:\+?86[\s\-]?)?(1[3-9]\d)[\s\-]?(\d{4})[\s\-]?(\d{4})`)
+
+	nameLabelPattern   = regexp.MustCompile(`姓\s*名\s*[:：]\s*([^\s|｜,，]+)`)
+	genderLabelPattern = regexp.MustCompile(`性\s*别\s*[:：]?\s*(男|女)`)
+	ageLabelPattern    = regexp.MustCompile(`年\s*龄\s*[:：]?\s*(\d{2})`)
+	agePattern         = regexp.MustCompile(`(\d{2})\s*岁`)
+	birthPattern       = regexp.MustCompile(`(?:出生(?:日期|年月)?|生日)\s*[:：]?\s*((?:19|20)\d{2})`)
+	yoePattern         = regexp.MustCompile(`(\d{1,2})\s*年(?:以上)?(?:的)?(?:工作|相关|开发|从业)?经验`)
+	yoeLabelPattern    = regexp.MustCompile(`工作(?:年限|经验)\s*[:：]\s*(\d{1,2})\s*年`)
+
+	// 日期区间，如 2019.07 - 至今、2016年9月～2020年6月、2015/03-2018/12
+	dateRangePattern = regexp.MustCompile(`((?:19|20)\d{2})\s*(?:[./\-年]\s*(\d{1,2})\s*月?)?\s*(?:-|–|—|~|～|至|到)+\s*(?:((?:19|20)\d{2})\s*(?:[./\-年]\s*(\d{1,2})\s*月?)?|(至今|今|现在|[Pp]resent|[Nn]ow))`)
+	// 时长等括号内容，如 (3年)、（2年5个月）
+	parenPattern = regexp.MustCompile(`[(（][^)）]*[)）]`)
+)
+
+// headerLines 姓名、性别、年龄等基本信息只在简历开头的若干行中查找
+const headerLines = 15
+
+var (
+	companyKeywords = []string{"公司", "集团", "有限", "科技", "银行", "研究院", "事务所", "工作室", "Inc", "Ltd", "LLC", "Co.", "Corp"}
+	schoolKeywords  = []string{"大学", "学院", "学校", "中学", "University", "College", "Institute", "School"}
+	// degreeKeywords 按优先级排列，value 为规范化后的学历
+	degreeKeywords = []struct{ keyword, degree string }{
+		{"博士", "博士"}, {"硕士", "硕士"}, {"研究生", "硕士"}, {"MBA", "硕士"},
+		{"本科", "本科"}, {"学士", "本科"}, {"大专", "大专"}, {"专科", "大专"},
+		{"中专", "中专"}, {"高中", "高中"},
+	}
+	// nameStopWords 简历开头常见的非姓名短语
+	nameStopWords = map[string]bool{
+		"个人简历": true, "简历": true, "基本信息": true, "个人信息": true, "求职意向": true,
+		"联系方式": true, "自我评价": true, "工作经历": true, "教育经历": true, "男": true, "女": true,
+	}
+)
+
+// resumeSection 日期区间所在的简历分区
+type resumeSection int
+
+const (
+	sectionNone resumeSection = iota
+	sectionWork
+	sectionEducation
+	sectionProject
+)
+
+// ExtractFields 基于规则从简历文本中提取姓名、联系方式、年龄性别、教育与工作经历、工作年限，
+// 不依赖大模型，返回标记为 client.SourceRules 的部分分析结果
+func ExtractFields(text string) *client.ResumeAnalysis {
+	lines := splitLines(text)
+	header := headerOf(lines)
+
+	work, education := extractExperience(lines)
+
+	return &client.ResumeAnalysis{
+		PersonalInfo: client.PersonalInfo{
+			Name:   extractName(header),
+			Email:  emailPattern.FindString(text),
+			Phone:  ExtractPhone(text),
+			Links:  []string{},
+			Age:    extractAge(header),
+			Gender: extractGender(header),
+		},
+		WorkExperience: work,
+		Education:      education,
+		Skills: client.Skills{
+			Technical:      []string{},
+			Soft:           []string{},
+			Languages:      []client.Language{},
+			Certifications: []string{},
+		},
+		Analysis: client.JobAnalysis{
+			Strengths:       []string{},
+			Weaknesses:      []string{},
+			Recommendations: []string{},
+		},
+		Metadata: client.AnalysisMetadata{
+			AnalysisDate: time.Now().Format("2006-01-02"),
+			WordCount:    utf8.RuneCountInString(text),
+			EstimatedYOE: extractYOE(text, lines),
+			Source:       client.SourceRules,
+		},
+	}
+}
+
+// ExtractLabeledName 返回简历开头“姓名：”标签后的姓名，没有标签时返回空。
+// ExtractFields 在没有标签时猜测的姓名可能是职位等其他词，不能据此脱敏或覆盖模型结果
+func ExtractLabeledName(text string) string {
+	return labeledName(headerOf(splitLines(text)))
+}
+
+func splitLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return lines
+}
+
+func headerOf(lines []string) []string {
+	if len(lines) > headerLines {
+		return lines[:headerLines]
+	}
+	return lines
+}
+
+// ExtractPhone 返回文本中第一个手机号，去掉 +86 前缀与分隔符
+func ExtractPhone(text string) string {
+	for _, m := range mobilePattern.FindAllStringSubmatchIndex(text, -1) {
+		// 前后紧邻数字的是更长的编号（如身份证号），不是手机号
+		if m[0] > 0 && isASCIIDigit(text[m[0]-1]) {
+			continue
+		}
+		if m[1] < len(text) && isASCIIDigit(text[m[1]]) {
+			continue
+		}
+		return text[m[2]:m[3]] + text[m[4]:m[5]] + text[m[6]:m[7]]
+	}
+	return ""
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// splitFields 按简历中常见的分隔符切分一行
+func splitFields(line string) []string {
+	return strings.FieldsFunc(line, func(r rune) bool {
+		switch r {
+		case '|', '｜', '/', '·', ',', '，', '、', '丨':
+			return true
+		}
+		return unicode.IsSpace(r)
+	})
+}
+
+func isHanWord(s string, min, max int) bool {
+	n := 0
+	for _, r := range s {
+		if !unicode.Is(unicode.Han, r) {
+			return false
+		}
+		n++
+	}
+	return n >= min && n <= max
+}
+
+// extractName 优先使用“姓名：”标签，否则取开头几行中第一个 2-4 个汉字的词（智联导出的“戴先生”也算）
+func extractName(header []string) string {
+	if name := labeledName(header); name != "" {
+		return name
+	}
+	for _, line := range header {
+		for _, field := range splitFields(line) {
+			if isHanWord(field, 2, 4) && !nameStopWords[field] && !strings.HasSuffix(field, "简历") {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+func labeledName(header []string) string {
+	for _, line := range header {
+		if m := nameLabelPattern.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// extractGender 识别“性别：男”或智联、51job 基本信息行中单独的“男/女”
+func extractGender(header []string) string {
+	for _, line := range header {
+		if m := genderLabelPattern.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	for _, line := range header {
+		for _, field := range splitFields(line) {
+			if field == "男" || field == "女" {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+// extractAge 识别“年龄：30”“36岁(1988年5月)”，都没有时按出生年份估算
+func extractAge(header []string) int {
+	for _, pattern := range []*regexp.Regexp{ageLabelPattern, agePattern} {
+		for _, line := range header {
+			if m := pattern.FindStringSubmatch(line); m != nil {
+				if age, _ := strconv.Atoi(m[1]); age >= 16 && age <= 70 {
+					return age
+				}
+			}
+		}
+	}
+	for _, line := range header {
+		if m := birthPattern.FindStringSubmatch(line); m != nil {
+			year, _ := strconv.Atoi(m[1])
+			if age := time.Now().Year() - year; age >= 16 && age <= 70 {
+				return age
+			}
+		}
+	}
+	return 0
+}
+
+// extractYOE 优先使用简历中写明的工作年限，否则按工作经历的时间区间合并计算
+func extractYOE(text string, lines []string) int {
+	for _, pattern := range []*regexp.Regexp{yoeLabelPattern, yoePattern} {
+		if m := pattern.FindStringSubmatch(text); m != nil {
+			if yoe, _ := strconv.Atoi(m[1]); yoe <= 60 {
+				return yoe
+			}
+		}
+	}
+
+	var spans [][2]int
+	section := sectionNone
+	for _, line := range lines {
+		if s, ok := sectionHeading(line); ok {
+			section = s
+			continue
+		}
+		if section == sectionEducation || section == sectionProject {
+			continue
+		}
+		for _, r := range dateRangePattern.FindAllStringSubmatch(line, -1) {
+			if hasKeyword(line, schoolKeywords) {
+				continue
+			}
+			start, end := rangeMonths(r)
+			if end > start {
+				spans = append(spans, [2]int{start, end})
+			}
+		}
+	}
+	return mergedMonths(spans) / 12
+}
+
+// rangeMonths 将日期区间换算为自公元 0 年起的月数，缺少月份时按 1 月计
+func rangeMonths(m []string) (int, int) {
+	months := func(year, month string) int {
+		y, _ := strconv.Atoi(year)
+		mo, _ := strconv.Atoi(month)
+		if mo < 1 || mo > 12 {
+			mo = 1
+		}
+		return y*12 + mo - 1
+	}
+	start := months(m[1], m[2])
+	if m[5] != "" {
+		now := time.Now()
+		return start, now.Year()*12 + int(now.Month()) - 1
+	}
+	return start, months(m[3], m[4])
+}
+
+// mergedMonths 合并重叠的区间后返回总月数
+func mergedMonths(spans [][2]int) int {
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	total, curStart, curEnd := 0, 0, -1
+	for _, s := range spans {
+		if s[0] > curEnd {
+			if curEnd > curStart {
+				total += curEnd - curStart
+			}
+			curStart, curEnd = s[0], s[1]
+		} else if s[1] > curEnd {
+			curEnd = s[1]
+		}
+	}
+	if curEnd > curStart {
+		total += curEnd - curStart
+	}
+	return total
+}
+
+// sectionHeading 识别“工作经历”“教育背景”等分区标题
+func sectionHeading(line string) (resumeSection, bool) {
+	title := strings.Trim(line, "#*-=：: \t")
+	if title == "" || utf8.RuneCountInString(title) > 12 || dateRangePattern.MatchString(title) {
+		return sectionNone, false
+	}
+	lower := strings.ToLower(title)
+	switch {
+	case strings.Contains(title, "工作经历") || strings.Contains(title, "工作经验") ||
+		strings.Contains(title, "实习经历") || strings.Contains(lower, "work experience"):
+		return sectionWork, true
+	case strings.Contains(title, "教育经历") || strings.Contains(title, "教育背景") ||
+		strings.Contains(lower, "education"):
+		return sectionEducation, true
+	case strings.Contains(title, "项目经历") || strings.Contains(title, "项目经验") ||
+		strings.Contains(lower, "project"):
+		return sectionProject, true
+	}
+	return sectionNone, false
+}
+
+func hasKeyword(s string, keywords []string) bool {
+	for _, k := range keywords {
+		if strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func findDegree(s string) string {
+	for _, d := range degreeKeywords {
+		if strings.Contains(s, d.keyword) {
+			return d.degree
+		}
+	}
+	return ""
+}
+
+func formatMonth(year, month string) string {
+	if month == "" {
+		return year
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	return year + "." + month
+}
+
+// extractExperience 按带日期区间的行识别工作与教育经历：
+// 含学校关键词或位于教育分区的为教育经历，含公司关键词或位于工作分区的为工作经历
+func extractExperience(lines []string) ([]client.WorkExperience, []client.Education) {
+	work := []client.WorkExperience{}
+	education := []client.Education{}
+
+	section := sectionNone
+	for i, line := range lines {
+		if s, ok := sectionHeading(line); ok {
+			section = s
+			continue
+		}
+		loc := dateRangePattern.FindStringSubmatchIndex(line)
+		if loc == nil || section == sectionProject {
+			continue
+		}
+		m := dateRangePattern.FindStringSubmatch(line)
+		rest := parenPattern.ReplaceAllString(line[:loc[0]]+" "+line[loc[1]:], " ")
+		fields := splitFields(rest)
+
+		var next string
+		if i+1 < len(lines) && !dateRangePattern.MatchString(lines[i+1]) {
+			if _, heading := sectionHeading(lines[i+1]); !heading {
+				next = lines[i+1]
+			}
+		}
+
+		end := "至今"
+		if m[5] == "" {
+			end = formatMonth(m[3], m[4])
+		}
+
+		switch {
+		case hasKeyword(rest, schoolKeywords) || (section == sectionEducation && !hasKeyword(rest, companyKeywords)):
+			edu := client.Education{GraduationYear: m[3]}
+			for _, field := range fields {
+				switch {
+				case edu.Institution == "" && hasKeyword(field, schoolKeywords):
+					edu.Institution = field
+				case findDegree(field) != "":
+					if edu.Degree == "" {
+						edu.Degree = findDegree(field)
+					}
+				case edu.Field == "":
+					edu.Field = field
+				}
+			}
+			if edu.Institution == "" && edu.Field != "" {
+				edu.Institution, edu.Field = edu.Field, ""
+			}
+			if edu.Degree == "" {
+				edu.Degree = findDegree(next)
+			}
+			if edu.Institution != "" {
+				education = append(education, edu)
+			}
+		case hasKeyword(rest, companyKeywords) || section == sectionWork:
+			exp := client.WorkExperience{
+				Duration:         formatMonth(m[1], m[2]) + "-" + end,
+				Responsibilities: []string{},
+				Achievements:     []string{},
+			}
+			for _, field := range fields {
+				switch {
+				case exp.Company == "" && hasKeyword(field, companyKeywords):
+					exp.Company = field
+				case exp.Position == "":
+					exp.Position = field
+				}
+			}
+			if exp.Company == "" && exp.Position != "" {
+				exp.Company, exp.Position = exp.Position, ""
+			}
+			// 智联、51job 的职位通常在公司下一行，如“高级开发工程师 | 月薪...”
+			if exp.Position == "" && next != "" && !strings.ContainsAny(next, ":：") {
+				if nextFields := splitFields(next); len(nextFields) > 0 && utf8.RuneCountInString(nextFields[0]) <= 15 {
+					exp.Position = nextFields[0]
+				}
+			}
+			if exp.Company != "" {
+				work = append(work, exp)
+			}
+		}
+	}
+	return work, education
+}
@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// 不包含正文的 HTML 元素，其内容整体忽略
+var htmlSkipTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "title": true, "head": true,
+}
+
+// 块级元素前后换行
+var htmlBlockTags = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "ul": true, "ol": true,
+	"table": true, "tr": true, "dl": true, "dt": true, "dd": true, "blockquote": true, "pre": true,
+	"hr": true, "form": true, "fieldset": true, "address": true,
+}
+
+var metaCharsetRegexp = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?([\w-]+)`)
+
+func (p *UnifiedResumeParser) parseHTML(data []byte) (string, error) {
+	return htmlToText(decodeHTML(data, "")), nil
+}
+
+// decodeHTML 按 Content-Type 或页面 meta 中声明的字符集解码
+func decodeHTML(data []byte, charset string) string {
+	if charset == "" {
+		head := data
+		if len(head) > 4096 {
+			head = head[:4096]
+		}
+		if m := metaCharsetRegexp.FindSubmatch(head); m != nil {
+			charset = string(m[1])
+		}
+	}
+	return decodeText(data, charset)
+}
+
+// htmlToText 把 HTML 转换为纯文本：块级元素换行，表格单元格用制表符分隔，列表项加 "- " 前缀
+func htmlToText(doc string) string {
+	z := html.NewTokenizer(strings.NewReader(doc))
+	var b []byte
+	skip := 0
+	pre := 0
+
+	newline := func() {
+		if len(b) > 0 && b[len(b)-1] != '\n' {
+			b = append(b, '\n')
+		}
+	}
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return normalizeHTMLLines(string(b))
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			// 缺少 </head> 的页面以 <body> 为准结束跳过
+			if tag == "body" {
+				skip = 0
+			}
+			if htmlSkipTags[tag] {
+				if tt == html.StartTagToken {
+					skip++
+				} else if tt == html.EndTagToken && skip > 0 {
+					skip--
+				}
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			switch {
+			case tag == "br":
+				b = append(b, '\n')
+			case tag == "pre":
+				newline()
+				if tt == html.StartTagToken {
+					pre++
+				} else if tt == html.EndTagToken && pre > 0 {
+					pre--
+				}
+			case tag == "li":
+				newline()
+				if tt == html.StartTagToken {
+					b = append(b, "- "...)
+				}
+			case (tag == "td" || tag == "th") && tt == html.EndTagToken:
+				b = bytes.TrimRight(b, "\n ")
+				b = append(b, '\t')
+			case htmlBlockTags[tag]:
+				newline()
+			}
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := string(z.Text())
+			if pre == 0 {
+				text = collapseSpaces(text)
+				// 行首不保留折叠后的空格
+				if strings.HasPrefix(text, " ") && (len(b) == 0 || b[len(b)-1] == '\n' || b[len(b)-1] == '\t' || b[len(b)-1] == ' ') {
+					text = text[1:]
+				}
+			}
+			b = append(b, text...)
+		}
+	}
+}
+
+// collapseSpaces 按 HTML 规则把连续空白折叠为一个空格，&nbsp; 同样视为空格
+func collapseSpaces(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == ' ' {
+			if !space {
+				sb.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func normalizeHTMLLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimLeft(line, " ")
+	}
+	return normalizeLines(strings.Join(lines, "\n"))
+}
+
+// parseMHT 解析 MHT/MHTML（MIME 封装的网页），取第一个 text/html 部分
+func (p *UnifiedResumeParser) parseMHT(data []byte) (string, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("解析MHT文件失败: %w", err)
+	}
+	body, charset, err := findHTMLPart(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return "", err
+	}
+	return htmlToText(decodeHTML(body, charset)), nil
+}
+
+// findHTMLPart 在 MIME 结构中递归查找 HTML 正文，返回解码后的字节与声明的字符集
+func findHTMLPart(contentType, transferEncoding string, body io.Reader) ([]byte, string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/html"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, "", fmt.Errorf("解析MHT分段失败: %w", err)
+			}
+			data, charset, err := findHTMLPart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err == nil {
+				return data, charset, nil
+			}
+		}
+		return nil, "", fmt.Errorf("MHT文件中没有HTML内容")
+	}
+	if mediaType != "text/html" {
+		return nil, "", fmt.Errorf("MHT文件中没有HTML内容")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		// base64 解码器会忽略换行
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("读取MHT内容失败: %w", err)
+	}
+	return data, params["charset"], nil
+}
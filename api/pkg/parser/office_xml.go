@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// readZipEntry 读取 zip 包中的单个文件
+func readZipEntry(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("文件中缺少 %s", name)
+}
+
+// xmlTextRules 描述如何把 XML 元素转换为纯文本，键为元素本地名
+type xmlTextRules struct {
+	text       map[string]bool   // 其字符数据属于正文的元素，为空表示所有元素
+	breaks     map[string]string // 元素结束时追加的分隔符，如段落换行、单元格制表符
+	empties    map[string]string // 空元素代表的字符，如制表符、换行
+	repeatAttr string            // 空元素的重复次数属性，如 ODF 的 text:s 的 c 属性
+}
+
+// xmlToText 按规则把 XML 文档流式转换为纯文本
+func xmlToText(data []byte, rules xmlTextRules) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var b []byte
+	depth := 0 // 位于正文元素内的层数
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("解析XML失败: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if rules.text[t.Name.Local] {
+				depth++
+			}
+			if s, ok := rules.empties[t.Name.Local]; ok {
+				n := 1
+				for _, a := range t.Attr {
+					if a.Name.Local == rules.repeatAttr {
+						if v, err := strconv.Atoi(a.Value); err == nil && v > 0 && v < 100 {
+							n = v
+						}
+					}
+				}
+				b = append(b, strings.Repeat(s, n)...)
+			}
+		case xml.EndElement:
+			if rules.text[t.Name.Local] {
+				depth--
+			}
+			if s, ok := rules.breaks[t.Name.Local]; ok {
+				// 单元格内的段落换行改为单元格分隔，使表格的一行保持在同一行
+				if s == "\t" {
+					b = bytes.TrimRight(b, "\n")
+				}
+				b = append(b, s...)
+			}
+		case xml.CharData:
+			if len(rules.text) == 0 || depth > 0 {
+				b = append(b, t...)
+			}
+		}
+	}
+	return normalizeLines(string(b)), nil
+}
+
+var blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+
+// normalizeLines 去掉行尾空白并合并多余空行
+func normalizeLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimSpace(blankLinesRegexp.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// parseODT 解析 OpenDocument 文本：content.xml 中的段落、标题、列表与表格
+func (p *UnifiedResumeParser) parseODT(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("打开ODT文件失败: %w", err)
+	}
+
+	content, err := readZipEntry(zr, "content.xml")
+	if err != nil {
+		return "", err
+	}
+	return xmlToText(content, xmlTextRules{
+		text: map[string]bool{"p": true, "h": true},
+		breaks: map[string]string{
+			"p": "\n", "h": "\n", "table-cell": "\t", "table-row": "\n", "table": "\n",
+		},
+		empties:    map[string]string{"s": " ", "tab": "\t", "line-break": "\n"},
+		repeatAttr: "c",
+	})
+}
+
+var slideNameRegexp = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+
+// parsePPTX 按幻灯片编号顺序提取每页中的文本框与表格文字
+func (p *UnifiedResumeParser) parsePPTX(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("打开PPTX文件失败: %w", err)
+	}
+
+	type slide struct {
+		index int
+		name  string
+	}
+	var slides []slide
+	for _, f := range zr.File {
+		if m := slideNameRegexp.FindStringSubmatch(f.Name); m != nil {
+			index, _ := strconv.Atoi(m[1])
+			slides = append(slides, slide{index, f.Name})
+		}
+	}
+	if len(slides) == 0 {
+		return "", fmt.Errorf("PPTX文件中没有幻灯片")
+	}
+	sort.Slice(slides, func(i, j int) bool { return slides[i].index < slides[j].index })
+
+	var pages []string
+	for _, s := range slides {
+		slideXML, err := readZipEntry(zr, s.name)
+		if err != nil {
+			return "", err
+		}
+		text, err := xmlToText(slideXML, xmlTextRules{
+			text:    map[string]bool{"t": true},
+			breaks:  map[string]string{"p": "\n", "tc": "\t", "tr": "\n"},
+			empties: map[string]string{"br": "\n"},
+		})
+		if err != nil {
+			return "", fmt.Errorf("解析幻灯片%d失败: %w", s.index, err)
+		}
+		if text != "" {
+			pages = append(pages, text)
+		}
+	}
+	return strings.Join(pages, "\n\n"), nil
+}
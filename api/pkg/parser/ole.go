@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+)
+
+var oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+const (
+	oleEndOfChain = 0xFFFFFFFE
+	oleFreeSector = 0xFFFFFFFF
+	oleMaxSectors = 1 << 22 // 防止损坏文件导致的死循环
+)
+
+// oleFile 只读的 OLE 复合文档（.doc/.xls 的容器格式），按名称读取流
+type oleFile struct {
+	data           []byte
+	sectorSize     int
+	miniSectorSize int
+	miniCutoff     uint32
+	fat            []uint32
+	miniFAT        []uint32
+	miniStream     []byte
+	entries        []oleEntry
+}
+
+type oleEntry struct {
+	name  string
+	kind  byte // 1 存储，2 流，5 根
+	start uint32
+	size  uint64
+}
+
+func openOLE(data []byte) (*oleFile, error) {
+	if len(data) < 512 || !bytes.Equal(data[:8], oleSignature) {
+		return nil, fmt.Errorf("不是有效的OLE复合文档")
+	}
+	le := binary.LittleEndian
+	// 规范只允许 512/4096 字节扇区与 64 字节短扇区，先校验位移量再计算大小，避免溢出
+	sectorShift, miniSectorShift := le.Uint16(data[0x1E:]), le.Uint16(data[0x20:])
+	if sectorShift != 9 && sectorShift != 12 {
+		return nil, fmt.Errorf("OLE扇区大小不合法: 2^%d", sectorShift)
+	}
+	if miniSectorShift != 6 {
+		return nil, fmt.Errorf("OLE短扇区大小不合法: 2^%d", miniSectorShift)
+	}
+	f := &oleFile{
+		data:           data,
+		sectorSize:     1 << sectorShift,
+		miniSectorSize: 1 << miniSectorShift,
+		miniCutoff:     le.Uint32(data[0x38:]),
+	}
+
+	// DIFAT：文件头中的 109 项，之后按链继续
+	var difat []uint32
+	for i := 0; i < 109; i++ {
+		difat = append(difat, le.Uint32(data[0x4C+i*4:]))
+	}
+	next := le.Uint32(data[0x44:])
+	for n := 0; next != oleEndOfChain && next != oleFreeSector && n < oleMaxSectors; n++ {
+		sector, err := f.sector(next)
+		if err != nil {
+			return nil, err
+		}
+		count := f.sectorSize/4 - 1
+		for i := 0; i < count; i++ {
+			difat = append(difat, le.Uint32(sector[i*4:]))
+		}
+		next = le.Uint32(sector[count*4:])
+	}
+	for _, sid := range difat {
+		if sid == oleFreeSector || sid == oleEndOfChain {
+			continue
+		}
+		sector, err := f.sector(sid)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < f.sectorSize/4; i++ {
+			f.fat = append(f.fat, le.Uint32(sector[i*4:]))
+		}
+	}
+
+	dir, err := f.chain(le.Uint32(data[0x30:]), f.fat, f.sector)
+	if err != nil {
+		return nil, fmt.Errorf("读取OLE目录失败: %w", err)
+	}
+	for i := 0; i+128 <= len(dir); i += 128 {
+		e := dir[i : i+128]
+		nameLen := int(le.Uint16(e[0x40:]))
+		if nameLen > 64 {
+			nameLen = 64
+		}
+		units := make([]uint16, 0, nameLen/2)
+		for j := 0; j+1 < nameLen; j += 2 {
+			if u := le.Uint16(e[j:]); u != 0 {
+				units = append(units, u)
+			}
+		}
+		f.entries = append(f.entries, oleEntry{
+			name:  string(utf16.Decode(units)),
+			kind:  e[0x42],
+			start: le.Uint32(e[0x74:]),
+			size:  le.Uint64(e[0x78:]),
+		})
+	}
+	if len(f.entries) == 0 || f.entries[0].kind != 5 {
+		return nil, fmt.Errorf("OLE目录缺少根节点")
+	}
+
+	miniFAT, err := f.chain(le.Uint32(data[0x3C:]), f.fat, f.sector)
+	if err == nil {
+		for i := 0; i+4 <= len(miniFAT); i += 4 {
+			f.miniFAT = append(f.miniFAT, le.Uint32(miniFAT[i:]))
+		}
+	}
+	root := f.entries[0]
+	if f.miniStream, err = f.chain(root.start, f.fat, f.sector); err != nil {
+		return nil, fmt.Errorf("读取OLE短流失败: %w", err)
+	}
+	return f, nil
+}
+
+func (f *oleFile) sector(sid uint32) ([]byte, error) {
+	off := (int(sid) + 1) * f.sectorSize
+	if sid >= oleMaxSectors || off+f.sectorSize > len(f.data) {
+		return nil, fmt.Errorf("OLE扇区越界: %d", sid)
+	}
+	return f.data[off : off+f.sectorSize], nil
+}
+
+func (f *oleFile) miniSector(sid uint32) ([]byte, error) {
+	off := int(sid) * f.miniSectorSize
+	if sid >= oleMaxSectors || off+f.miniSectorSize > len(f.miniStream) {
+		return nil, fmt.Errorf("OLE短扇区越界: %d", sid)
+	}
+	return f.miniStream[off : off+f.miniSectorSize], nil
+}
+
+// chain 沿分配表读取扇区链
+func (f *oleFile) chain(start uint32, table []uint32, read func(uint32) ([]byte, error)) ([]byte, error) {
+	var out []byte
+	for sid, n := start, 0; sid != oleEndOfChain && sid != oleFreeSector; n++ {
+		if n > len(table) {
+			return nil, fmt.Errorf("OLE扇区链存在循环")
+		}
+		sector, err := read(sid)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sector...)
+		if int(sid) >= len(table) {
+			return nil, fmt.Errorf("OLE扇区链越界: %d", sid)
+		}
+		sid = table[sid]
+	}
+	return out, nil
+}
+
+// Stream 按名称读取流
+func (f *oleFile) Stream(name string) ([]byte, error) {
+	for _, e := range f.entries {
+		if e.kind != 2 || e.name != name {
+			continue
+		}
+		var data []byte
+		var err error
+		if e.size < uint64(f.miniCutoff) {
+			data, err = f.chain(e.start, f.miniFAT, f.miniSector)
+		} else {
+			data, err = f.chain(e.start, f.fat, f.sector)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if uint64(len(data)) > e.size {
+			data = data[:e.size]
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("OLE文档中缺少 %s 流", name)
+}
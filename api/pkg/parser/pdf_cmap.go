@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"unicode/utf16"
+)
+
+// pdfCodespace CMap 的编码空间，逐字节比较上下界
+type pdfCodespace struct {
+	low  []byte
+	high []byte
+}
+
+func (cs pdfCodespace) match(b []byte) bool {
+	if len(b) != len(cs.low) {
+		return false
+	}
+	for i := range b {
+		if b[i] < cs.low[i] || b[i] > cs.high[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// pdfBFRange beginbfrange 中的一个区间，目标为起始 UTF-16 值或逐个列出的数组
+type pdfBFRange struct {
+	low, high uint32
+	size      int
+	dst       []uint16
+	dstArray  [][]uint16
+}
+
+// pdfCMap 解析后的 CMap，既用于 ToUnicode 也用于嵌入的 Encoding CMap 切分编码
+type pdfCMap struct {
+	codespaces []pdfCodespace
+	chars      map[string][]uint16
+	ranges     []pdfBFRange
+}
+
+func codeValue(b []byte) uint32 {
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+	return v
+}
+
+func bytesToUTF16(b []byte) []uint16 {
+	if len(b)%2 == 1 {
+		b = append([]byte{0}, b...)
+	}
+	out := make([]uint16, len(b)/2)
+	for i := range out {
+		out[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return out
+}
+
+// parsePDFCMap 解析 CMap 流中的 codespacerange、bfchar 与 bfrange，
+// 其余 PostScript 语法（如 usecmap、cidrange）直接忽略
+func parsePDFCMap(data []byte) *pdfCMap {
+	cm := &pdfCMap{chars: map[string][]uint16{}}
+
+	_ = forEachPDFOperation(data, func(op pdfOperation) error {
+		args := op.Operands
+		switch op.Operator {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(args); i += 2 {
+				if len(args[i].Bytes) == 0 || len(args[i].Bytes) != len(args[i+1].Bytes) {
+					continue
+				}
+				cm.codespaces = append(cm.codespaces, pdfCodespace{low: args[i].Bytes, high: args[i+1].Bytes})
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(args); i += 2 {
+				if len(args[i].Bytes) == 0 {
+					continue
+				}
+				cm.chars[string(args[i].Bytes)] = bytesToUTF16(args[i+1].Bytes)
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(args); i += 3 {
+				low, high := args[i].Bytes, args[i+1].Bytes
+				if len(low) == 0 || len(low) != len(high) {
+					continue
+				}
+				r := pdfBFRange{low: codeValue(low), high: codeValue(high), size: len(low)}
+				if args[i+2].Array != nil {
+					for _, d := range args[i+2].Array {
+						r.dstArray = append(r.dstArray, bytesToUTF16(d.Bytes))
+					}
+				} else {
+					r.dst = bytesToUTF16(args[i+2].Bytes)
+				}
+				cm.ranges = append(cm.ranges, r)
+			}
+		}
+		return nil
+	})
+
+	return cm
+}
+
+// nextCode 按编码空间从 b 的开头切出一个编码，未定义编码空间时使用 defaultSize
+func (cm *pdfCMap) nextCode(b []byte, defaultSize int) []byte {
+	if cm != nil {
+		for n := 1; n <= 4 && n <= len(b); n++ {
+			for _, cs := range cm.codespaces {
+				if cs.match(b[:n]) {
+					return b[:n]
+				}
+			}
+		}
+	}
+	if defaultSize > len(b) {
+		defaultSize = len(b)
+	}
+	return b[:defaultSize]
+}
+
+// lookup 返回编码对应的 Unicode 文本
+func (cm *pdfCMap) lookup(code []byte) (string, bool) {
+	if cm == nil {
+		return "", false
+	}
+	if dst, ok := cm.chars[string(code)]; ok {
+		return string(utf16.Decode(dst)), true
+	}
+	v := codeValue(code)
+	for _, r := range cm.ranges {
+		if r.size != len(code) || v < r.low || v > r.high {
+			continue
+		}
+		offset := v - r.low
+		if r.dstArray != nil {
+			if int(offset) < len(r.dstArray) {
+				return string(utf16.Decode(r.dstArray[offset])), true
+			}
+			return "", false
+		}
+		if len(r.dst) == 0 {
+			return "", false
+		}
+		dst := append([]uint16(nil), r.dst...)
+		dst[len(dst)-1] += uint16(offset)
+		return string(utf16.Decode(dst)), true
+	}
+	return "", false
+}
+
+func (cm *pdfCMap) empty() bool {
+	return cm == nil || (len(cm.chars) == 0 && len(cm.ranges) == 0)
+}
@@ -0,0 +1,383 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// pdfGlyph 一个字符编码解码后的文本与字宽（千分之一文本空间单位）
+type pdfGlyph struct {
+	text  string
+	width float64
+	space bool // 单字节 32，需要叠加字间距 Tw
+}
+
+// pdfFont 文本提取所需的字体信息：编码切分、Unicode 映射与字宽
+type pdfFont struct {
+	composite bool
+
+	toUnicode *pdfCMap
+
+	// 复合字体（Type0）
+	cmapName     string   // 预定义 CMap 名称，如 Identity-H、UniGB-UCS2-H、GBK-EUC-H
+	encodingCMap *pdfCMap // 嵌入的 Encoding CMap，只用于切分编码
+	dw           float64
+	cidWidths    map[uint32]float64
+
+	// 简单字体
+	table        [256]rune
+	firstChar    int
+	widths       []float64
+	missingWidth float64
+	widthScale   float64 // Type3 字体按 FontMatrix 缩放
+}
+
+// loadPDFFont 读取字体字典，字典损坏时尽量返回可用的默认值而不是报错
+func loadPDFFont(xRefTable *model.XRefTable, obj types.Object) *pdfFont {
+	f := &pdfFont{dw: 1000, widthScale: 1}
+	d, err := xRefTable.DereferenceDict(obj)
+	if err != nil || d == nil {
+		f.table = standardEncodingTable()
+		return f
+	}
+
+	if o, found := d.Find("ToUnicode"); found {
+		if data := pdfStreamContent(xRefTable, o); data != nil {
+			f.toUnicode = parsePDFCMap(data)
+		}
+	}
+
+	subtype := pdfName(xRefTable, d, "Subtype")
+	if subtype == "Type0" {
+		f.loadComposite(xRefTable, d)
+		return f
+	}
+
+	f.loadSimple(xRefTable, d)
+	if subtype == "Type3" {
+		if m, _ := xRefTable.DereferenceArray(d["FontMatrix"]); len(m) >= 1 {
+			if v, err := xRefTable.DereferenceNumber(m[0]); err == nil && v > 0 {
+				f.widthScale = v * 1000
+			}
+		}
+	}
+	return f
+}
+
+func (f *pdfFont) loadComposite(xRefTable *model.XRefTable, d types.Dict) {
+	f.composite = true
+
+	enc, _ := xRefTable.Dereference(d["Encoding"])
+	switch enc := enc.(type) {
+	case types.Name:
+		f.cmapName = enc.Value()
+	case types.StreamDict:
+		if data := pdfStreamContent(xRefTable, enc); data != nil {
+			f.encodingCMap = parsePDFCMap(data)
+		}
+		if name := enc.Dict.NameEntry("CMapName"); name != nil {
+			f.cmapName = *name
+		}
+	}
+	if f.cmapName == "" && f.encodingCMap == nil {
+		f.cmapName = "Identity-H"
+	}
+
+	descendants, _ := xRefTable.DereferenceArray(d["DescendantFonts"])
+	if len(descendants) == 0 {
+		return
+	}
+	cid, err := xRefTable.DereferenceDict(descendants[0])
+	if err != nil || cid == nil {
+		return
+	}
+	if v, err := xRefTable.DereferenceNumber(cid["DW"]); err == nil {
+		f.dw = v
+	}
+
+	// W 数组格式：c [w1 w2 ...] 或 cfirst clast w
+	w, _ := xRefTable.DereferenceArray(cid["W"])
+	f.cidWidths = map[uint32]float64{}
+	for i := 0; i < len(w); {
+		first, err := xRefTable.DereferenceNumber(w[i])
+		if err != nil || i+1 >= len(w) {
+			break
+		}
+		next, _ := xRefTable.Dereference(w[i+1])
+		if arr, ok := next.(types.Array); ok {
+			for j, o := range arr {
+				if v, err := xRefTable.DereferenceNumber(o); err == nil {
+					f.cidWidths[uint32(first)+uint32(j)] = v
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			break
+		}
+		last, err1 := xRefTable.DereferenceNumber(w[i+1])
+		v, err2 := xRefTable.DereferenceNumber(w[i+2])
+		if err1 == nil && err2 == nil && last >= first && last-first < 65536 {
+			for c := uint32(first); c <= uint32(last); c++ {
+				f.cidWidths[c] = v
+			}
+		}
+		i += 3
+	}
+}
+
+func (f *pdfFont) loadSimple(xRefTable *model.XRefTable, d types.Dict) {
+	f.table = standardEncodingTable()
+
+	enc, _ := xRefTable.Dereference(d["Encoding"])
+	var differences types.Array
+	switch enc := enc.(type) {
+	case types.Name:
+		f.table = baseEncodingTable(enc.Value())
+	case types.Dict:
+		if base := enc.NameEntry("BaseEncoding"); base != nil {
+			f.table = baseEncodingTable(*base)
+		}
+		differences, _ = xRefTable.DereferenceArray(enc["Differences"])
+	}
+
+	code := 0
+	for _, o := range differences {
+		o, _ = xRefTable.Dereference(o)
+		switch o := o.(type) {
+		case types.Integer:
+			code = o.Value()
+		case types.Float:
+			code = int(o.Value())
+		case types.Name:
+			if code >= 0 && code < 256 {
+				if r, ok := glyphNameToRune(o.Value()); ok {
+					f.table[code] = r
+				}
+			}
+			code++
+		}
+	}
+
+	if v, err := xRefTable.DereferenceNumber(d["FirstChar"]); err == nil {
+		f.firstChar = int(v)
+	}
+	widths, _ := xRefTable.DereferenceArray(d["Widths"])
+	for _, o := range widths {
+		v, _ := xRefTable.DereferenceNumber(o)
+		f.widths = append(f.widths, v)
+	}
+	if fd, err := xRefTable.DereferenceDict(d["FontDescriptor"]); err == nil && fd != nil {
+		if v, err := xRefTable.DereferenceNumber(fd["MissingWidth"]); err == nil {
+			f.missingWidth = v
+		}
+	}
+}
+
+// decode 把 Tj/TJ 中的字符串按字体编码拆成字形
+func (f *pdfFont) decode(b []byte) []pdfGlyph {
+	var glyphs []pdfGlyph
+	for len(b) > 0 {
+		var code []byte
+		if f.composite {
+			code = f.nextCompositeCode(b)
+		} else {
+			code = b[:1]
+		}
+		b = b[len(code):]
+
+		g := pdfGlyph{space: len(code) == 1 && code[0] == ' '}
+		if text, ok := f.toUnicode.lookup(code); ok {
+			g.text = text
+		} else if f.composite {
+			g.text = f.decodePredefined(code)
+		} else if r := f.table[code[0]]; r != 0 {
+			g.text = string(r)
+		}
+		g.width = f.glyphWidth(code)
+		glyphs = append(glyphs, g)
+	}
+	return glyphs
+}
+
+func (f *pdfFont) nextCompositeCode(b []byte) []byte {
+	if f.encodingCMap != nil && len(f.encodingCMap.codespaces) > 0 {
+		return f.encodingCMap.nextCode(b, 2)
+	}
+	name := f.cmapName
+	switch {
+	case strings.HasPrefix(name, "Identity") || strings.Contains(name, "UCS2"):
+		return fixedCode(b, 2)
+	case strings.Contains(name, "UTF16"):
+		if len(b) >= 4 && b[0] >= 0xD8 && b[0] <= 0xDB {
+			return b[:4]
+		}
+		return fixedCode(b, 2)
+	case strings.Contains(name, "GBK2K"):
+		if b[0] < 0x80 {
+			return b[:1]
+		}
+		if len(b) >= 4 && b[1] >= 0x30 && b[1] <= 0x39 {
+			return b[:4]
+		}
+		return fixedCode(b, 2)
+	default:
+		// EUC、GBK、Big5 等多字节编码，高位为 0 的是单字节
+		if b[0] < 0x80 {
+			return b[:1]
+		}
+		return fixedCode(b, 2)
+	}
+}
+
+func fixedCode(b []byte, n int) []byte {
+	if n > len(b) {
+		n = len(b)
+	}
+	return b[:n]
+}
+
+// decodePredefined 没有 ToUnicode 时按预定义 CMap 的字符集解码；
+// Identity-H 的 CID 无法还原为 Unicode，只能丢弃
+func (f *pdfFont) decodePredefined(code []byte) string {
+	name := f.cmapName
+	var dec *encoding.Decoder
+	switch {
+	case strings.HasPrefix(name, "Identity"):
+		return ""
+	case strings.Contains(name, "UCS2") || strings.Contains(name, "UTF16"):
+		return string(utf16.Decode(bytesToUTF16(code)))
+	case strings.Contains(name, "GBK2K"):
+		dec = simplifiedchinese.GB18030.NewDecoder()
+	case strings.HasPrefix(name, "GB"):
+		dec = simplifiedchinese.GBK.NewDecoder()
+	case strings.HasPrefix(name, "B5") || strings.HasPrefix(name, "ETen") || strings.HasPrefix(name, "HKscs"):
+		dec = traditionalchinese.Big5.NewDecoder()
+	default:
+		return ""
+	}
+	out, err := dec.Bytes(code)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+func (f *pdfFont) glyphWidth(code []byte) float64 {
+	if f.composite {
+		if f.cmapName != "" && !strings.HasPrefix(f.cmapName, "Identity") {
+			return f.dw
+		}
+		if w, ok := f.cidWidths[codeValue(code)]; ok {
+			return w
+		}
+		return f.dw
+	}
+
+	i := int(code[0]) - f.firstChar
+	if i >= 0 && i < len(f.widths) {
+		return f.widths[i] * f.widthScale
+	}
+	if f.missingWidth > 0 {
+		return f.missingWidth * f.widthScale
+	}
+	return 500
+}
+
+func standardEncodingTable() [256]rune {
+	var t [256]rune
+	for i := 32; i < 127; i++ {
+		t[i] = rune(i)
+	}
+	// StandardEncoding 与 ASCII 的差异
+	t['\''] = '’'
+	t['`'] = '‘'
+	return t
+}
+
+func baseEncodingTable(name string) [256]rune {
+	var cm *charmap.Charmap
+	switch name {
+	case "WinAnsiEncoding":
+		cm = charmap.Windows1252
+	case "MacRomanEncoding":
+		cm = charmap.Macintosh
+	default:
+		return standardEncodingTable()
+	}
+	var t [256]rune
+	for i := 32; i < 256; i++ {
+		if r := cm.DecodeByte(byte(i)); r != '�' {
+			t[i] = r
+		}
+	}
+	return t
+}
+
+var pdfGlyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$', "percent": '%',
+	"ampersand": '&', "quotesingle": '\'', "quoteright": '’', "quoteleft": '‘', "parenleft": '(',
+	"parenright": ')', "asterisk": '*', "plus": '+', "comma": ',', "hyphen": '-', "minus": '-',
+	"period": '.', "slash": '/', "zero": '0', "one": '1', "two": '2', "three": '3', "four": '4',
+	"five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9', "colon": ':', "semicolon": ';',
+	"less": '<', "equal": '=', "greater": '>', "question": '?', "at": '@', "bracketleft": '[',
+	"backslash": '\\', "bracketright": ']', "asciicircum": '^', "underscore": '_', "grave": '`',
+	"braceleft": '{', "bar": '|', "braceright": '}', "asciitilde": '~', "bullet": '•',
+	"endash": '–', "emdash": '—', "quotedblleft": '“', "quotedblright": '”', "ellipsis": '…',
+	"fi": 'ﬁ', "fl": 'ﬂ', "ff": 'ﬀ', "ffi": 'ﬃ', "ffl": 'ﬄ', "copyright": '©', "registered": '®',
+	"trademark": '™', "degree": '°', "middot": '·', "periodcentered": '·', "nbspace": ' ',
+}
+
+// glyphNameToRune 按 Adobe Glyph List 的常用子集及 uniXXXX/uXXXX 约定解析字形名
+func glyphNameToRune(name string) (rune, bool) {
+	if r, ok := pdfGlyphNames[name]; ok {
+		return r, true
+	}
+	if len(name) == 1 {
+		return rune(name[0]), true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) >= 7 {
+		if v, err := strconv.ParseUint(name[3:7], 16, 32); err == nil {
+			return rune(v), true
+		}
+	}
+	if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+		if v, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+			return rune(v), true
+		}
+	}
+	return 0, false
+}
+
+// pdfName 读取字典中的名称项（可能是间接引用）
+func pdfName(xRefTable *model.XRefTable, d types.Dict, key string) string {
+	o, err := xRefTable.Dereference(d[key])
+	if err != nil {
+		return ""
+	}
+	if n, ok := o.(types.Name); ok {
+		return n.Value()
+	}
+	return ""
+}
+
+// pdfStreamContent 解码流对象，失败时返回 nil
+func pdfStreamContent(xRefTable *model.XRefTable, o types.Object) []byte {
+	sd, _, err := xRefTable.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+	return sd.Content
+}
@@ -0,0 +1,314 @@
+package parser
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// pdfTokenKind 内容流词法单元类型
+type pdfTokenKind int
+
+const (
+	pdfTokenEOF pdfTokenKind = iota
+	pdfTokenNumber
+	pdfTokenString // 字面量字符串与十六进制字符串，Bytes 为解码后的原始字节
+	pdfTokenName
+	pdfTokenOperator
+	pdfTokenArrayStart
+	pdfTokenArrayEnd
+	pdfTokenDictStart
+	pdfTokenDictEnd
+)
+
+type pdfToken struct {
+	Kind  pdfTokenKind
+	Num   float64
+	Bytes []byte
+	Text  string // 名称（不含 /）或操作符
+}
+
+// pdfOperand 操作数，数组操作数（TJ、W、Differences）保存在 Array 中
+type pdfOperand struct {
+	pdfToken
+	Array []pdfOperand
+}
+
+// pdfLexer 内容流与 CMap 共用的词法分析器，只识别文本提取所需的语法
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func newPDFLexer(data []byte) *pdfLexer {
+	return &pdfLexer{data: data}
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// Next 返回下一个词法单元，读到末尾时返回 pdfTokenEOF
+func (l *pdfLexer) Next() pdfToken {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return pdfToken{Kind: pdfTokenEOF}
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return pdfToken{Kind: pdfTokenString, Bytes: l.readLiteral()}
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfToken{Kind: pdfTokenDictStart}
+		}
+		return pdfToken{Kind: pdfTokenString, Bytes: l.readHex()}
+	case c == '>':
+		l.pos++
+		if l.pos < len(l.data) && l.data[l.pos] == '>' {
+			l.pos++
+		}
+		return pdfToken{Kind: pdfTokenDictEnd}
+	case c == '[':
+		l.pos++
+		return pdfToken{Kind: pdfTokenArrayStart}
+	case c == ']':
+		l.pos++
+		return pdfToken{Kind: pdfTokenArrayEnd}
+	case c == '/':
+		l.pos++
+		return pdfToken{Kind: pdfTokenName, Text: l.readName()}
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.data) {
+			c := l.data[l.pos]
+			if (c >= '0' && c <= '9') || c == '.' {
+				l.pos++
+				continue
+			}
+			break
+		}
+		num, err := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+		if err != nil {
+			return pdfToken{Kind: pdfTokenOperator, Text: string(l.data[start:l.pos])}
+		}
+		return pdfToken{Kind: pdfTokenNumber, Num: num}
+	default:
+		start := l.pos
+		l.pos++
+		if c != '{' && c != '}' && c != ')' {
+			for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+		}
+		return pdfToken{Kind: pdfTokenOperator, Text: string(l.data[start:l.pos])}
+	}
+}
+
+func (l *pdfLexer) readName() string {
+	var buf bytes.Buffer
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) || isPDFDelimiter(c) {
+			break
+		}
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				buf.WriteByte(byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		buf.WriteByte(c)
+		l.pos++
+	}
+	return buf.String()
+}
+
+func (l *pdfLexer) readLiteral() []byte {
+	var buf bytes.Buffer
+	depth := 0
+	l.pos++ // (
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			buf.WriteByte(c)
+		case ')':
+			if depth == 0 {
+				return buf.Bytes()
+			}
+			depth--
+			buf.WriteByte(c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return buf.Bytes()
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case 'b':
+				buf.WriteByte('\b')
+			case 'f':
+				buf.WriteByte('\f')
+			case '\r':
+				// 续行
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					buf.WriteByte(byte(v))
+				} else {
+					buf.WriteByte(e)
+				}
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.Bytes()
+}
+
+func (l *pdfLexer) readHex() []byte {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			break
+		}
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		v, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		out[i] = byte(v)
+	}
+	return out
+}
+
+// SkipInlineImage 跳过 ID 与 EI 之间的内联图像数据
+func (l *pdfLexer) SkipInlineImage() {
+	if l.pos < len(l.data) && isPDFWhitespace(l.data[l.pos]) {
+		l.pos++
+	}
+	for l.pos+2 <= len(l.data) {
+		if l.data[l.pos] == 'E' && l.data[l.pos+1] == 'I' &&
+			(l.pos == 0 || isPDFWhitespace(l.data[l.pos-1])) &&
+			(l.pos+2 == len(l.data) || isPDFWhitespace(l.data[l.pos+2])) {
+			l.pos += 2
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
+
+// pdfOperation 一次操作符调用及其操作数
+type pdfOperation struct {
+	Operator string
+	Operands []pdfOperand
+}
+
+// forEachPDFOperation 逐个回调内容流中的操作符，字典操作数（如 BDC 的属性）会被丢弃
+func forEachPDFOperation(data []byte, fn func(op pdfOperation) error) error {
+	lexer := newPDFLexer(data)
+	var stack []pdfOperand
+	var arrays [][]pdfOperand
+	dictDepth := 0
+
+	push := func(o pdfOperand) {
+		if dictDepth > 0 {
+			return
+		}
+		if len(arrays) > 0 {
+			arrays[len(arrays)-1] = append(arrays[len(arrays)-1], o)
+			return
+		}
+		stack = append(stack, o)
+	}
+
+	for {
+		tok := lexer.Next()
+		switch tok.Kind {
+		case pdfTokenEOF:
+			return nil
+		case pdfTokenArrayStart:
+			arrays = append(arrays, nil)
+		case pdfTokenArrayEnd:
+			if len(arrays) == 0 {
+				continue
+			}
+			arr := arrays[len(arrays)-1]
+			arrays = arrays[:len(arrays)-1]
+			push(pdfOperand{Array: arr, pdfToken: pdfToken{Kind: pdfTokenArrayStart}})
+		case pdfTokenDictStart:
+			dictDepth++
+		case pdfTokenDictEnd:
+			if dictDepth > 0 {
+				dictDepth--
+			}
+		case pdfTokenOperator:
+			if dictDepth > 0 || len(arrays) > 0 {
+				continue
+			}
+			if err := fn(pdfOperation{Operator: tok.Text, Operands: stack}); err != nil {
+				return err
+			}
+			if tok.Text == "ID" {
+				lexer.SkipInlineImage()
+			}
+			stack = nil
+		default:
+			push(pdfOperand{pdfToken: tok})
+		}
+	}
+}
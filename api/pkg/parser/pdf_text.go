@@ -0,0 +1,406 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// 嵌套 Form XObject 的最大深度，防止循环引用
+const maxPDFFormDepth = 8
+
+// pdfMatrix PDF 变换矩阵 [a b c d e f]
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+// mul 返回 m × n
+func (m pdfMatrix) mul(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func pdfTranslate(tx, ty float64) pdfMatrix {
+	return pdfMatrix{1, 0, 0, 1, tx, ty}
+}
+
+// pdfTextSpan 一段连续显示的文本及其在页面上的位置
+type pdfTextSpan struct {
+	x, y, endX float64
+	size       float64
+	text       string
+}
+
+// pdfGraphicsState q/Q 保存与恢复的状态，只保留文本提取需要的部分
+type pdfGraphicsState struct {
+	ctm      pdfMatrix
+	font     *pdfFont
+	fontSize float64
+	tc, tw   float64
+	th       float64
+	tl       float64
+	rise     float64
+}
+
+type pdfTextExtractor struct {
+	xRefTable *model.XRefTable
+	fonts     map[int]*pdfFont
+	spans     []pdfTextSpan
+
+	gs      pdfGraphicsState
+	stack   []pdfGraphicsState
+	tm, tlm pdfMatrix
+}
+
+// extractPDFText 解析 PDF 文本层：解码 ToUnicode CMap 与 CID 字体，处理 TJ 字距，
+// 并按页面位置（自上而下、从左到右）重排文本。pdfcpu 遇到结构损坏的文件可能 panic，转为错误返回
+func extractPDFText(rs io.ReadSeeker) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("PDF 结构损坏: %v", r)
+		}
+	}()
+
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+
+	ctx, err := api.ReadContext(rs, conf)
+	if err != nil {
+		return "", fmt.Errorf("读取 PDF 失败: %w", err)
+	}
+	if err := ctx.EnsurePageCount(); err != nil {
+		return "", fmt.Errorf("读取 PDF 页数失败: %w", err)
+	}
+
+	e := &pdfTextExtractor{xRefTable: ctx.XRefTable, fonts: map[int]*pdfFont{}}
+	var pages []string
+	for i := 1; i <= ctx.PageCount; i++ {
+		text, err := e.extractPage(i)
+		if err != nil {
+			return "", fmt.Errorf("解析第%d页失败: %w", i, err)
+		}
+		if text != "" {
+			pages = append(pages, text)
+		}
+	}
+	return strings.Join(pages, "\n\n"), nil
+}
+
+func (e *pdfTextExtractor) extractPage(pageNr int) (string, error) {
+	pageDict, _, inherited, err := e.xRefTable.PageDict(pageNr, false)
+	if err != nil {
+		return "", err
+	}
+	if pageDict == nil {
+		return "", nil
+	}
+	content, err := e.xRefTable.PageContent(pageDict, pageNr)
+	if err == model.ErrNoContent {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var resources types.Dict
+	if inherited != nil {
+		resources = inherited.Resources
+	}
+
+	e.spans = nil
+	e.stack = nil
+	e.gs = pdfGraphicsState{ctm: pdfIdentity, th: 1}
+	if err := e.run(content, resources, 0); err != nil {
+		return "", err
+	}
+	return layoutPDFSpans(e.spans), nil
+}
+
+func operandNumbers(args []pdfOperand) []float64 {
+	var nums []float64
+	for _, a := range args {
+		if a.Kind == pdfTokenNumber {
+			nums = append(nums, a.Num)
+		}
+	}
+	return nums
+}
+
+// run 解释内容流中与文本相关的操作符
+func (e *pdfTextExtractor) run(content []byte, resources types.Dict, depth int) error {
+	return forEachPDFOperation(content, func(op pdfOperation) error {
+		args := op.Operands
+		nums := operandNumbers(args)
+
+		switch op.Operator {
+		case "q":
+			e.stack = append(e.stack, e.gs)
+		case "Q":
+			if n := len(e.stack); n > 0 {
+				e.gs = e.stack[n-1]
+				e.stack = e.stack[:n-1]
+			}
+		case "cm":
+			if len(nums) == 6 {
+				e.gs.ctm = pdfMatrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}.mul(e.gs.ctm)
+			}
+		case "BT":
+			e.tm, e.tlm = pdfIdentity, pdfIdentity
+		case "Tf":
+			if len(args) == 2 && args[0].Kind == pdfTokenName {
+				e.gs.font = e.font(resources, args[0].Text)
+				e.gs.fontSize = args[1].Num
+			}
+		case "Tc":
+			if len(nums) == 1 {
+				e.gs.tc = nums[0]
+			}
+		case "Tw":
+			if len(nums) == 1 {
+				e.gs.tw = nums[0]
+			}
+		case "Tz":
+			if len(nums) == 1 {
+				e.gs.th = nums[0] / 100
+			}
+		case "TL":
+			if len(nums) == 1 {
+				e.gs.tl = nums[0]
+			}
+		case "Ts":
+			if len(nums) == 1 {
+				e.gs.rise = nums[0]
+			}
+		case "Td":
+			if len(nums) == 2 {
+				e.moveLine(nums[0], nums[1])
+			}
+		case "TD":
+			if len(nums) == 2 {
+				e.gs.tl = -nums[1]
+				e.moveLine(nums[0], nums[1])
+			}
+		case "Tm":
+			if len(nums) == 6 {
+				e.tm = pdfMatrix{nums[0], nums[1], nums[2], nums[3], nums[4], nums[5]}
+				e.tlm = e.tm
+			}
+		case "T*":
+			e.moveLine(0, -e.gs.tl)
+		case "Tj":
+			if len(args) == 1 {
+				e.show(args[0].Bytes)
+			}
+		case "'":
+			e.moveLine(0, -e.gs.tl)
+			if len(args) == 1 {
+				e.show(args[0].Bytes)
+			}
+		case "\"":
+			if len(args) == 3 {
+				e.gs.tw, e.gs.tc = args[0].Num, args[1].Num
+				e.moveLine(0, -e.gs.tl)
+				e.show(args[2].Bytes)
+			}
+		case "TJ":
+			if len(args) == 1 {
+				e.showArray(args[0].Array)
+			}
+		case "Do":
+			if len(args) == 1 && args[0].Kind == pdfTokenName && depth < maxPDFFormDepth {
+				return e.runForm(resources, args[0].Text, depth+1)
+			}
+		}
+		return nil
+	})
+}
+
+func (e *pdfTextExtractor) moveLine(tx, ty float64) {
+	e.tlm = pdfTranslate(tx, ty).mul(e.tlm)
+	e.tm = e.tlm
+}
+
+// showArray TJ 数组中的数字是以千分之一字号为单位的字距调整，正数向左移动
+func (e *pdfTextExtractor) showArray(items []pdfOperand) {
+	for _, item := range items {
+		if item.Kind == pdfTokenNumber {
+			tx := -item.Num / 1000 * e.gs.fontSize * e.gs.th
+			e.tm = pdfTranslate(tx, 0).mul(e.tm)
+			continue
+		}
+		e.show(item.Bytes)
+	}
+}
+
+func (e *pdfTextExtractor) show(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	font := e.gs.font
+	if font == nil {
+		font = &pdfFont{table: standardEncodingTable(), dw: 1000, widthScale: 1}
+	}
+
+	gs := e.gs
+	start := pdfMatrix{gs.fontSize * gs.th, 0, 0, gs.fontSize, 0, gs.rise}.mul(e.tm).mul(gs.ctm)
+	var text strings.Builder
+	for _, g := range font.decode(b) {
+		text.WriteString(g.text)
+		tx := g.width/1000*gs.fontSize + gs.tc
+		if g.space {
+			tx += gs.tw
+		}
+		e.tm = pdfTranslate(tx*gs.th, 0).mul(e.tm)
+	}
+	end := pdfMatrix{1, 0, 0, 1, 0, gs.rise}.mul(e.tm).mul(gs.ctm)
+
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\t' {
+			return -1
+		}
+		return r
+	}, text.String())
+	if s == "" {
+		return
+	}
+
+	size := math.Hypot(start[2], start[3])
+	if size < 1 {
+		size = 1
+	}
+	e.spans = append(e.spans, pdfTextSpan{x: start[4], y: start[5], endX: end[4], size: size, text: s})
+}
+
+// font 按资源名称查找字体，间接引用的字体按对象号缓存
+func (e *pdfTextExtractor) font(resources types.Dict, name string) *pdfFont {
+	fonts, err := e.xRefTable.DereferenceDict(resources["Font"])
+	if err != nil || fonts == nil {
+		return nil
+	}
+	obj, ok := fonts[name]
+	if !ok {
+		return nil
+	}
+	if ref, ok := obj.(types.IndirectRef); ok {
+		nr := ref.ObjectNumber.Value()
+		if f, ok := e.fonts[nr]; ok {
+			return f
+		}
+		f := loadPDFFont(e.xRefTable, obj)
+		e.fonts[nr] = f
+		return f
+	}
+	return loadPDFFont(e.xRefTable, obj)
+}
+
+// runForm 执行 Form XObject，简历模板常把整块内容放在表单中
+func (e *pdfTextExtractor) runForm(resources types.Dict, name string, depth int) error {
+	xObjects, err := e.xRefTable.DereferenceDict(resources["XObject"])
+	if err != nil || xObjects == nil {
+		return nil
+	}
+	sd, _, err := e.xRefTable.DereferenceStreamDict(xObjects[name])
+	if err != nil || sd == nil {
+		return nil
+	}
+	if pdfName(e.xRefTable, sd.Dict, "Subtype") != "Form" {
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return nil
+	}
+
+	formResources, _ := e.xRefTable.DereferenceDict(sd.Dict["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+
+	saved, savedStack, tm, tlm := e.gs, e.stack, e.tm, e.tlm
+	if m, _ := e.xRefTable.DereferenceArray(sd.Dict["Matrix"]); len(m) == 6 {
+		var mat pdfMatrix
+		for i, o := range m {
+			mat[i], _ = e.xRefTable.DereferenceNumber(o)
+		}
+		e.gs.ctm = mat.mul(e.gs.ctm)
+	}
+	err = e.run(sd.Content, formResources, depth)
+	e.gs, e.stack, e.tm, e.tlm = saved, savedStack, tm, tlm
+	return err
+}
+
+// pdfTextLine 基线相近的文本段组成的一行
+type pdfTextLine struct {
+	y     float64
+	size  float64
+	spans []pdfTextSpan
+}
+
+// layoutPDFSpans 按基线把文本段分行，行内按横坐标排序，
+// 间距明显大于字距时补空格，行距明显变大时插入空行
+func layoutPDFSpans(spans []pdfTextSpan) string {
+	if len(spans) == 0 {
+		return ""
+	}
+	sorted := append([]pdfTextSpan(nil), spans...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].y > sorted[j].y })
+
+	var lines []*pdfTextLine
+	for _, s := range sorted {
+		if n := len(lines); n > 0 {
+			line := lines[n-1]
+			if math.Abs(line.y-s.y) <= math.Max(line.size, s.size)*0.5 {
+				line.spans = append(line.spans, s)
+				continue
+			}
+		}
+		lines = append(lines, &pdfTextLine{y: s.y, size: s.size, spans: []pdfTextSpan{s}})
+	}
+
+	var out strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			out.WriteString("\n")
+			if gap := lines[i-1].y - line.y; gap > math.Max(lines[i-1].size, line.size)*2 {
+				out.WriteString("\n")
+			}
+		}
+		out.WriteString(joinLineSpans(line.spans))
+	}
+	return strings.TrimSpace(out.String())
+}
+
+func joinLineSpans(spans []pdfTextSpan) string {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].x < spans[j].x })
+
+	var b strings.Builder
+	var prev *pdfTextSpan
+	for i := range spans {
+		s := &spans[i]
+		if prev != nil {
+			// 伪粗体常把同一段文字错位绘制两次
+			if s.text == prev.text && math.Abs(s.x-prev.x) < s.size*0.3 {
+				continue
+			}
+			gap := s.x - prev.endX
+			if gap > s.size*0.2 && !strings.HasSuffix(prev.text, " ") && !strings.HasPrefix(s.text, " ") {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(s.text)
+		prev = s
+	}
+	return strings.TrimRight(b.String(), " ")
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FormatParser 把某种格式的简历内容解析为纯文本
+type FormatParser func(p *UnifiedResumeParser, data []byte) (string, error)
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]FormatParser{}
+)
+
+// RegisterFormat 按扩展名（如 ".pdf"）注册解析函数，重复注册时覆盖已有的解析函数
+func RegisterFormat(ext string, fn FormatParser) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[strings.ToLower(ext)] = fn
+}
+
+func lookupFormat(ext string) (FormatParser, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	fn, ok := formats[strings.ToLower(ext)]
+	return fn, ok
+}
+
+// SupportedFormats 返回已注册的扩展名，按字母排序
+func SupportedFormats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	exts := make([]string, 0, len(formats))
+	for ext := range formats {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+func init() {
+	RegisterFormat(".pdf", (*UnifiedResumeParser).parsePDF)
+	RegisterFormat(".docx", (*UnifiedResumeParser).parseDocx)
+	RegisterFormat(".txt", (*UnifiedResumeParser).parseText)
+	RegisterFormat(".doc", (*UnifiedResumeParser).parseDoc)
+	RegisterFormat(".odt", (*UnifiedResumeParser).parseODT)
+	RegisterFormat(".pptx", (*UnifiedResumeParser).parsePPTX)
+	RegisterFormat(".rtf", (*UnifiedResumeParser).parseRTF)
+	RegisterFormat(".html", (*UnifiedResumeParser).parseHTML)
+	RegisterFormat(".htm", (*UnifiedResumeParser).parseHTML)
+	RegisterFormat(".mht", (*UnifiedResumeParser).parseMHT)
+	RegisterFormat(".mhtml", (*UnifiedResumeParser).parseMHT)
+}
@@ -0,0 +1,300 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding"
+)
+
+// 不包含正文的 RTF 目标组，整组跳过
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true, "pict": true,
+	"themedata": true, "colorschememapping": true, "latentstyles": true, "datastore": true,
+	"xmlnstbl": true, "listtable": true, "listoverridetable": true, "rsidtbl": true,
+	"generator": true, "mmathPr": true, "fldinst": true, "filetbl": true, "revtbl": true,
+	"userprops": true, "docvar": true, "wgrffmtfilter": true, "pgdsctbl": true,
+	"nonshppict": true, "objdata": true, "falt": true, "panose": true, "bkmkstart": true,
+	"bkmkend": true,
+}
+
+// 直接输出字符的控制字
+var rtfSymbols = map[string]string{
+	"par": "\n", "line": "\n", "sect": "\n", "page": "\n", "row": "\n",
+	"tab": "\t", "cell": "\t", "emdash": "—", "endash": "–", "bullet": "•",
+	"lquote": "‘", "rquote": "’", "ldblquote": "“", "rdblquote": "”",
+	"emspace": " ", "enspace": " ", "qmspace": " ",
+}
+
+// RTF 字体字符集（\fcharset）对应的 Windows 代码页
+var rtfCharsetCodepages = map[int]string{134: "936", 136: "950", 0: "1252"}
+
+type rtfGroupState struct {
+	skip    bool
+	uc      int // \uN 之后需要跳过的替代字符数
+	enc     encoding.Encoding
+	fontTbl bool
+	tblFont int
+}
+
+// rtfReader 把 RTF 控制字流转换为纯文本
+type rtfReader struct {
+	data []byte
+	pos  int
+	out  []rune
+
+	state        rtfGroupState
+	stack        []rtfGroupState
+	groupStart   bool // 刚进入新组，用于识别目标控制字
+	ansi         encoding.Encoding
+	fontCharsets map[int]int
+	pending      []byte // 连续的 \'hh 字节，多字节编码需要合并后再解码
+	skipChars    int
+	highSurr     rune
+}
+
+func (p *UnifiedResumeParser) parseRTF(data []byte) (string, error) {
+	return rtfToText(data)
+}
+
+func rtfToText(data []byte) (string, error) {
+	if len(data) < 5 || string(data[:5]) != "{\\rtf" {
+		return "", fmt.Errorf("不是有效的RTF文件")
+	}
+	r := &rtfReader{data: data, fontCharsets: map[int]int{}, ansi: charsetEncoding("1252")}
+	r.state = rtfGroupState{uc: 1, enc: r.ansi}
+	r.run()
+	return normalizeLines(string(r.out)), nil
+}
+
+func (r *rtfReader) run() {
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		r.pos++
+		switch c {
+		case '{':
+			r.flush()
+			r.stack = append(r.stack, r.state)
+			r.groupStart = true
+			continue
+		case '}':
+			r.flush()
+			if n := len(r.stack); n > 0 {
+				r.state = r.stack[n-1]
+				r.stack = r.stack[:n-1]
+			}
+		case '\\':
+			r.control()
+			continue
+		case '\r', '\n':
+			continue
+		default:
+			r.byteChar(c)
+		}
+		r.groupStart = false
+	}
+	r.flush()
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (r *rtfReader) control() {
+	if r.pos >= len(r.data) {
+		return
+	}
+	c := r.data[r.pos]
+	if !isASCIILetter(c) {
+		r.pos++
+		switch c {
+		case '\'':
+			r.groupStart = false
+			if r.pos+2 <= len(r.data) {
+				if v, err := strconv.ParseUint(string(r.data[r.pos:r.pos+2]), 16, 8); err == nil {
+					r.pos += 2
+					if r.consumeSkip() {
+						return
+					}
+					r.pending = append(r.pending, byte(v))
+					return
+				}
+			}
+		case '*':
+			if r.groupStart {
+				r.state.skip = true
+			}
+		case '~':
+			r.text(" ")
+		case '_':
+			r.text("-")
+		case '\\', '{', '}':
+			r.text(string(c))
+		case '\r', '\n':
+			r.text("\n")
+		}
+		// \* 之后紧跟的控制字仍是目标名称
+		if c == '*' {
+			return
+		}
+		r.groupStart = false
+		return
+	}
+
+	start := r.pos
+	for r.pos < len(r.data) && isASCIILetter(r.data[r.pos]) {
+		r.pos++
+	}
+	word := string(r.data[start:r.pos])
+
+	hasParam := false
+	param := 0
+	numStart := r.pos
+	if r.pos < len(r.data) && r.data[r.pos] == '-' {
+		r.pos++
+	}
+	for r.pos < len(r.data) && r.data[r.pos] >= '0' && r.data[r.pos] <= '9' {
+		r.pos++
+	}
+	if r.pos > numStart {
+		if v, err := strconv.Atoi(string(r.data[numStart:r.pos])); err == nil {
+			hasParam, param = true, v
+		}
+	}
+	if r.pos < len(r.data) && r.data[r.pos] == ' ' {
+		r.pos++
+	}
+
+	r.word(word, hasParam, param)
+	r.groupStart = false
+}
+
+func (r *rtfReader) word(word string, hasParam bool, param int) {
+	if word != "u" {
+		r.flush()
+	}
+	if r.groupStart && rtfSkipDestinations[word] {
+		r.state.skip = true
+	}
+	if word == "fonttbl" {
+		r.state.fontTbl = true
+	}
+
+	switch word {
+	case "bin":
+		// 二进制数据直接跳过
+		if hasParam && param > 0 {
+			r.pos += param
+		}
+	case "ansicpg":
+		if enc := charsetEncoding(strconv.Itoa(param)); enc != nil {
+			r.ansi = enc
+			r.state.enc = enc
+		}
+	case "f":
+		if r.state.fontTbl {
+			r.state.tblFont = param
+		} else if cs, ok := r.fontCharsets[param]; ok {
+			r.state.enc = r.charsetEncoding(cs)
+		} else {
+			r.state.enc = r.ansi
+		}
+	case "fcharset":
+		if r.state.fontTbl {
+			r.fontCharsets[r.state.tblFont] = param
+		}
+	case "uc":
+		if hasParam && param >= 0 {
+			r.state.uc = param
+		}
+	case "u":
+		if param < 0 {
+			param += 65536
+		}
+		r.unicode(rune(param))
+		r.skipChars = r.state.uc
+	default:
+		if s, ok := rtfSymbols[word]; ok {
+			if !r.consumeSkip() {
+				r.text(s)
+			}
+		}
+	}
+}
+
+func (r *rtfReader) charsetEncoding(charset int) encoding.Encoding {
+	if cp, ok := rtfCharsetCodepages[charset]; ok {
+		if charset == 0 {
+			return r.ansi
+		}
+		return charsetEncoding(cp)
+	}
+	return r.ansi
+}
+
+// consumeSkip 处理 \uN 之后的替代字符，返回 true 表示当前字符被跳过
+func (r *rtfReader) consumeSkip() bool {
+	if r.skipChars > 0 {
+		r.skipChars--
+		return true
+	}
+	return false
+}
+
+func (r *rtfReader) byteChar(c byte) {
+	if r.consumeSkip() {
+		return
+	}
+	if c >= 0x80 {
+		r.pending = append(r.pending, c)
+		return
+	}
+	r.flush()
+	r.text(string(c))
+}
+
+func (r *rtfReader) unicode(u rune) {
+	if r.state.skip {
+		return
+	}
+	if utf16.IsSurrogate(u) {
+		if u < 0xDC00 {
+			r.highSurr = u
+			return
+		}
+		if r.highSurr != 0 {
+			u = utf16.DecodeRune(r.highSurr, u)
+			r.highSurr = 0
+		}
+	}
+	r.out = append(r.out, u)
+}
+
+// flush 按当前字体的字符集解码累积的 \'hh 字节
+func (r *rtfReader) flush() {
+	if len(r.pending) == 0 {
+		return
+	}
+	data := r.pending
+	r.pending = nil
+	if r.state.skip {
+		return
+	}
+	enc := r.state.enc
+	if enc == nil {
+		enc = r.ansi
+	}
+	out, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return
+	}
+	r.out = append(r.out, []rune(string(out))...)
+}
+
+func (r *rtfReader) text(s string) {
+	if r.state.skip {
+		return
+	}
+	r.out = append(r.out, []rune(s)...)
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"mime"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// 内容类型到扩展名的映射，仅在魔数无法识别时使用
+var contentTypeFormats = map[string]string{
+	"application/pdf":    ".pdf",
+	"application/msword": ".doc",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+	"application/vnd.oasis.opendocument.text":                                   ".odt",
+	"application/rtf":           ".rtf",
+	"text/rtf":                  ".rtf",
+	"text/html":                 ".html",
+	"message/rfc822":            ".mht",
+	"multipart/related":         ".mht",
+	"application/x-mimearchive": ".mht",
+	"text/plain":                ".txt",
+}
+
+// DetectFormat 识别简历格式并返回对应的扩展名：先看文件头魔数，
+// 其次是 contentType，最后才是文件名的扩展名（招聘网站导出的文件扩展名经常与内容不符）
+func DetectFormat(data []byte, filename, contentType string) string {
+	if ext := sniffFormat(data); ext != "" {
+		return ext
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if ext, ok := contentTypeFormats[mediaType]; ok {
+			return ext
+		}
+	}
+	if ext := strings.ToLower(filepath.Ext(filename)); ext != "" {
+		return ext
+	}
+	if utf8.Valid(data) {
+		return ".txt"
+	}
+	return ""
+}
+
+func sniffFormat(data []byte) string {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	switch {
+	case bytes.Contains(head, []byte("%PDF-")):
+		return ".pdf"
+	case bytes.HasPrefix(data, oleSignature):
+		return ".doc"
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return sniffZip(data)
+	case bytes.HasPrefix(data, []byte("{\\rtf")):
+		return ".rtf"
+	}
+
+	text := strings.ToLower(strings.TrimLeft(string(bytes.TrimPrefix(head, []byte{0xEF, 0xBB, 0xBF})), " \t\r\n"))
+	switch {
+	case strings.HasPrefix(text, "mime-version:") || strings.HasPrefix(text, "from:") ||
+		(strings.Contains(text, "content-type: multipart/related") && !strings.HasPrefix(text, "<")):
+		return ".mht"
+	case strings.HasPrefix(text, "<!doctype html") || strings.HasPrefix(text, "<html") ||
+		(strings.HasPrefix(text, "<") && strings.Contains(text, "<body")):
+		return ".html"
+	}
+	return ""
+}
+
+// sniffZip 根据 zip 包中的文件区分 DOCX、PPTX 与 ODT
+func sniffZip(data []byte) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ""
+	}
+	for _, f := range zr.File {
+		switch {
+		case f.Name == "word/document.xml":
+			return ".docx"
+		case strings.HasPrefix(f.Name, "ppt/"):
+			return ".pptx"
+		case f.Name == "mimetype":
+			if content, err := readZipEntry(zr, "mimetype"); err == nil &&
+				strings.TrimSpace(string(content)) == "application/vnd.oasis.opendocument.text" {
+				return ".odt"
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,220 @@
+package prompt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"hr-api/pkg/client"
+	"hr-api/pkg/setting"
+)
+
+const (
+	// DefaultName 未指定模板时使用的模板名称
+	DefaultName = "default"
+	// BuiltinVersion 运行目录中没有 default 模板时使用内置模板，版本号固定
+	BuiltinVersion = "builtin-v1"
+
+	fileExt = ".tmpl"
+)
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Template 一个提示词模板，文件中需定义 system 与 user 两个子模板
+type Template struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Builtin    bool   `json:"builtin"`
+	UpdateTime int    `json:"update_time"`
+
+	tmpl *template.Template
+}
+
+// Data 渲染模板时可用的数据，如 {{.Job.Title}}、{{.Resume}}
+type Data struct {
+	Job    client.JobInfo
+	Resume string
+}
+
+// Dir 提示词模板所在目录，位于运行目录下
+func Dir() string {
+	return filepath.Join(setting.AppSetting.RuntimeRootPath, setting.LLMSetting.PromptDir)
+}
+
+// ValidName 模板名称只允许字母、数字、下划线与中划线，避免越出模板目录
+func ValidName(name string) bool {
+	return nameRegexp.MatchString(name)
+}
+
+// Load 按名称加载模板，名称为空时加载 default；每次调用都会重新读取文件，修改模板无需重启
+func Load(name string) (*Template, error) {
+	if name == "" {
+		name = DefaultName
+	}
+	if !ValidName(name) {
+		return nil, fmt.Errorf("提示词模板名称不合法: %s", name)
+	}
+
+	path := filepath.Join(Dir(), name+fileExt)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) && name == DefaultName {
+		return builtin()
+	}
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("提示词模板不存在: %s", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := parse(name, string(content))
+	if err != nil {
+		return nil, err
+	}
+	t.Version = version(name, content)
+	t.UpdateTime = int(info.ModTime().Unix())
+
+	return t, nil
+}
+
+// List 列出模板目录下的全部模板，目录中没有 default 模板时附带内置模板
+func List() ([]*Template, error) {
+	entries, err := os.ReadDir(Dir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var (
+		templates  []*Template
+		hasDefault bool
+	)
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), fileExt)
+		if entry.IsDir() || filepath.Ext(entry.Name()) != fileExt || !ValidName(name) {
+			continue
+		}
+
+		t, err := Load(name)
+		if err != nil {
+			return nil, err
+		}
+		if name == DefaultName {
+			hasDefault = true
+		}
+		templates = append(templates, t)
+	}
+
+	if !hasDefault {
+		t, err := builtin()
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// Render 使用招聘需求与简历文本渲染出系统提示词与用户提示词
+func (t *Template) Render(job client.JobInfo, resumeText string) (*client.Prompt, error) {
+	data := Data{Job: job, Resume: resumeText}
+
+	system, err := t.execute("system", data)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := t.execute("user", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.Prompt{
+		Version: t.Version,
+		System:  system,
+		User:    user,
+	}, nil
+}
+
+func (t *Template) execute(name string, data Data) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("渲染提示词模板 %s 失败: %w", t.Name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func parse(name, content string) (*Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("解析提示词模板 %s 失败: %w", name, err)
+	}
+
+	for _, block := range []string{"system", "user"} {
+		if tmpl.Lookup(block) == nil {
+			return nil, fmt.Errorf("提示词模板 %s 缺少 {{define \"%s\"}} 定义", name, block)
+		}
+	}
+
+	return &Template{Name: name, tmpl: tmpl}, nil
+}
+
+func builtin() (*Template, error) {
+	t, err := parse(DefaultName, builtinTemplate)
+	if err != nil {
+		return nil, err
+	}
+	t.Version = BuiltinVersion
+	t.Builtin = true
+
+	return t, nil
+}
+
+// version 模板名称加内容摘要，如 technical@1a2b3c4d，模板内容变化后版本随之变化
+func version(name string, content []byte) string {
+	sum := sha256.Sum256(content)
+	return name + "@" + hex.EncodeToString(sum[:])[:8]
+}
+
+const builtinTemplate = `{{define "system"}}
+你是一个专业的简历分析师。
+只允许输出 JSON，不允许任何多余内容。
+{{end}}
+
+{{define "user"}}
+【招聘需求】
+岗位名称：{{.Job.Title}}
+岗位要求：{{.Job.Requirements}}
+岗位描述：{{.Job.Description}}
+请分析以下简历内容：
+
+{{.Resume}}
+
+严格返回如下 JSON 结构，match_score 取值 0-100：
+{
+  "personal_info": { "name": "", "email": "", "phone": "", "location": "", "links": [] },
+  "summary": "",
+  "work_experience": [],
+  "education": [],
+  "skills": { "technical": [], "soft": [], "languages": [{ "name": "", "proficiency": "" }], "certifications": [] },
+  "analysis": { "strengths": [], "weaknesses": [], "recommendations": [], "match_score": 0 },
+  "metadata": { "analysis_date": "", "word_count": 0, "estimated_yoe": 0 }
+}
+{{end}}
+`
@@ -0,0 +1,216 @@
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 可脱敏的个人信息类别
+const (
+	CategoryName    = "name"
+	CategoryPhone   = "phone"
+	CategoryEmail   = "email"
+	CategoryIDCard  = "id_card"
+	CategoryAddress = "address"
+)
+
+// Categories 全部可脱敏的类别
+var Categories = []string{CategoryName, CategoryPhone, CategoryEmail, CategoryIDCard, CategoryAddress}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// 手机号（可带 +86 与分隔符）与带区号的固定电话
+	phonePattern = regexp.MustCompile(`(?:\+?86[\s\-]?)?1[3-9]\d[\s\-]?\d{4}[\s\-]?\d{4}|0\d{2,3}-\d{7,8}`)
+	// 18 位身份证号，末位可为 X
+	idCardPattern = regexp.MustCompile(`[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]`)
+	// 带标签的地址取标签后到行尾或分隔符为止的内容
+	addressLabelPattern = regexp.MustCompile(`(?:家庭住址|通讯地址|联系地址|现住址|居住地址|住址|地址)\s*[:：]\s*([^\n|｜]+)`)
+	// 未带标签的详细地址，如 北京市朝阳区建国路88号
+	addressPattern = regexp.MustCompile(`\p{Han}{2,}(?:省|市|自治区)\p{Han}*(?:市|区|县)\p{Han}*(?:路|街|巷|道|村)\d+(?:-\d+)?号(?:\d+(?:栋|号楼|单元|室))*`)
+)
+
+var placeholderPattern = regexp.MustCompile(`\[(?:NAME|PHONE|EMAIL|ID_CARD|ADDRESS)_\d+\]`)
+
+// Redactor 将文本中的个人信息替换为占位符
+type Redactor struct {
+	categories map[string]bool
+}
+
+// New 创建脱敏器，categories 为空时不做任何替换
+func New(categories []string) (*Redactor, error) {
+	r := &Redactor{categories: map[string]bool{}}
+	for _, c := range categories {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if !isCategory(c) {
+			return nil, fmt.Errorf("unsupported redact category: %s", c)
+		}
+		r.categories[c] = true
+	}
+	return r, nil
+}
+
+func isCategory(c string) bool {
+	for _, known := range Categories {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled 是否脱敏该类别
+func (r *Redactor) Enabled(category string) bool {
+	return r.categories[category]
+}
+
+// Mapping 一次脱敏中占位符与原文的对应关系，只保存在本地，用于还原模型输出
+type Mapping struct {
+	originals map[string]string // placeholder -> original
+	byValue   map[string]string // category + original -> placeholder
+	counts    map[string]int
+}
+
+func newMapping() *Mapping {
+	return &Mapping{
+		originals: map[string]string{},
+		byValue:   map[string]string{},
+		counts:    map[string]int{},
+	}
+}
+
+// placeholder 同一原文在一份简历中总是得到同一个占位符，如 [PHONE_1]
+func (m *Mapping) placeholder(category, original string) string {
+	key := category + "\x00" + original
+	if p, ok := m.byValue[key]; ok {
+		return p
+	}
+	m.counts[category]++
+	p := fmt.Sprintf("[%s_%d]", strings.ToUpper(category), m.counts[category])
+	m.byValue[key] = p
+	m.originals[p] = original
+	return p
+}
+
+// Counts 每个类别被替换的不同取值个数，可用于审计记录，不包含原文
+func (m *Mapping) Counts() map[string]int {
+	counts := make(map[string]int, len(m.counts))
+	for k, v := range m.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+// Empty 是否没有替换任何内容
+func (m *Mapping) Empty() bool {
+	return len(m.originals) == 0
+}
+
+// Redact 替换 text 中启用类别的个人信息。姓名无法可靠地用正则识别，
+// 由调用方通过 names 传入本地提取到的姓名
+func (r *Redactor) Redact(text string, names ...string) (string, *Mapping) {
+	m := newMapping()
+
+	// 身份证号先于手机号替换，避免其中的数字片段被当作手机号
+	if r.Enabled(CategoryIDCard) {
+		text = replaceBounded(text, idCardPattern, func(s string) string { return m.placeholder(CategoryIDCard, s) })
+	}
+	if r.Enabled(CategoryEmail) {
+		text = emailPattern.ReplaceAllStringFunc(text, func(s string) string { return m.placeholder(CategoryEmail, s) })
+	}
+	if r.Enabled(CategoryPhone) {
+		text = replaceBounded(text, phonePattern, func(s string) string { return m.placeholder(CategoryPhone, s) })
+	}
+	if r.Enabled(CategoryAddress) {
+		text = addressLabelPattern.ReplaceAllStringFunc(text, func(s string) string {
+			sub := addressLabelPattern.FindStringSubmatchIndex(s)
+			value := strings.TrimSpace(s[sub[2]:sub[3]])
+			if value == "" || placeholderPattern.MatchString(value) {
+				return s
+			}
+			return s[:sub[2]] + m.placeholder(CategoryAddress, value)
+		})
+		text = addressPattern.ReplaceAllStringFunc(text, func(s string) string { return m.placeholder(CategoryAddress, s) })
+	}
+	if r.Enabled(CategoryName) {
+		// 先替换较长的姓名，避免“张三丰”被“张三”部分替换
+		sorted := append([]string(nil), names...)
+		sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+		for _, name := range sorted {
+			if name = strings.TrimSpace(name); name != "" && strings.Contains(text, name) {
+				text = strings.ReplaceAll(text, name, m.placeholder(CategoryName, name))
+			}
+		}
+	}
+
+	return text, m
+}
+
+// replaceBounded 只替换前后不紧邻数字的匹配，避免截断更长的编号
+func replaceBounded(text string, pattern *regexp.Regexp, repl func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		if loc[0] > 0 && isDigit(text[loc[0]-1]) || loc[1] < len(text) && isDigit(text[loc[1]]) {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(repl(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Restore 将 s 中的占位符还原为原文
+func (m *Mapping) Restore(s string) string {
+	if m == nil || len(m.originals) == 0 {
+		return s
+	}
+	return placeholderPattern.ReplaceAllStringFunc(s, func(p string) string {
+		if original, ok := m.originals[p]; ok {
+			return original
+		}
+		return p
+	})
+}
+
+// RestoreAll 递归还原 v（结构体指针）中所有字符串字段与字符串切片里的占位符
+func (m *Mapping) RestoreAll(v interface{}) {
+	if m == nil || len(m.originals) == 0 {
+		return
+	}
+	m.restoreValue(reflect.ValueOf(v))
+}
+
+func (m *Mapping) restoreValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			m.restoreValue(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				m.restoreValue(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			m.restoreValue(v.Index(i))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(m.Restore(v.String()))
+		}
+	}
+}
@@ -18,10 +18,19 @@ type App struct {
 	ImageMaxSize   int
 	ImageAllowExts []string
 
+	// ResumeMaxSize 解析简历文件的大小上限
+	ResumeMaxSize int
+
 	ExportSavePath string
 	QrCodeSavePath string
 	FontSavePath   string
 
+	// OfferTemplateDir offer 函模板目录（.html 或 .docx），相对于 RuntimeRootPath
+	OfferTemplateDir string
+
+	// JobCloseReason 关闭职位时自动淘汰进行中应聘记录的默认原因
+	JobCloseReason string
+
 	LogSavePath string
 	LogSaveName string
 	LogFileExt  string
@@ -106,6 +115,75 @@ type Redis struct {
 
 var RedisSetting = &Redis{}
 
+type Queue struct {
+	// azure, redis 或 memory
+	Driver           string
+	Namespace        string
+	ConnectionString string
+	Name             string
+	MaxDeliveryCount int
+	BatchSize        int
+	PollInterval     time.Duration
+
+	// RetryDelay 用于 redis 与 memory：处理失败后首次重试的等待时间，之后按投递次数翻倍；
+	// LockDuration 仅用于 redis：消息取出后的锁定时间，超时未完成（worker 崩溃）的消息重新入队
+	RetryDelay   time.Duration
+	LockDuration time.Duration
+}
+
+var QueueSetting = &Queue{}
+
+type LLM struct {
+	// azure, openai 或 fake
+	Provider string
+	// StructuredOutput 使用 response_format JSON Schema 约束输出，模型不支持时关闭
+	StructuredOutput bool
+	// PromptDir 提示词模板目录，相对于 RuntimeRootPath
+	PromptDir string
+	// MonthlyTokenBudget 每月 token 预算，超出后只做规则提取，0 表示不限制
+	MonthlyTokenBudget int
+	// 每千 token 的价格，用于估算成本
+	PromptTokenPrice     float64
+	CompletionTokenPrice float64
+	// RedactCategories 发送给模型前脱敏的个人信息类别：name, phone, email, id_card, address
+	RedactCategories []string
+	// 以下仅用于 openai（OpenAI 兼容接口）
+	BaseURL string
+	Model   string
+	ApiKey  string
+}
+
+var LLMSetting = &LLM{}
+
+type Mail struct {
+	// Host SMTP 服务器，为空时不发送邮件通知
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// From 发件地址，FromName 为发件人显示名称
+	From     string
+	FromName string
+	// TLS starttls（服务器支持时升级）、tls（隐式 TLS，一般为 465 端口）或 none
+	TLS     string
+	Timeout time.Duration
+	// TemplateDir 通知模板目录，相对于 RuntimeRootPath，其中的模板覆盖内置模板
+	TemplateDir string
+}
+
+var MailSetting = &Mail{}
+
+type Webhook struct {
+	// Timeout 单次投递的超时时间
+	Timeout time.Duration
+	// DefaultMinScore 订阅高匹配度事件时未指定阈值使用的默认值
+	DefaultMinScore int
+	// AllowedHosts 允许投递的内网主机，其余解析为内网、本机或链路本地地址的订阅地址一律拒绝
+	AllowedHosts []string
+}
+
+var WebhookSetting = &Webhook{}
+
 var cfg *ini.File
 
 // Setup initialize the configuration instance
@@ -121,8 +199,13 @@ func Setup() {
 	mapTo("server", ServerSetting)
 	mapTo("database", DatabaseSetting)
 	mapTo("redis", RedisSetting)
+	mapTo("queue", QueueSetting)
+	mapTo("llm", LLMSetting)
+	mapTo("mail", MailSetting)
+	mapTo("webhook", WebhookSetting)
 
 	AppSetting.ImageMaxSize = AppSetting.ImageMaxSize * 1024 * 1024
+	AppSetting.ResumeMaxSize = AppSetting.ResumeMaxSize * 1024 * 1024
 	ServerSetting.ReadTimeout = ServerSetting.ReadTimeout * time.Second
 	ServerSetting.WriteTimeout = ServerSetting.WriteTimeout * time.Second
 	RedisSetting.IdleTimeout = RedisSetting.IdleTimeout * time.Second
@@ -130,6 +213,11 @@ func Setup() {
 	RedisSetting.DialTimeout = RedisSetting.DialTimeout * time.Second
 	RedisSetting.ReadTimeout = RedisSetting.ReadTimeout * time.Second
 	RedisSetting.WriteTimeout = RedisSetting.WriteTimeout * time.Second
+	QueueSetting.PollInterval = QueueSetting.PollInterval * time.Second
+	QueueSetting.RetryDelay = QueueSetting.RetryDelay * time.Second
+	QueueSetting.LockDuration = QueueSetting.LockDuration * time.Second
+	MailSetting.Timeout = MailSetting.Timeout * time.Second
+	WebhookSetting.Timeout = WebhookSetting.Timeout * time.Second
 }
 
 // mapTo map section
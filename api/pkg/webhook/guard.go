@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"hr-api/pkg/setting"
+)
+
+// ErrForbiddenAddress 订阅地址指向内网、本机或链路本地地址，为防止 SSRF 不允许投递
+var ErrForbiddenAddress = errors.New("webhook 地址不能指向内网地址")
+
+// sharedAddressSpace 运营商级 NAT 使用的 100.64.0.0/10，同样不对外
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsForbiddenIP 本机、私有网络、链路本地（含云厂商元数据地址）、组播与未指定地址
+func IsForbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// isAllowedHost 配置中允许的主机不做内网地址检查，用于投递到内网中的订阅方
+func isAllowedHost(host string) bool {
+	for _, allowed := range setting.WebhookSetting.AllowedHosts {
+		if strings.EqualFold(strings.TrimSpace(allowed), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUrl 解析订阅地址的主机，任一解析结果为内网地址时返回 ErrForbiddenAddress。
+// 解析结果可能在保存后变化，Send 在建立连接时还会再次检查
+func CheckUrl(ctx context.Context, rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+	if isAllowedHost(host) {
+		return nil
+	}
+
+	ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("无法解析 webhook 地址 %s: %v", host, err)
+	}
+	for _, ip := range ips {
+		if IsForbiddenIP(ip) {
+			return fmt.Errorf("%w: %s 解析为 %s", ErrForbiddenAddress, host, ip)
+		}
+	}
+	return nil
+}
+
+// guardedDialer 连接前检查实际连接的 IP，防止解析结果在检查后被改为内网地址，重定向后的连接同样检查
+func guardedDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || IsForbiddenIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return nil
+		},
+	}
+}
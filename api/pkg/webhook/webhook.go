@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hr-api/pkg/setting"
+)
+
+// 订阅方类型：teams 发送 Adaptive Card，generic 发送签名的 JSON
+const (
+	KindTeams   = "teams"
+	KindGeneric = "generic"
+)
+
+// 可订阅的招聘事件
+const (
+	EventHighMatchScore = "high_match_score"
+	EventNewCandidate   = "new_candidate"
+	EventStageChanged   = "stage_changed"
+)
+
+// Events 全部可订阅的事件
+var Events = []string{EventHighMatchScore, EventNewCandidate, EventStageChanged}
+
+// 发送给 generic 订阅方的请求头，签名为 HMAC-SHA256(secret, timestamp + "." + body) 的十六进制
+const (
+	HeaderEvent     = "X-Hr-Event"
+	HeaderDelivery  = "X-Hr-Delivery"
+	HeaderTimestamp = "X-Hr-Timestamp"
+	HeaderSignature = "X-Hr-Signature"
+
+	signaturePrefix = "sha256="
+	// responseLimit 投递记录中保存的响应内容上限
+	responseLimit  = 1024
+	defaultTimeout = 10 * time.Second
+)
+
+// Fact Teams 卡片中展示的一个字段
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// Event 一个招聘事件，Data 为 generic 订阅方收到的结构化数据，Facts 用于 Teams 卡片展示
+type Event struct {
+	ID    string                 `json:"id"`
+	Type  string                 `json:"event"`
+	Time  time.Time              `json:"time"`
+	JobId int                    `json:"job_id"`
+	Title string                 `json:"title"`
+	Data  map[string]interface{} `json:"data"`
+	Facts []Fact                 `json:"-"`
+}
+
+// NewEvent 创建一个带随机 ID 的事件
+func NewEvent(eventType string, jobId int, title string) *Event {
+	return &Event{
+		ID:    NewID(),
+		Type:  eventType,
+		Time:  time.Now(),
+		JobId: jobId,
+		Title: title,
+		Data:  make(map[string]interface{}),
+	}
+}
+
+// Add 同时添加结构化数据与卡片字段，label 为空时只添加到 Data
+func (e *Event) Add(key, label string, value interface{}) *Event {
+	e.Data[key] = value
+	if label != "" {
+		e.Facts = append(e.Facts, Fact{Title: label, Value: fmt.Sprint(value)})
+	}
+	return e
+}
+
+// ValidEvent 判断是否为可订阅的事件
+func ValidEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NewID 生成随机的十六进制 ID，用作事件 ID 与签名密钥
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Payload 按订阅方类型生成请求体
+func Payload(kind string, e *Event) ([]byte, error) {
+	switch kind {
+	case KindTeams:
+		return json.Marshal(adaptiveCard(e))
+	case KindGeneric:
+		return json.Marshal(e)
+	default:
+		return nil, fmt.Errorf("不支持的 webhook 类型: %s", kind)
+	}
+}
+
+// adaptiveCard Teams Incoming Webhook / Workflows 接受的 Adaptive Card 消息
+func adaptiveCard(e *Event) map[string]interface{} {
+	facts := e.Facts
+	if facts == nil {
+		facts = []Fact{}
+	}
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body": []map[string]interface{}{
+					{"type": "TextBlock", "text": e.Title, "weight": "Bolder", "size": "Medium", "wrap": true},
+					{"type": "FactSet", "facts": facts},
+					{"type": "TextBlock", "text": e.Time.Format("2006-01-02 15:04:05"), "isSubtle": true, "size": "Small"},
+				},
+			},
+		}},
+	}
+}
+
+// Sign 计算 generic 请求的签名
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，供接收方参考实现
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Request 一次投递
+type Request struct {
+	Kind       string
+	Url        string
+	Secret     string
+	Event      string
+	DeliveryId string
+	Body       []byte
+}
+
+// Response 订阅方的响应，Body 最多保留 1KB
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// Retryable 网络错误、超时、限流与 5xx 值得重试，其余 4xx 说明请求本身有问题
+func (r *Response) Retryable() bool {
+	return r == nil || r.StatusCode >= 500 || r.StatusCode == http.StatusRequestTimeout || r.StatusCode == http.StatusTooManyRequests
+}
+
+// Success 订阅方返回 2xx
+func (r *Response) Success() bool {
+	return r != nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Send 投递请求，generic 订阅方且设置了密钥时附带签名；收到响应时 err 为 nil，是否成功看 StatusCode。
+// 连接内网地址时返回 ErrForbiddenAddress，配置中允许的主机除外
+func Send(ctx context.Context, req *Request) (*Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.Url, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "hr-api-webhook")
+	if req.Kind == KindGeneric {
+		timestamp := time.Now().Unix()
+		httpReq.Header.Set(HeaderEvent, req.Event)
+		httpReq.Header.Set(HeaderDelivery, req.DeliveryId)
+		httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		if req.Secret != "" {
+			httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+		}
+	}
+
+	timeout := setting.WebhookSetting.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	httpClient := &http.Client{Timeout: timeout}
+	if !isAllowedHost(httpReq.URL.Hostname()) {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = guardedDialer().DialContext
+		httpClient.Transport = transport
+	}
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseLimit))
+	// 截断处可能落在多字节字符中间
+	return &Response{StatusCode: resp.StatusCode, Body: strings.ToValidUTF8(string(body), "")}, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"hr-api/pkg/app"
+	"hr-api/pkg/dedupe"
 )
 
 func GetBlobConf() (*setting.MicrosoftEntraIDConfig, error) {
@@ -88,9 +89,10 @@ func UploadFile(c *gin.Context) {
 	blobURL := fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", accountName, containerName, blobName)
 
 	appG.SuccessResponse(map[string]interface{}{
-		"container": containerName,
-		"filename":  file.Filename,
-		"size":      file.Size,
-		"url":       blobURL,
+		"container":    containerName,
+		"filename":     file.Filename,
+		"size":         file.Size,
+		"url":          blobURL,
+		"content_hash": dedupe.ContentHash(fileBytes),
 	})
 }
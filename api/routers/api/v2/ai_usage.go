@@ -0,0 +1,78 @@
+package v2
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/setting"
+	"hr-api/service/ai_usage_service"
+)
+
+// @Summary Get AI token usage and estimated cost
+// @Description Usage is recorded once per resume analysis: calls and retries count the model requests made by that analysis, including failed ones, and analyses counts analyses rather than requests
+// @Produce json
+// @Param group_by query string false "day, uid, job or deployment, default day"
+// @Param start query string false "Start date 2006-01-02, default first day of this month"
+// @Param end query string false "End date 2006-01-02, default today"
+// @Param uid query int false "Uid"
+// @Param job_id query int false "JobId"
+// @Param deployment query string false "Deployment"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/ai/usage [get]
+func GetAIUsage(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	groupBy := c.DefaultQuery("group_by", "day")
+	if _, ok := models.AIUsageGroupColumns[groupBy]; !ok {
+		appG.FailResponse(fmt.Sprintf("不支持的汇总维度: %s", groupBy))
+		return
+	}
+
+	now := time.Now()
+	start, err := time.ParseInLocation("2006-01-02", c.DefaultQuery("start", now.Format("2006-01")+"-01"), time.Local)
+	if err != nil {
+		appG.FailResponse(fmt.Sprintf("开始日期格式错误: %v", err))
+		return
+	}
+	end, err := time.ParseInLocation("2006-01-02", c.DefaultQuery("end", now.Format("2006-01-02")), time.Local)
+	if err != nil {
+		appG.FailResponse(fmt.Sprintf("结束日期格式错误: %v", err))
+		return
+	}
+
+	service := ai_usage_service.AIUsage{
+		Uid:        com.StrTo(c.DefaultQuery("uid", "0")).MustInt(),
+		JobId:      com.StrTo(c.DefaultQuery("job_id", "0")).MustInt(),
+		Deployment: c.DefaultQuery("deployment", ""),
+		GroupBy:    groupBy,
+		StartDay:   ai_usage_service.Day(start),
+		EndDay:     ai_usage_service.Day(end),
+	}
+	datas, err := service.GetSummary()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	monthUsed, err := ai_usage_service.MonthUsed()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists":    datas,
+		"total":    len(datas),
+		"group_by": groupBy,
+		"budget": map[string]interface{}{
+			"monthly_tokens": setting.LLMSetting.MonthlyTokenBudget,
+			"month_used":     monthUsed,
+		},
+	})
+}
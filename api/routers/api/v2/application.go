@@ -0,0 +1,317 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/util"
+	"hr-api/service/application_service"
+	"hr-api/service/candidate_service"
+	"hr-api/service/job_service"
+	"hr-api/service/resume_service"
+)
+
+// @Summary Get application list
+// @Produce json
+// @Param candidate_id query int false "CandidateId"
+// @Param job_id query int false "JobId"
+// @Param status query string false "active, withdrawn or archived"
+// @Param stage query string false "Pipeline stage"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/application/list [get]
+func GetApplications(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := application_service.Application{
+		CandidateId: com.StrTo(c.DefaultQuery("candidate_id", "0")).MustInt(),
+		JobId:       com.StrTo(c.DefaultQuery("job_id", "0")).MustInt(),
+		Status:      c.DefaultQuery("status", ""),
+		Stage:       c.DefaultQuery("stage", ""),
+		Page:        page,
+		Limit:       limit,
+	}
+	datas, err := service.GetAll()
+	if err != nil {
+		datas = []*models.Application{}
+	}
+
+	count, err := service.Count()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+type ApplicationAddBody struct {
+	CandidateId int `json:"candidate_id" binding:"required,min=1"`
+	JobId       int `json:"job_id" binding:"required,min=1"`
+	ResumeId    int `json:"resume_id" binding:"min=0"`
+}
+
+// @Summary Add an application of a candidate to a job
+// @Produce json
+// @Param candidate_id body int true "CandidateId"
+// @Param job_id body int true "JobId"
+// @Param resume_id body int false "ResumeId"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/application/create [post]
+func AddApplication(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data ApplicationAddBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	candidateService := candidate_service.Candidate{Id: data.CandidateId}
+	exists, err := candidateService.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("候选人不存在: %d", data.CandidateId))
+		return
+	}
+
+	jobService := job_service.Job{Id: data.JobId}
+	exists, err = jobService.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("招聘需求不存在: %d", data.JobId))
+		return
+	}
+
+	if data.ResumeId > 0 {
+		resumeService := resume_service.Resume{Id: data.ResumeId}
+		exists, err = resumeService.ExistByID()
+		if err != nil {
+			appG.IntervalErrorResponse(err.Error())
+			return
+		}
+		if !exists {
+			appG.FailResponse(fmt.Sprintf("简历记录不存在: %d", data.ResumeId))
+			return
+		}
+	}
+
+	service := application_service.Application{
+		CandidateId: data.CandidateId,
+		JobId:       data.JobId,
+		ResumeId:    data.ResumeId,
+		CreateUid:   util.GetCurrentUid(c),
+		Ctx:         c.Request.Context(),
+	}
+	existsData, err := service.GetByCandidateJob()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+	if existsData.ID > 0 {
+		appG.FailResponse(fmt.Sprintf("候选人已应聘该职位: %d", existsData.ID))
+		return
+	}
+
+	if err := service.Add(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{"id": service.Id})
+}
+
+type ApplicationEditBody struct {
+	Id       int    `json:"id" binding:"required,min=1"`
+	ResumeId int    `json:"resume_id" binding:"min=0"`
+	Status   string `json:"status" binding:"omitempty,oneof=active withdrawn archived"`
+}
+
+// @Summary Edit an application
+// @Produce json
+// @Param id body int true "Id"
+// @Param resume_id body int false "ResumeId"
+// @Param status body string false "active, withdrawn or archived"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/application/update [put]
+func EditApplication(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data ApplicationEditBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := application_service.Application{Id: data.Id}
+	existsData, err := service.GetApplication()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if existsData.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("应聘记录不存在: %d", data.Id))
+		return
+	}
+
+	service.ResumeId = existsData.ResumeId
+	if data.ResumeId > 0 && data.ResumeId != existsData.ResumeId {
+		resumeService := resume_service.Resume{Id: data.ResumeId}
+		exists, err := resumeService.ExistByID()
+		if err != nil {
+			appG.IntervalErrorResponse(err.Error())
+			return
+		}
+		if !exists {
+			appG.FailResponse(fmt.Sprintf("简历记录不存在: %d", data.ResumeId))
+			return
+		}
+		service.ResumeId = data.ResumeId
+	}
+	service.Status = firstNonEmpty(data.Status, existsData.Status)
+
+	if err := service.Edit(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+type ApplicationURI struct {
+	Id int `uri:"id" binding:"required,min=1"`
+}
+
+// @Summary Delete an application
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/application/delete/{id} [delete]
+func DeleteApplication(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ApplicationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := application_service.Application{Id: uri.Id}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("应聘记录不存在: %d", uri.Id))
+		return
+	}
+
+	if err := service.Delete(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(uri)
+}
+
+type ApplicationStageBody struct {
+	Stage  string `json:"stage" binding:"required,max=32"`
+	Reason string `json:"reason" binding:"max=512"`
+}
+
+// @Summary Move an application to another pipeline stage
+// @Produce json
+// @Param id path int true "Id"
+// @Param stage body string true "Target stage"
+// @Param reason body string false "Reason, required when rejecting"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/application/{id}/stage [put]
+func MoveApplicationStage(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ApplicationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	var data ApplicationStageBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := application_service.Application{Id: uri.Id, Ctx: c.Request.Context()}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("应聘记录不存在: %d", uri.Id))
+		return
+	}
+
+	err = service.MoveStage(data.Stage, data.Reason, util.GetCurrentUid(c))
+	if errors.Is(err, application_service.ErrInvalidMove) || errors.Is(err, application_service.ErrStageChanged) {
+		appG.FailResponse(err.Error())
+		return
+	}
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{"id": uri.Id, "stage": service.Stage})
+}
+
+// @Summary Get the stage history of an application
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/application/{id}/stage/history [get]
+func GetApplicationStageHistory(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ApplicationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := application_service.Application{Id: uri.Id}
+	logs, err := service.StageHistory()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(logs)
+}
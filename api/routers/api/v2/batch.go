@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-api/pkg/app"
+	"hr-api/pkg/util"
+	"hr-api/service/batch_service"
+	"hr-api/service/job_service"
+)
+
+// @Summary Re-analyze all resumes of a job in a batch
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/job/{id}/reanalyze [post]
+func ReanalyzeJob(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri JobURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	jobService := job_service.Job{Id: uri.Id}
+	exists, err := jobService.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("招聘需求不存在: %d", uri.Id))
+		return
+	}
+
+	service := batch_service.Batch{
+		JobId:     uri.Id,
+		CreateUid: util.GetCurrentUid(c),
+		Ctx:       c.Request.Context(),
+	}
+	if err := service.Add(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	data, err := service.Get()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+type BatchURI struct {
+	Id int `uri:"id" binding:"required,min=1"`
+}
+
+// @Summary Get progress of an analysis batch
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/batch/{id} [get]
+func GetBatch(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri BatchURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := batch_service.Batch{Id: uri.Id, Ctx: c.Request.Context()}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("批次不存在: %d", uri.Id))
+		return
+	}
+
+	data, err := service.Get()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
@@ -0,0 +1,260 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/util"
+	"hr-api/service/candidate_service"
+)
+
+// @Summary Get candidate list
+// @Produce json
+// @Param keyword query string false "Name, phone or email"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/candidate/list [get]
+func GetCandidates(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := candidate_service.Candidate{
+		Keyword: c.DefaultQuery("keyword", ""),
+		Page:    page,
+		Limit:   limit,
+	}
+	datas, err := service.GetAll()
+	if err != nil {
+		datas = []*models.Candidate{}
+	}
+
+	count, err := service.Count()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+type CandidateURI struct {
+	Id int `uri:"id" binding:"required,min=1"`
+}
+
+// @Summary Get a candidate
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/candidate/{id} [get]
+func GetCandidate(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri CandidateURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := candidate_service.Candidate{Id: uri.Id}
+	data, err := service.GetCandidate()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if data.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("候选人不存在: %d", uri.Id))
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+type CandidateAddBody struct {
+	Name     string `json:"name" binding:"required,max=64"`
+	Phone    string `json:"phone" binding:"max=32"`
+	Email    string `json:"email" binding:"omitempty,email,max=128"`
+	Location string `json:"location" binding:"max=128"`
+	Remark   string `json:"remark" binding:"max=1024"`
+}
+
+// @Summary Add a candidate
+// @Produce json
+// @Param name body string true "Name"
+// @Param phone body string false "Phone"
+// @Param email body string false "Email"
+// @Param location body string false "Location"
+// @Param remark body string false "Remark"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/candidate/create [post]
+func AddCandidate(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data CandidateAddBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := candidate_service.Candidate{
+		Name:      data.Name,
+		Phone:     data.Phone,
+		Email:     data.Email,
+		Location:  data.Location,
+		Remark:    data.Remark,
+		CreateUid: util.GetCurrentUid(c),
+	}
+	if err := service.Add(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{"id": service.Id})
+}
+
+type CandidateEditBody struct {
+	Id       int    `json:"id" binding:"required,min=1"`
+	Name     string `json:"name" binding:"max=64"`
+	Phone    string `json:"phone" binding:"max=32"`
+	Email    string `json:"email" binding:"omitempty,email,max=128"`
+	Location string `json:"location" binding:"max=128"`
+	Remark   string `json:"remark" binding:"max=1024"`
+}
+
+// @Summary Edit a candidate
+// @Produce json
+// @Param id body int true "Id"
+// @Param name body string false "Name"
+// @Param phone body string false "Phone"
+// @Param email body string false "Email"
+// @Param location body string false "Location"
+// @Param remark body string false "Remark"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/candidate/update [put]
+func EditCandidate(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data CandidateEditBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := candidate_service.Candidate{Id: data.Id}
+	existsData, err := service.GetCandidate()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if existsData.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("候选人不存在: %d", data.Id))
+		return
+	}
+
+	// 未传的字段保持原值
+	service.Name = firstNonEmpty(data.Name, existsData.Name)
+	service.Phone = firstNonEmpty(data.Phone, existsData.Phone)
+	service.Email = firstNonEmpty(data.Email, existsData.Email)
+	service.Location = firstNonEmpty(data.Location, existsData.Location)
+	service.Remark = firstNonEmpty(data.Remark, existsData.Remark)
+
+	if err := service.Edit(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// @Summary Delete a candidate and its applications, resumes are kept
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/candidate/delete/{id} [delete]
+func DeleteCandidate(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri CandidateURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := candidate_service.Candidate{Id: uri.Id}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("候选人不存在: %d", uri.Id))
+		return
+	}
+
+	if err := service.Delete(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(uri)
+}
+
+// @Summary Get the timeline of a candidate: resumes, AI analyses and applications, newest first
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/candidate/{id}/timeline [get]
+func GetCandidateTimeline(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri CandidateURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := candidate_service.Candidate{Id: uri.Id}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("候选人不存在: %d", uri.Id))
+		return
+	}
+
+	events, err := service.Timeline()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(events)
+}
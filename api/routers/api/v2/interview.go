@@ -0,0 +1,446 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/util"
+	"hr-api/service/application_service"
+	"hr-api/service/interview_service"
+	"hr-api/service/user_service"
+)
+
+// @Summary Get interview list
+// @Produce json
+// @Param application_id query int false "ApplicationId"
+// @Param status query string false "scheduled, cancelled or completed"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/list [get]
+func GetInterviews(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := interview_service.Interview{
+		ApplicationId: com.StrTo(c.DefaultQuery("application_id", "0")).MustInt(),
+		Status:        c.DefaultQuery("status", ""),
+		Page:          page,
+		Limit:         limit,
+	}
+	datas, err := service.GetAll()
+	if err != nil {
+		datas = []*models.Interview{}
+	}
+
+	count, err := service.Count()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// @Summary Get interviews of the current user as interviewer
+// @Produce json
+// @Param since query int false "Unix time, only interviews ending after it, defaults to now"
+// @Param status query string false "scheduled, cancelled or completed"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/mine [get]
+func GetMyInterviews(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := interview_service.Interview{
+		Uid:    util.GetCurrentUid(c),
+		Since:  com.StrTo(c.DefaultQuery("since", fmt.Sprint(time.Now().Unix()))).MustInt(),
+		Status: c.DefaultQuery("status", ""),
+		Page:   page,
+		Limit:  limit,
+	}
+	datas, err := service.Mine()
+	if err != nil {
+		datas = []*models.Interview{}
+	}
+
+	count, err := service.MineCount()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// @Summary Check interviewer conflicts of a time slot
+// @Produce json
+// @Param interviewers query string true "Comma separated interviewer uids"
+// @Param start_time query int true "StartTime"
+// @Param end_time query int true "EndTime"
+// @Param id query int false "Interview being rescheduled"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/conflicts [get]
+func GetInterviewConflicts(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	service := interview_service.Interview{
+		Id:        com.StrTo(c.DefaultQuery("id", "0")).MustInt(),
+		StartTime: com.StrTo(c.DefaultQuery("start_time", "0")).MustInt(),
+		EndTime:   com.StrTo(c.DefaultQuery("end_time", "0")).MustInt(),
+	}
+	for _, uid := range strings.Split(c.DefaultQuery("interviewers", ""), ",") {
+		if id := com.StrTo(strings.TrimSpace(uid)).MustInt(); id > 0 {
+			service.Interviewers = append(service.Interviewers, id)
+		}
+	}
+	if len(service.Interviewers) == 0 || service.EndTime <= service.StartTime {
+		appG.FailResponse("请指定面试官与有效的时间段")
+		return
+	}
+
+	conflicts, err := service.Conflicts()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(conflicts)
+}
+
+type InterviewURI struct {
+	Id int `uri:"id" binding:"required,min=1"`
+}
+
+// @Summary Get an interview
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/{id} [get]
+func GetInterview(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri InterviewURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := interview_service.Interview{Id: uri.Id}
+	data, err := service.GetInterview()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if data.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("面试不存在: %d", uri.Id))
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+// checkInterviewers 面试官必须是已存在的用户
+func checkInterviewers(appG *app.Gin, uids []int) bool {
+	for _, uid := range uids {
+		userService := user_service.User{Id: uid}
+		exists, err := userService.ExistByID()
+		if err != nil {
+			appG.IntervalErrorResponse(err.Error())
+			return false
+		}
+		if !exists {
+			appG.FailResponse(fmt.Sprintf("面试官不存在: %d", uid))
+			return false
+		}
+	}
+	return true
+}
+
+// checkInterviewConflicts 面试官时间冲突时返回 false
+func checkInterviewConflicts(appG *app.Gin, service *interview_service.Interview) bool {
+	conflicts, err := service.Conflicts()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return false
+	}
+	if len(conflicts) > 0 {
+		appG.FailResponse(interview_service.ConflictMessage(conflicts))
+		return false
+	}
+	return true
+}
+
+type InterviewAddBody struct {
+	ApplicationId int    `json:"application_id" binding:"required,min=1"`
+	Round         int    `json:"round" binding:"required,min=1"`
+	StartTime     int    `json:"start_time" binding:"required,min=1"`
+	EndTime       int    `json:"end_time" binding:"required,gtfield=StartTime"`
+	Location      string `json:"location" binding:"max=255"`
+	MeetingUrl    string `json:"meeting_url" binding:"omitempty,url,max=1024"`
+	Remark        string `json:"remark" binding:"max=1024"`
+	Interviewers  []int  `json:"interviewers" binding:"required,min=1,dive,min=1"`
+}
+
+// @Summary Schedule an interview for an application
+// @Produce json
+// @Param application_id body int true "ApplicationId"
+// @Param round body int true "Round"
+// @Param start_time body int true "StartTime"
+// @Param end_time body int true "EndTime"
+// @Param location body string false "Location"
+// @Param meeting_url body string false "MeetingUrl"
+// @Param remark body string false "Remark"
+// @Param interviewers body []int true "Interviewer uids"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/create [post]
+func AddInterview(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data InterviewAddBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	if data.Location == "" && data.MeetingUrl == "" {
+		appG.FailResponse("请填写面试地点或会议链接")
+		return
+	}
+
+	applicationService := application_service.Application{Id: data.ApplicationId}
+	application, err := applicationService.GetApplication()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+	if application.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("应聘记录不存在: %d", data.ApplicationId))
+		return
+	}
+	if application.Status != models.ApplicationActive || application_service.IsTerminalStage(application.Stage) {
+		appG.FailResponse(fmt.Sprintf("应聘记录已结束，不能安排面试: %d", data.ApplicationId))
+		return
+	}
+
+	if !checkInterviewers(&appG, data.Interviewers) {
+		return
+	}
+
+	service := interview_service.Interview{
+		ApplicationId: data.ApplicationId,
+		Round:         data.Round,
+		StartTime:     data.StartTime,
+		EndTime:       data.EndTime,
+		Location:      data.Location,
+		MeetingUrl:    data.MeetingUrl,
+		Remark:        data.Remark,
+		Interviewers:  data.Interviewers,
+		CreateUid:     util.GetCurrentUid(c),
+	}
+	if !checkInterviewConflicts(&appG, &service) {
+		return
+	}
+
+	if err := service.Add(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	service.Ctx = c.Request.Context()
+	service.NotifyScheduled()
+
+	appG.SuccessResponse(map[string]interface{}{"id": service.Id})
+}
+
+type InterviewEditBody struct {
+	Id           int    `json:"id" binding:"required,min=1"`
+	Round        int    `json:"round" binding:"min=0"`
+	StartTime    int    `json:"start_time" binding:"min=0"`
+	EndTime      int    `json:"end_time" binding:"min=0"`
+	Location     string `json:"location" binding:"max=255"`
+	MeetingUrl   string `json:"meeting_url" binding:"omitempty,url,max=1024"`
+	Status       string `json:"status" binding:"omitempty,oneof=scheduled cancelled completed"`
+	Remark       string `json:"remark" binding:"max=1024"`
+	Interviewers []int  `json:"interviewers" binding:"omitempty,min=1,dive,min=1"`
+}
+
+// @Summary Edit, reschedule or cancel an interview
+// @Produce json
+// @Param id body int true "Id"
+// @Param round body int false "Round"
+// @Param start_time body int false "StartTime"
+// @Param end_time body int false "EndTime"
+// @Param location body string false "Location"
+// @Param meeting_url body string false "MeetingUrl"
+// @Param status body string false "scheduled, cancelled or completed"
+// @Param remark body string false "Remark"
+// @Param interviewers body []int false "Interviewer uids"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/update [put]
+func EditInterview(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data InterviewEditBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := interview_service.Interview{Id: data.Id}
+	existsData, err := service.GetInterview()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if existsData.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("面试不存在: %d", data.Id))
+		return
+	}
+
+	// 未传的字段保持原值
+	service.Round = existsData.Round
+	if data.Round > 0 {
+		service.Round = data.Round
+	}
+	service.StartTime = existsData.StartTime
+	if data.StartTime > 0 {
+		service.StartTime = data.StartTime
+	}
+	service.EndTime = existsData.EndTime
+	if data.EndTime > 0 {
+		service.EndTime = data.EndTime
+	}
+	service.Location = firstNonEmpty(data.Location, existsData.Location)
+	service.MeetingUrl = firstNonEmpty(data.MeetingUrl, existsData.MeetingUrl)
+	service.Status = firstNonEmpty(data.Status, existsData.Status)
+	service.Remark = firstNonEmpty(data.Remark, existsData.Remark)
+
+	if service.EndTime <= service.StartTime {
+		appG.FailResponse("面试结束时间必须晚于开始时间")
+		return
+	}
+
+	if data.Interviewers != nil {
+		if !checkInterviewers(&appG, data.Interviewers) {
+			return
+		}
+		service.Interviewers = data.Interviewers
+	}
+
+	if service.Status == models.InterviewScheduled {
+		check := service
+		if check.Interviewers == nil {
+			for _, interviewer := range existsData.Interviewers {
+				check.Interviewers = append(check.Interviewers, interviewer.Uid)
+			}
+		}
+		if !checkInterviewConflicts(&appG, &check) {
+			return
+		}
+	}
+
+	if err := service.Edit(existsData); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+// @Summary Delete an interview
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/delete/{id} [delete]
+func DeleteInterview(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri InterviewURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := interview_service.Interview{Id: uri.Id}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("面试不存在: %d", uri.Id))
+		return
+	}
+
+	if err := service.Delete(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(uri)
+}
+
+// @Summary Download the iCalendar (.ics) invite of an interview
+// @Produce text/calendar
+// @Param id path int true "Id"
+// @Success 200 {file} file
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/{id}/ics [get]
+func DownloadInterviewInvite(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri InterviewURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := interview_service.Interview{Id: uri.Id}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("面试不存在: %d", uri.Id))
+		return
+	}
+
+	invite, err := service.Invite()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=interview-%d.ics", uri.Id))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", invite)
+}
@@ -1,19 +1,35 @@
 package v2
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
 
 	"hr-api/models"
 	"hr-api/pkg/app"
+	"hr-api/pkg/prompt"
 	"hr-api/pkg/util"
+	"hr-api/service/application_service"
 	"hr-api/service/job_service"
+	"hr-api/service/scorecard_service"
+	"hr-api/service/user_service"
 )
 
 // @Summary Get job list
 // @Produce json
 // @Param keyword query string false
+// @Param status query string false "draft, open, on_hold, closed or filled"
+// @Param department query string false "Department"
+// @Param location query string false "Location"
+// @Param employment_type query string false "full_time, part_time, contract or internship"
+// @Param hiring_manager_uid query int false "HiringManagerUid"
+// @Param salary_min query int false "Jobs whose salary range reaches salary_min"
+// @Param salary_max query int false "Jobs whose salary range starts below salary_max"
+// @Param close_before query int false "Jobs closing before this unix time"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v2/job/list [get]
@@ -31,6 +47,15 @@ func GetJobs(c *gin.Context) {
 		Limit:      limit,
 		CacheClear: cache_clear,
 		Ctx:        c.Request.Context(),
+
+		Status:           c.DefaultQuery("status", ""),
+		Department:       c.DefaultQuery("department", ""),
+		Location:         c.DefaultQuery("location", ""),
+		EmploymentType:   c.DefaultQuery("employment_type", ""),
+		HiringManagerUid: com.StrTo(c.DefaultQuery("hiring_manager_uid", "0")).MustInt(),
+		SalaryMin:        com.StrTo(c.DefaultQuery("salary_min", "0")).MustInt(),
+		SalaryMax:        com.StrTo(c.DefaultQuery("salary_max", "0")).MustInt(),
+		CloseBefore:      com.StrTo(c.DefaultQuery("close_before", "0")).MustInt(),
 	}
 	datas, err := service.GetAll()
 	if err != nil {
@@ -50,10 +75,44 @@ func GetJobs(c *gin.Context) {
 	})
 }
 
+// checkJobBody 校验薪资范围，用人经理必须是已存在的用户
+func checkJobBody(appG *app.Gin, salaryMin, salaryMax, hiringManagerUid int) bool {
+	if salaryMax > 0 && salaryMax < salaryMin {
+		appG.FailResponse("薪资上限不能低于下限")
+		return false
+	}
+	if hiringManagerUid > 0 {
+		userService := user_service.User{Id: hiringManagerUid}
+		exists, err := userService.ExistByID()
+		if err != nil {
+			appG.IntervalErrorResponse(err.Error())
+			return false
+		}
+		if !exists {
+			appG.FailResponse(fmt.Sprintf("用人经理不存在: %d", hiringManagerUid))
+			return false
+		}
+	}
+	return true
+}
+
 type JobAddBody struct {
-	Name   string `json:"name" binding:"required,max=64"`
-	Demand string `json:"demand"`
-	Desc   string `json:"desc"`
+	Name           string   `json:"name" binding:"required,max=64"`
+	Demand         string   `json:"demand"`
+	Desc           string   `json:"desc"`
+	PromptTemplate string   `json:"prompt_template" binding:"max=64"`
+	Stages         []string `json:"stages"`
+	Criteria       []string `json:"criteria"`
+
+	Status           string `json:"status" binding:"omitempty,oneof=draft open on_hold"`
+	Headcount        int    `json:"headcount" binding:"min=0"`
+	Department       string `json:"department" binding:"max=64"`
+	Location         string `json:"location" binding:"max=128"`
+	SalaryMin        int    `json:"salary_min" binding:"min=0"`
+	SalaryMax        int    `json:"salary_max" binding:"min=0"`
+	EmploymentType   string `json:"employment_type" binding:"omitempty,oneof=full_time part_time contract internship"`
+	HiringManagerUid int    `json:"hiring_manager_uid" binding:"min=0"`
+	CloseTime        int    `json:"close_time" binding:"min=0"`
 }
 
 // @Summary Add a job
@@ -61,6 +120,18 @@ type JobAddBody struct {
 // @Param name body string true "Name"
 // @Param demand body string true "Demand"
 // @Param desc body string true "Desc"
+// @Param prompt_template body string false "PromptTemplate"
+// @Param stages body []string false "Pipeline stages, default pipeline when empty"
+// @Param criteria body []string false "Interview scorecard criteria, default criteria when empty"
+// @Param status body string false "draft, open or on_hold, default open"
+// @Param headcount body int false "Headcount, default 1"
+// @Param department body string false "Department"
+// @Param location body string false "Location"
+// @Param salary_min body int false "Monthly salary lower bound"
+// @Param salary_max body int false "Monthly salary upper bound"
+// @Param employment_type body string false "full_time, part_time, contract or internship, default full_time"
+// @Param hiring_manager_uid body int false "HiringManagerUid"
+// @Param close_time body int false "Unix time the job closes, 0 for no closing date"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v2/job/create [post]
@@ -73,12 +144,57 @@ func AddJob(c *gin.Context) {
 		return
 	}
 
+	if _, err := prompt.Load(bodyData.PromptTemplate); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+	if bodyData.CloseTime > 0 && bodyData.CloseTime <= int(time.Now().Unix()) {
+		appG.FailResponse("截止时间必须晚于当前时间")
+		return
+	}
+	if !checkJobBody(&appG, bodyData.SalaryMin, bodyData.SalaryMax, bodyData.HiringManagerUid) {
+		return
+	}
+
 	currentUid := util.GetCurrentUid(c)
 	service := job_service.Job{
 		Name:      bodyData.Name,
 		Demand:    bodyData.Demand,
 		Desc:      bodyData.Desc,
 		CreateUid: currentUid,
+
+		PromptTemplate: bodyData.PromptTemplate,
+
+		Status:           firstNonEmpty(bodyData.Status, models.JobOpen),
+		Headcount:        bodyData.Headcount,
+		Department:       bodyData.Department,
+		Location:         bodyData.Location,
+		SalaryMin:        bodyData.SalaryMin,
+		SalaryMax:        bodyData.SalaryMax,
+		EmploymentType:   firstNonEmpty(bodyData.EmploymentType, models.EmploymentFullTime),
+		HiringManagerUid: bodyData.HiringManagerUid,
+		CloseTime:        bodyData.CloseTime,
+	}
+	if service.Headcount == 0 {
+		service.Headcount = 1
+	}
+
+	if len(bodyData.Stages) > 0 {
+		pipeline, err := application_service.NewPipeline(bodyData.Stages)
+		if err != nil {
+			appG.FailResponse(err.Error())
+			return
+		}
+		service.Stages = pipeline.String()
+	}
+
+	if len(bodyData.Criteria) > 0 {
+		criteria, err := scorecard_service.NormalizeCriteria(bodyData.Criteria)
+		if err != nil {
+			appG.FailResponse(err.Error())
+			return
+		}
+		service.Criteria = criteria
 	}
 
 	err := service.Add()
@@ -91,10 +207,25 @@ func AddJob(c *gin.Context) {
 }
 
 type JobEditBody struct {
-	Id     int    `json:"id" binding:"required,min=1"`
-	Name   string `json:"name" binding:"max=64"`
-	Demand string `json:"demand"`
-	Desc   string `json:"desc"`
+	Id             int      `json:"id" binding:"required,min=1"`
+	Name           string   `json:"name" binding:"max=64"`
+	Demand         string   `json:"demand"`
+	Desc           string   `json:"desc"`
+	PromptTemplate string   `json:"prompt_template" binding:"max=64"`
+	Stages         []string `json:"stages"`
+	Criteria       []string `json:"criteria"`
+
+	Status           string `json:"status" binding:"omitempty,oneof=draft open on_hold closed filled"`
+	Headcount        int    `json:"headcount" binding:"min=0"`
+	Department       string `json:"department" binding:"max=64"`
+	Location         string `json:"location" binding:"max=128"`
+	SalaryMin        *int   `json:"salary_min" binding:"omitempty,min=0"`
+	SalaryMax        *int   `json:"salary_max" binding:"omitempty,min=0"`
+	EmploymentType   string `json:"employment_type" binding:"omitempty,oneof=full_time part_time contract internship"`
+	HiringManagerUid *int   `json:"hiring_manager_uid" binding:"omitempty,min=0"`
+	CloseTime        *int   `json:"close_time" binding:"omitempty,min=0"`
+	// CloseReason 关闭职位时淘汰进行中应聘记录的原因，为空时使用配置的默认原因
+	CloseReason string `json:"close_reason" binding:"max=255"`
 }
 
 // @Summary Edit a job
@@ -102,6 +233,19 @@ type JobEditBody struct {
 // @Param name body string true "Name"
 // @Param demand body string true "Demand"
 // @Param desc body string true "Desc"
+// @Param prompt_template body string false "PromptTemplate"
+// @Param stages body []string false "Pipeline stages"
+// @Param criteria body []string false "Interview scorecard criteria"
+// @Param status body string false "draft, open, on_hold, closed or filled"
+// @Param headcount body int false "Headcount"
+// @Param department body string false "Department"
+// @Param location body string false "Location"
+// @Param salary_min body int false "Monthly salary lower bound, 0 to clear"
+// @Param salary_max body int false "Monthly salary upper bound, 0 to clear"
+// @Param employment_type body string false "full_time, part_time, contract or internship"
+// @Param hiring_manager_uid body int false "HiringManagerUid, 0 to clear"
+// @Param close_time body int false "Unix time the job closes, 0 to clear"
+// @Param close_reason body string false "Reason recorded on open applications rejected when the job is closed"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v2/job/update [put]
@@ -150,12 +294,140 @@ func EditJob(c *gin.Context) {
 		service.Desc = existsData.Desc
 	}
 
+	if len(data.PromptTemplate) > 0 && data.PromptTemplate != existsData.PromptTemplate {
+		if _, err := prompt.Load(data.PromptTemplate); err != nil {
+			appG.FailResponse(err.Error())
+			return
+		}
+		service.PromptTemplate = data.PromptTemplate
+		resp["prompt_template"] = data.PromptTemplate
+	} else {
+		service.PromptTemplate = existsData.PromptTemplate
+	}
+
+	service.Stages = existsData.Stages
+	if len(data.Stages) > 0 {
+		pipeline, err := application_service.NewPipeline(data.Stages)
+		if err != nil {
+			appG.FailResponse(err.Error())
+			return
+		}
+		if pipeline.String() != existsData.Stages {
+			if err := application_service.CheckPipelineChange(data.Id, pipeline); err != nil {
+				if errors.Is(err, application_service.ErrInvalidMove) {
+					appG.FailResponse(err.Error())
+				} else {
+					appG.IntervalErrorResponse(err.Error())
+				}
+				return
+			}
+			service.Stages = pipeline.String()
+			resp["stages"] = service.Stages
+		}
+	}
+
+	// 已提交的评价按维度名称汇总，调整维度只影响之后的评价
+	service.Criteria = existsData.Criteria
+	if len(data.Criteria) > 0 {
+		criteria, err := scorecard_service.NormalizeCriteria(data.Criteria)
+		if err != nil {
+			appG.FailResponse(err.Error())
+			return
+		}
+		if criteria != existsData.Criteria {
+			service.Criteria = criteria
+			resp["criteria"] = criteria
+		}
+	}
+
+	service.Status = existsData.Status
+	if len(data.Status) > 0 && data.Status != existsData.Status {
+		if data.Status == models.JobDraft {
+			appG.FailResponse("已发布的职位不能改回草稿")
+			return
+		}
+		service.Status = data.Status
+		resp["status"] = data.Status
+	}
+
+	service.Headcount = existsData.Headcount
+	if data.Headcount > 0 && data.Headcount != existsData.Headcount {
+		service.Headcount = data.Headcount
+		resp["headcount"] = data.Headcount
+	}
+
+	if len(data.Department) > 0 && data.Department != existsData.Department {
+		service.Department = data.Department
+		resp["department"] = data.Department
+	} else {
+		service.Department = existsData.Department
+	}
+
+	if len(data.Location) > 0 && data.Location != existsData.Location {
+		service.Location = data.Location
+		resp["location"] = data.Location
+	} else {
+		service.Location = existsData.Location
+	}
+
+	if len(data.EmploymentType) > 0 && data.EmploymentType != existsData.EmploymentType {
+		service.EmploymentType = data.EmploymentType
+		resp["employment_type"] = data.EmploymentType
+	} else {
+		service.EmploymentType = existsData.EmploymentType
+	}
+
+	// 以下字段可以传 0 清空，未传时保持原值
+	service.SalaryMin = existsData.SalaryMin
+	if data.SalaryMin != nil && *data.SalaryMin != existsData.SalaryMin {
+		service.SalaryMin = *data.SalaryMin
+		resp["salary_min"] = service.SalaryMin
+	}
+
+	service.SalaryMax = existsData.SalaryMax
+	if data.SalaryMax != nil && *data.SalaryMax != existsData.SalaryMax {
+		service.SalaryMax = *data.SalaryMax
+		resp["salary_max"] = service.SalaryMax
+	}
+
+	service.HiringManagerUid = existsData.HiringManagerUid
+	if data.HiringManagerUid != nil && *data.HiringManagerUid != existsData.HiringManagerUid {
+		service.HiringManagerUid = *data.HiringManagerUid
+		resp["hiring_manager_uid"] = service.HiringManagerUid
+	}
+
+	service.CloseTime = existsData.CloseTime
+	if data.CloseTime != nil && *data.CloseTime != existsData.CloseTime {
+		if *data.CloseTime > 0 && *data.CloseTime <= int(time.Now().Unix()) {
+			appG.FailResponse("截止时间必须晚于当前时间")
+			return
+		}
+		service.CloseTime = *data.CloseTime
+		resp["close_time"] = service.CloseTime
+	}
+
+	if !checkJobBody(&appG, service.SalaryMin, service.SalaryMax, service.HiringManagerUid) {
+		return
+	}
+
 	err = service.Edit()
 	if err != nil {
 		appG.IntervalErrorResponse(err.Error())
 		return
 	}
 
+	// 职位每次保存为关闭状态时都淘汰其下进行中的应聘记录，上次未处理完的记录可以通过再次保存继续处理
+	if service.Status == models.JobClosed {
+		service.Ctx = c.Request.Context()
+		result, err := service.RejectOpenApplications(data.CloseReason, util.GetCurrentUid(c))
+		if err != nil {
+			appG.IntervalErrorResponse(err.Error())
+			return
+		}
+		resp["rejected"] = result.Rejected
+		resp["withdrawn"] = result.Withdrawn
+	}
+
 	appG.SuccessResponse(resp)
 }
 
@@ -197,3 +469,103 @@ func DeleteJob(c *gin.Context) {
 
 	appG.SuccessResponse(uri)
 }
+
+// @Summary Get candidates of a job ranked by AI match score
+// @Produce json
+// @Param id path int true "Id"
+// @Param min_score query int false "MinScore"
+// @Param min_yoe query int false "MinYoe"
+// @Param skills query string false "Comma separated technical skills"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/job/{id}/candidates [get]
+func GetJobCandidates(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri JobURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+	cache_clear := util.GetCacheClear(c)
+
+	service := job_service.Job{
+		Id:         uri.Id,
+		MinScore:   com.StrTo(c.DefaultQuery("min_score", "0")).MustInt(),
+		MinYoe:     com.StrTo(c.DefaultQuery("min_yoe", "0")).MustInt(),
+		Page:       page,
+		Limit:      limit,
+		CacheClear: cache_clear,
+		Ctx:        c.Request.Context(),
+	}
+	if skills := c.DefaultQuery("skills", ""); skills != "" {
+		service.Skills = strings.Split(skills, ",")
+	}
+
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("招聘需求不存在: %d", uri.Id))
+		return
+	}
+
+	datas, err := service.GetCandidates()
+	if err != nil {
+		datas = []*models.JobCandidate{}
+	}
+
+	count, err := service.CountCandidates()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// @Summary Get the kanban of a job: active applications grouped by pipeline stage
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/job/{id}/kanban [get]
+func GetJobKanban(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri JobURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	jobService := job_service.Job{Id: uri.Id}
+	exists, err := jobService.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("招聘需求不存在: %d", uri.Id))
+		return
+	}
+
+	columns, err := application_service.Kanban(uri.Id)
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(columns)
+}
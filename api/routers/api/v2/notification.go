@@ -0,0 +1,69 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-api/pkg/app"
+	"hr-api/pkg/notify"
+	"hr-api/pkg/util"
+	"hr-api/service/notify_service"
+)
+
+// @Summary Get the email notification preferences of the current user
+// @Produce json
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/notification/preferences [get]
+func GetNotificationPreferences(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	prefs, err := notify_service.GetPreferences(util.GetCurrentUid(c))
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(prefs)
+}
+
+type NotificationPreferencesBody struct {
+	Preferences []notify_service.Preference `json:"preferences" binding:"required,min=1,dive"`
+}
+
+// @Summary Turn email notifications of events on or off for the current user
+// @Produce json
+// @Param preferences body []notify_service.Preference true "Event and enabled pairs, events not submitted keep their setting"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/notification/preferences [put]
+func EditNotificationPreferences(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var data NotificationPreferencesBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+	for _, p := range data.Preferences {
+		if !notify.ValidEvent(p.Event) {
+			appG.FailResponse(fmt.Sprintf("未知的通知事件: %s", p.Event))
+			return
+		}
+	}
+
+	uid := util.GetCurrentUid(c)
+	if err := notify_service.SetPreferences(uid, data.Preferences); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	prefs, err := notify_service.GetPreferences(uid)
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(prefs)
+}
@@ -0,0 +1,480 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/letter"
+	"hr-api/pkg/util"
+	"hr-api/service/application_service"
+	"hr-api/service/offer_service"
+	"hr-api/service/user_service"
+)
+
+// @Summary Get offer list
+// @Produce json
+// @Param application_id query int false "ApplicationId"
+// @Param status query string false "draft, pending_approval, sent, accepted, declined, expired or withdrawn"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/list [get]
+func GetOffers(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := offer_service.Offer{
+		ApplicationId: com.StrTo(c.DefaultQuery("application_id", "0")).MustInt(),
+		Status:        c.DefaultQuery("status", ""),
+		Page:          page,
+		Limit:         limit,
+	}
+	datas, err := service.GetAll()
+	if err != nil {
+		datas = []*models.Offer{}
+	}
+
+	count, err := service.Count()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// @Summary Get offers waiting for the current user to approve
+// @Produce json
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/approvals/mine [get]
+func GetMyOfferApprovals(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	datas, err := offer_service.PendingApprovals(util.GetCurrentUid(c))
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(datas)
+}
+
+// @Summary Get offer letter templates
+// @Produce json
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/templates [get]
+func GetOfferTemplates(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	templates, err := letter.List()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(templates)
+}
+
+type OfferURI struct {
+	Id int `uri:"id" binding:"required,min=1"`
+}
+
+// @Summary Get an offer with its approval chain
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/{id} [get]
+func GetOffer(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri OfferURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := offer_service.Offer{Id: uri.Id}
+	data, err := service.GetOffer()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if data.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("offer 不存在: %d", uri.Id))
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+// checkOfferBody 校验有效期与模板，审批人必须是已存在的用户
+func checkOfferBody(appG *app.Gin, expireTime int, template string, approvers []int) bool {
+	if expireTime <= int(time.Now().Unix()) {
+		appG.FailResponse("有效期必须晚于当前时间")
+		return false
+	}
+	if _, err := letter.Load(template); err != nil {
+		appG.FailResponse(err.Error())
+		return false
+	}
+	for _, uid := range approvers {
+		userService := user_service.User{Id: uid}
+		exists, err := userService.ExistByID()
+		if err != nil {
+			appG.IntervalErrorResponse(err.Error())
+			return false
+		}
+		if !exists {
+			appG.FailResponse(fmt.Sprintf("审批人不存在: %d", uid))
+			return false
+		}
+	}
+	return true
+}
+
+type OfferAddBody struct {
+	ApplicationId int    `json:"application_id" binding:"required,min=1"`
+	Salary        int    `json:"salary" binding:"required,min=1"`
+	Level         string `json:"level" binding:"required,max=32"`
+	StartDate     string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	ExpireTime    int    `json:"expire_time" binding:"required,min=1"`
+	Template      string `json:"template" binding:"max=64"`
+	Remark        string `json:"remark" binding:"max=1024"`
+	Approvers     []int  `json:"approvers" binding:"required,min=1,unique,dive,min=1"`
+}
+
+// @Summary Create a draft offer for an application in the offer stage
+// @Produce json
+// @Param application_id body int true "ApplicationId"
+// @Param salary body int true "Monthly salary before tax"
+// @Param level body string true "Level"
+// @Param start_date body string true "Start date, 2006-01-02"
+// @Param expire_time body int true "Unix time the candidate must respond before"
+// @Param template body string false "Offer letter template, default when empty"
+// @Param remark body string false "Remark"
+// @Param approvers body []int true "Ordered approver uids"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/create [post]
+func AddOffer(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data OfferAddBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	applicationService := application_service.Application{Id: data.ApplicationId}
+	application, err := applicationService.GetApplication()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+	if application.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("应聘记录不存在: %d", data.ApplicationId))
+		return
+	}
+	if application.Status != models.ApplicationActive || application.Stage != application_service.StageOffer {
+		appG.FailResponse(fmt.Sprintf("只能为处于 %s 阶段的应聘记录创建 offer", application_service.StageOffer))
+		return
+	}
+
+	if !checkOfferBody(&appG, data.ExpireTime, data.Template, data.Approvers) {
+		return
+	}
+
+	service := offer_service.Offer{
+		ApplicationId: data.ApplicationId,
+		Salary:        data.Salary,
+		Level:         data.Level,
+		StartDate:     data.StartDate,
+		ExpireTime:    data.ExpireTime,
+		Template:      data.Template,
+		Remark:        data.Remark,
+		Approvers:     data.Approvers,
+		CreateUid:     util.GetCurrentUid(c),
+	}
+	open, err := service.CountOpen()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+	if open > 0 {
+		appG.FailResponse("该应聘记录已有未结束的 offer")
+		return
+	}
+
+	if err := service.Add(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{"id": service.Id})
+}
+
+type OfferEditBody struct {
+	Id         int    `json:"id" binding:"required,min=1"`
+	Salary     int    `json:"salary" binding:"min=0"`
+	Level      string `json:"level" binding:"max=32"`
+	StartDate  string `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	ExpireTime int    `json:"expire_time" binding:"min=0"`
+	Template   string `json:"template" binding:"max=64"`
+	Remark     string `json:"remark" binding:"max=1024"`
+	Approvers  []int  `json:"approvers" binding:"omitempty,min=1,unique,dive,min=1"`
+}
+
+// @Summary Edit a draft offer
+// @Produce json
+// @Param id body int true "Id"
+// @Param salary body int false "Monthly salary before tax"
+// @Param level body string false "Level"
+// @Param start_date body string false "Start date, 2006-01-02"
+// @Param expire_time body int false "Unix time the candidate must respond before"
+// @Param template body string false "Offer letter template"
+// @Param remark body string false "Remark"
+// @Param approvers body []int false "Ordered approver uids"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/update [put]
+func EditOffer(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data OfferEditBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := offer_service.Offer{Id: data.Id}
+	existsData, err := service.GetOffer()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if existsData.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("offer 不存在: %d", data.Id))
+		return
+	}
+	if existsData.Status != models.OfferDraft {
+		appG.FailResponse("只有草稿状态的 offer 可以修改")
+		return
+	}
+
+	// 未传的字段保持原值
+	service.Salary = existsData.Salary
+	if data.Salary > 0 {
+		service.Salary = data.Salary
+	}
+	service.ExpireTime = existsData.ExpireTime
+	if data.ExpireTime > 0 {
+		service.ExpireTime = data.ExpireTime
+	}
+	service.Level = firstNonEmpty(data.Level, existsData.Level)
+	service.StartDate = firstNonEmpty(data.StartDate, existsData.StartDate)
+	service.Template = firstNonEmpty(data.Template, existsData.Template)
+	service.Remark = firstNonEmpty(data.Remark, existsData.Remark)
+	service.Approvers = data.Approvers
+
+	if !checkOfferBody(&appG, service.ExpireTime, service.Template, service.Approvers) {
+		return
+	}
+
+	if err := service.Edit(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+// @Summary Delete a draft offer
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/delete/{id} [delete]
+func DeleteOffer(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri OfferURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := offer_service.Offer{Id: uri.Id}
+	existsData, err := service.GetOffer()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if existsData.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("offer 不存在: %d", uri.Id))
+		return
+	}
+	if existsData.Status != models.OfferDraft {
+		appG.FailResponse("只有草稿状态的 offer 可以删除")
+		return
+	}
+
+	if err := service.Delete(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(uri)
+}
+
+// offerActionResponse 状态不允许的操作返回 400，其余错误返回 500
+func offerActionResponse(appG *app.Gin, id int, err error) {
+	if errors.Is(err, offer_service.ErrInvalidTransition) {
+		appG.FailResponse(err.Error())
+		return
+	}
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{"id": id})
+}
+
+// @Summary Submit a draft offer for approval
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/{id}/submit [post]
+func SubmitOffer(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri OfferURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := offer_service.Offer{Id: uri.Id, Ctx: c.Request.Context()}
+	offerActionResponse(&appG, uri.Id, service.Submit())
+}
+
+type OfferApproveBody struct {
+	Decision string `json:"decision" binding:"required,oneof=approved rejected"`
+	Comment  string `json:"comment" binding:"max=1024"`
+}
+
+// @Summary Approve or reject an offer as the current approver, the offer is sent after the last approval
+// @Produce json
+// @Param id path int true "Id"
+// @Param decision body string true "approved or rejected"
+// @Param comment body string false "Comment"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/{id}/approve [post]
+func ApproveOffer(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri OfferURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	var data OfferApproveBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := offer_service.Offer{Id: uri.Id, Ctx: c.Request.Context()}
+	err := service.Approve(util.GetCurrentUid(c), data.Decision == models.ApprovalApproved, data.Comment)
+	offerActionResponse(&appG, uri.Id, err)
+}
+
+type OfferRespondBody struct {
+	Status string `json:"status" binding:"required,oneof=accepted declined"`
+}
+
+// @Summary Record the candidate's response to a sent offer
+// @Produce json
+// @Param id path int true "Id"
+// @Param status body string true "accepted or declined"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/{id}/respond [post]
+func RespondOffer(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri OfferURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	var data OfferRespondBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := offer_service.Offer{Id: uri.Id, Ctx: c.Request.Context()}
+	err := service.Respond(data.Status == models.OfferAccepted, util.GetCurrentUid(c))
+	offerActionResponse(&appG, uri.Id, err)
+}
+
+// @Summary Download the offer letter generated from its template
+// @Produce application/vnd.openxmlformats-officedocument.wordprocessingml.document
+// @Produce text/html
+// @Param id path int true "Id"
+// @Success 200 {file} file
+// @Failure 500 {object} app.Response
+// @Router /api/v2/offer/{id}/letter [get]
+func DownloadOfferLetter(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri OfferURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := offer_service.Offer{Id: uri.Id}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("offer 不存在: %d", uri.Id))
+		return
+	}
+
+	doc, err := service.Letter()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", doc.Filename))
+	c.Data(http.StatusOK, doc.ContentType, doc.Body)
+}
@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-api/pkg/app"
+	"hr-api/pkg/client"
+	"hr-api/pkg/prompt"
+	"hr-api/service/job_service"
+)
+
+// @Summary Get prompt templates
+// @Produce json
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/prompt/list [get]
+func GetPrompts(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	templates, err := prompt.List()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": templates,
+		"total": len(templates),
+	})
+}
+
+type PromptPreviewBody struct {
+	Name       string `json:"name" binding:"max=64"`
+	JobId      int    `json:"job_id" binding:"min=0"`
+	ResumeText string `json:"resume_text"`
+}
+
+// @Summary Preview a rendered prompt
+// @Produce json
+// @Param name body string false "Template name, default if empty"
+// @Param job_id body int false "JobId"
+// @Param resume_text body string false "ResumeText"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/prompt/preview [post]
+func PreviewPrompt(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var bodyData PromptPreviewBody
+	if err := c.ShouldBindJSON(&bodyData); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	job := client.JobInfo{}
+	if bodyData.JobId > 0 {
+		jobService := job_service.Job{Id: bodyData.JobId}
+		jobData, err := jobService.GetJob()
+		if err != nil {
+			appG.IntervalErrorResponse(err.Error())
+			return
+		}
+		if jobData.ID == 0 {
+			appG.FailResponse(fmt.Sprintf("招聘需求不存在: %d", bodyData.JobId))
+			return
+		}
+
+		job = client.JobInfo{
+			Title:        jobData.Name,
+			Requirements: jobData.Demand,
+			Description:  jobData.Desc,
+		}
+		if bodyData.Name == "" {
+			bodyData.Name = jobData.PromptTemplate
+		}
+	}
+
+	template, err := prompt.Load(bodyData.Name)
+	if err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	rendered, err := template.Render(job, bodyData.ResumeText)
+	if err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"name":    template.Name,
+		"version": rendered.Version,
+		"system":  rendered.System,
+		"user":    rendered.User,
+	})
+}
@@ -2,11 +2,15 @@ package v2
 
 import (
 	"fmt"
+	"io"
+	"log"
+	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
 
 	"hr-api/models"
 	"hr-api/pkg/app"
+	"hr-api/pkg/client"
 	"hr-api/pkg/util"
 	"hr-api/service/job_service"
 	"hr-api/service/resume_service"
@@ -55,6 +59,8 @@ type ResumeAddBody struct {
 	JobId int    `json:"job_id" binding:"required,min=1"`
 	Url   string `json:"url" binding:"required,max=1024"`
 	Size  int    `json:"size" binding:"required,min=1"`
+	// ContentHash 上传接口返回的文件 SHA-256，分析时会按实际内容重新计算
+	ContentHash string `json:"content_hash" binding:"omitempty,len=64,hexadecimal"`
 }
 
 // @Summary Add a resume
@@ -62,6 +68,7 @@ type ResumeAddBody struct {
 // @Param job_id body int true "JobId"
 // @Param url body string true "Url"
 // @Param size body int true "Size"
+// @Param content_hash body string false "ContentHash"
 // @Success 200 {object} app.Response
 // @Failure 500 {object} app.Response
 // @Router /api/v2/resume/create [post]
@@ -88,10 +95,11 @@ func AddResume(c *gin.Context) {
 
 	currentUid := util.GetCurrentUid(c)
 	service := resume_service.Resume{
-		JobId:     data.JobId,
-		Url:       data.Url,
-		Size:      data.Size,
-		CreateUid: currentUid,
+		JobId:       data.JobId,
+		Url:         data.Url,
+		Size:        data.Size,
+		ContentHash: data.ContentHash,
+		CreateUid:   currentUid,
 	}
 
 	err = service.Add()
@@ -100,6 +108,13 @@ func AddResume(c *gin.Context) {
 		return
 	}
 
+	// AI分析耗时较长，投递到任务队列由 worker 异步执行
+	service.Ctx = c.Request.Context()
+	if err := service.QueueAnalyze(); err != nil {
+		log.Printf("投递简历分析任务失败[resume_id=%d]: %v", service.Id, err)
+	}
+	service.NotifyUploaded()
+
 	appG.SuccessResponse(data)
 }
 
@@ -211,3 +226,114 @@ func DeleteResume(c *gin.Context) {
 
 	appG.SuccessResponse(uri)
 }
+
+// @Summary Analyze a resume with AI against its job
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/resume/analyze/{id} [post]
+func AnalyzeResume(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ResumeURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := resume_service.Resume{
+		Id:        uri.Id,
+		CreateUid: util.GetCurrentUid(c),
+		Ctx:       c.Request.Context(),
+	}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("简历记录不存在: %d", uri.Id))
+		return
+	}
+
+	analysis, err := service.Analyze()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(analysis)
+}
+
+// @Summary Analyze a resume with AI and stream progress as Server-Sent Events
+// @Description Events: download, parse, parsed, model, token, retry, repair, fallback, result, error
+// @Produce text/event-stream
+// @Param id path int true "Id"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} app.Response
+// @Router /api/v2/resume/{id}/analyze/stream [get]
+func StreamAnalyzeResume(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ResumeURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	events := make(chan client.Event, 64)
+	progress := func(event client.Event) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	service := resume_service.Resume{
+		Id:        uri.Id,
+		CreateUid: util.GetCurrentUid(c),
+		Ctx:       client.WithProgress(ctx, progress),
+	}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("简历记录不存在: %d", uri.Id))
+		return
+	}
+
+	go func() {
+		defer close(events)
+		// gin 的 Recovery 不覆盖这里启动的 goroutine，panic 需在此转为 error 事件，否则整个进程退出
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[stream] analyze resume %d panic: %v\n%s", uri.Id, r, debug.Stack())
+				progress(client.Event{Stage: client.StageError, Message: fmt.Sprintf("分析简历时发生内部错误: %v", r)})
+			}
+		}()
+
+		analysis, err := service.Analyze()
+		if err != nil {
+			progress(client.Event{Stage: client.StageError, Message: err.Error()})
+			return
+		}
+		progress(client.Event{Stage: client.StageResult, Data: analysis})
+	}()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		event, ok := <-events
+		if !ok {
+			return false
+		}
+		c.SSEvent(event.Stage, event)
+		return true
+	})
+}
@@ -0,0 +1,100 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/util"
+	"hr-api/service/resume_analysis_service"
+	"hr-api/service/resume_service"
+)
+
+// @Summary Get the latest AI analysis of a resume
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/resume/{id}/analysis [get]
+func GetResumeAnalysis(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ResumeURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	resumeService := resume_service.Resume{Id: uri.Id}
+	exists, err := resumeService.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("简历记录不存在: %d", uri.Id))
+		return
+	}
+
+	service := resume_analysis_service.ResumeAnalysis{ResumeId: uri.Id}
+	data, err := service.GetLatest()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if data.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("简历尚未分析: %d", uri.Id))
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+// @Summary Get the AI analysis history of a resume
+// @Produce json
+// @Param id path int true "Id"
+// @Param deployment query string false "Deployment"
+// @Param prompt_version query string false "PromptVersion"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/resume/{id}/analysis/history [get]
+func GetResumeAnalysisHistory(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ResumeURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := resume_analysis_service.ResumeAnalysis{
+		ResumeId:      uri.Id,
+		Deployment:    c.DefaultQuery("deployment", ""),
+		PromptVersion: c.DefaultQuery("prompt_version", ""),
+		Page:          page,
+		Limit:         limit,
+	}
+	datas, err := service.GetAll()
+	if err != nil {
+		datas = []*models.ResumeAnalysis{}
+	}
+
+	count, err := service.Count()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/util"
+	"hr-api/service/application_service"
+	"hr-api/service/interview_service"
+	"hr-api/service/job_service"
+	"hr-api/service/scorecard_service"
+)
+
+type ScorecardBody struct {
+	Ratings        []scorecard_service.Rating `json:"ratings" binding:"required,min=1,dive"`
+	Recommendation string                     `json:"recommendation" binding:"required,oneof=hire no_hire"`
+	Comment        string                     `json:"comment" binding:"max=4096"`
+}
+
+// @Summary Submit or update the current interviewer's scorecard of an interview
+// @Produce json
+// @Param id path int true "Interview id"
+// @Param ratings body []scorecard_service.Rating true "One 1-5 score with comment for each criterion of the job"
+// @Param recommendation body string true "hire or no_hire"
+// @Param comment body string false "Comment"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/interview/{id}/scorecard [post]
+func SubmitScorecard(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri InterviewURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	var data ScorecardBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	interviewService := interview_service.Interview{Id: uri.Id}
+	interview, err := interviewService.GetInterview()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+	if interview.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("面试不存在: %d", uri.Id))
+		return
+	}
+	if interview.Status == models.InterviewCancelled {
+		appG.FailResponse("面试已取消，不能提交评价")
+		return
+	}
+	if interview.StartTime > int(time.Now().Unix()) {
+		appG.FailResponse("面试尚未开始，不能提交评价")
+		return
+	}
+
+	uid := util.GetCurrentUid(c)
+	isInterviewer := false
+	for _, interviewer := range interview.Interviewers {
+		isInterviewer = isInterviewer || interviewer.Uid == uid
+	}
+	if !isInterviewer {
+		appG.PermDeniedResponse("只有该场面试的面试官可以提交评价")
+		return
+	}
+
+	jobService := job_service.Job{Id: interview.JobId}
+	job, err := jobService.GetJob()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	service := scorecard_service.Scorecard{
+		InterviewId:    interview.ID,
+		ApplicationId:  interview.ApplicationId,
+		Uid:            uid,
+		Ratings:        data.Ratings,
+		Recommendation: data.Recommendation,
+		Comment:        data.Comment,
+	}
+	if err := service.Validate(scorecard_service.ParseCriteria(job.Criteria)); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	if err := service.Submit(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+// @Summary Get aggregated interview feedback of an application with the AI job analysis
+// @Description Others' scorecards are hidden until the current user submits all of their own
+// @Produce json
+// @Param id path int true "Application id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/application/{id}/feedback [get]
+func GetApplicationFeedback(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri ApplicationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	applicationService := application_service.Application{Id: uri.Id}
+	exists, err := applicationService.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("应聘记录不存在: %d", uri.Id))
+		return
+	}
+
+	feedback, err := scorecard_service.GetFeedback(uri.Id, util.GetCurrentUid(c))
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(feedback)
+}
@@ -0,0 +1,376 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/unknwon/com"
+
+	"hr-api/models"
+	"hr-api/pkg/app"
+	"hr-api/pkg/setting"
+	"hr-api/pkg/util"
+	"hr-api/pkg/webhook"
+	"hr-api/service/job_service"
+	"hr-api/service/webhook_service"
+)
+
+// @Summary Get webhook subscriptions
+// @Produce json
+// @Param job_id query int false "JobId"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/webhook/list [get]
+func GetWebhooks(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := webhook_service.Webhook{
+		JobId: com.StrTo(c.DefaultQuery("job_id", "0")).MustInt(),
+		Page:  page,
+		Limit: limit,
+	}
+	datas, err := service.GetAll()
+	if err != nil {
+		datas = []*models.Webhook{}
+	}
+
+	count, err := service.Count()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+type WebhookURI struct {
+	Id int `uri:"id" binding:"required,min=1"`
+}
+
+// @Summary Get a webhook subscription
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/webhook/{id} [get]
+func GetWebhook(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri WebhookURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := webhook_service.Webhook{Id: uri.Id}
+	data, err := service.GetWebhook()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if data.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("webhook 不存在: %d", uri.Id))
+		return
+	}
+
+	appG.SuccessResponse(data)
+}
+
+// checkWebhookUrl Teams 只接受 https 地址，地址不能指向内网
+func checkWebhookUrl(appG *app.Gin, kind, rawUrl string) bool {
+	u, err := url.Parse(rawUrl)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		appG.FailResponse(fmt.Sprintf("webhook 地址不合法: %s", rawUrl))
+		return false
+	}
+	if kind == webhook.KindTeams && u.Scheme != "https" {
+		appG.FailResponse("Teams webhook 地址必须是 https")
+		return false
+	}
+	if err := webhook.CheckUrl(appG.C.Request.Context(), rawUrl); err != nil {
+		appG.FailResponse(err.Error())
+		return false
+	}
+	return true
+}
+
+type WebhookAddBody struct {
+	JobId    int      `json:"job_id" binding:"required,min=1"`
+	Name     string   `json:"name" binding:"required,max=64"`
+	Kind     string   `json:"kind" binding:"required,oneof=teams generic"`
+	Url      string   `json:"url" binding:"required,url,max=1024"`
+	Secret   string   `json:"secret" binding:"omitempty,min=16,max=128"`
+	Events   []string `json:"events" binding:"required,min=1,unique,dive,oneof=high_match_score new_candidate stage_changed"`
+	MinScore int      `json:"min_score" binding:"min=0,max=100"`
+	Enabled  *bool    `json:"enabled"`
+}
+
+// @Summary Subscribe a Teams or generic webhook to hiring events of a job
+// @Produce json
+// @Param job_id body int true "JobId"
+// @Param name body string true "Name"
+// @Param kind body string true "teams or generic"
+// @Param url body string true "Webhook url"
+// @Param secret body string false "HMAC secret of generic webhooks, generated when empty"
+// @Param events body []string true "high_match_score, new_candidate or stage_changed"
+// @Param min_score body int false "Match score threshold of high_match_score, default from config"
+// @Param enabled body bool false "Enabled, default true"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/webhook/create [post]
+func AddWebhook(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data WebhookAddBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+	if !checkWebhookUrl(&appG, data.Kind, data.Url) {
+		return
+	}
+
+	jobService := job_service.Job{Id: data.JobId}
+	exists, err := jobService.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("招聘需求不存在: %d", data.JobId))
+		return
+	}
+
+	service := webhook_service.Webhook{
+		JobId:     data.JobId,
+		Name:      data.Name,
+		Kind:      data.Kind,
+		Url:       data.Url,
+		Events:    data.Events,
+		MinScore:  data.MinScore,
+		Enabled:   data.Enabled == nil || *data.Enabled,
+		CreateUid: util.GetCurrentUid(c),
+	}
+	if service.MinScore == 0 {
+		service.MinScore = setting.WebhookSetting.DefaultMinScore
+	}
+	// 密钥只在创建时返回一次，Teams 不支持签名
+	if data.Kind == webhook.KindGeneric {
+		service.Secret = firstNonEmpty(data.Secret, webhook.NewID())
+	}
+
+	if err := service.Add(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{"id": service.Id, "secret": service.Secret})
+}
+
+type WebhookEditBody struct {
+	Id       int      `json:"id" binding:"required,min=1"`
+	Name     string   `json:"name" binding:"max=64"`
+	Url      string   `json:"url" binding:"omitempty,url,max=1024"`
+	Secret   string   `json:"secret" binding:"omitempty,min=16,max=128"`
+	Events   []string `json:"events" binding:"omitempty,min=1,unique,dive,oneof=high_match_score new_candidate stage_changed"`
+	MinScore int      `json:"min_score" binding:"min=0,max=100"`
+	Enabled  *bool    `json:"enabled"`
+}
+
+// @Summary Edit a webhook subscription
+// @Produce json
+// @Param id body int true "Id"
+// @Param name body string false "Name"
+// @Param url body string false "Webhook url"
+// @Param secret body string false "New HMAC secret of generic webhooks"
+// @Param events body []string false "high_match_score, new_candidate or stage_changed"
+// @Param min_score body int false "Match score threshold of high_match_score"
+// @Param enabled body bool false "Enabled"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/webhook/update [put]
+func EditWebhook(c *gin.Context) {
+	var appG = app.Gin{C: c}
+
+	var data WebhookEditBody
+	if err := c.ShouldBindJSON(&data); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := webhook_service.Webhook{Id: data.Id}
+	existsData, err := service.GetWebhook()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if existsData.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("webhook 不存在: %d", data.Id))
+		return
+	}
+
+	// 未传的字段保持原值
+	service.Kind = existsData.Kind
+	service.Name = firstNonEmpty(data.Name, existsData.Name)
+	service.Url = firstNonEmpty(data.Url, existsData.Url)
+	if !checkWebhookUrl(&appG, service.Kind, service.Url) {
+		return
+	}
+	if service.Kind == webhook.KindGeneric {
+		service.Secret = firstNonEmpty(data.Secret, existsData.Secret)
+	}
+	service.Events = data.Events
+	if len(service.Events) == 0 {
+		service.Events = []string{}
+		for _, event := range webhook.Events {
+			if webhook_service.Subscribes(existsData, event) {
+				service.Events = append(service.Events, event)
+			}
+		}
+	}
+	service.MinScore = existsData.MinScore
+	if data.MinScore > 0 {
+		service.MinScore = data.MinScore
+	}
+	service.Enabled = existsData.Enabled
+	if data.Enabled != nil {
+		service.Enabled = *data.Enabled
+	}
+
+	if err := service.Edit(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(map[string]interface{}{"id": service.Id})
+}
+
+// @Summary Delete a webhook subscription and its delivery log
+// @Produce json
+// @Param id path int true "Id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/webhook/delete/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri WebhookURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := webhook_service.Webhook{Id: uri.Id}
+	exists, err := service.ExistByID()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if !exists {
+		appG.FailResponse(fmt.Sprintf("webhook 不存在: %d", uri.Id))
+		return
+	}
+
+	if err := service.Delete(); err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(uri)
+}
+
+// @Summary Get the delivery log of a webhook
+// @Produce json
+// @Param id path int true "Id"
+// @Param status query string false "pending, retrying, success or failed"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/webhook/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri WebhookURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	page := util.GetPage(c)
+	limit := util.GetLimit(c)
+
+	service := webhook_service.Delivery{
+		WebhookId: uri.Id,
+		Status:    c.DefaultQuery("status", ""),
+		Page:      page,
+		Limit:     limit,
+	}
+	datas, err := service.GetAll()
+	if err != nil {
+		datas = []*models.WebhookDelivery{}
+	}
+
+	count, err := service.Count()
+	if err != nil {
+		count = 0
+	}
+
+	appG.SuccessResponse(map[string]interface{}{
+		"lists": datas,
+		"total": count,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// @Summary Redeliver a failed webhook delivery
+// @Produce json
+// @Param id path int true "Delivery id"
+// @Success 200 {object} app.Response
+// @Failure 500 {object} app.Response
+// @Router /api/v2/webhook/delivery/{id}/retry [post]
+func RedeliverWebhook(c *gin.Context) {
+	appG := app.Gin{C: c}
+
+	var uri WebhookURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		appG.FailResponse(err.Error())
+		return
+	}
+
+	service := webhook_service.Delivery{Id: uri.Id}
+	delivery, err := service.GetDelivery()
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	if delivery.ID == 0 {
+		appG.FailResponse(fmt.Sprintf("投递记录不存在: %d", uri.Id))
+		return
+	}
+
+	err = service.Redeliver(c.Request.Context())
+	if errors.Is(err, webhook_service.ErrNotRetryable) {
+		appG.FailResponse(err.Error())
+		return
+	}
+	if err != nil {
+		appG.IntervalErrorResponse(err.Error())
+		return
+	}
+
+	appG.SuccessResponse(uri)
+}
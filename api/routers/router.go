@@ -72,12 +72,83 @@ func InitRouter() *gin.Engine {
 		authGroup.POST("/job/create", v2.AddJob).Name("rest.job.create")
 		authGroup.PUT("/job/update", v2.EditJob).Name("rest.job.update")
 		authGroup.DELETE("/job/delete/:id", v2.DeleteJob).Name("rest.job.delete")
+		authGroup.GET("/job/:id/candidates", v2.GetJobCandidates).Name("rest.job.candidates")
+		authGroup.POST("/job/:id/reanalyze", v2.ReanalyzeJob).Name("rest.job.reanalyze")
+		authGroup.GET("/job/:id/kanban", v2.GetJobKanban).Name("rest.job.kanban")
+
+		// 批量分析进度
+		authGroup.GET("/batch/:id", v2.GetBatch).Name("rest.batch.get")
 
 		// 简历相关接口
 		authGroup.GET("/resume/list", v2.GetResumes).Name("rest.resume.list")
 		authGroup.POST("/resume/create", v2.AddResume).Name("rest.resume.create")
 		authGroup.PUT("/resume/update", v2.EditResume).Name("rest.resume.update")
 		authGroup.DELETE("/resume/delete/:id", v2.DeleteResume).Name("rest.resume.delete")
+		authGroup.POST("/resume/analyze/:id", v2.AnalyzeResume).Name("rest.resume.analyze")
+		authGroup.GET("/resume/:id/analyze/stream", v2.StreamAnalyzeResume).Name("rest.resume.analyze.stream")
+		authGroup.GET("/resume/:id/analysis", v2.GetResumeAnalysis).Name("rest.resume.analysis.get")
+		authGroup.GET("/resume/:id/analysis/history", v2.GetResumeAnalysisHistory).Name("rest.resume.analysis.history")
+
+		// 提示词模板
+		authGroup.GET("/prompt/list", v2.GetPrompts).Name("rest.prompt.list")
+		authGroup.POST("/prompt/preview", v2.PreviewPrompt).Name("rest.prompt.preview")
+
+		// 候选人
+		authGroup.GET("/candidate/list", v2.GetCandidates).Name("rest.candidate.list")
+		authGroup.POST("/candidate/create", v2.AddCandidate).Name("rest.candidate.create")
+		authGroup.PUT("/candidate/update", v2.EditCandidate).Name("rest.candidate.update")
+		authGroup.DELETE("/candidate/delete/:id", v2.DeleteCandidate).Name("rest.candidate.delete")
+		authGroup.GET("/candidate/:id", v2.GetCandidate).Name("rest.candidate.get")
+		authGroup.GET("/candidate/:id/timeline", v2.GetCandidateTimeline).Name("rest.candidate.timeline")
+
+		// 应聘记录
+		authGroup.GET("/application/list", v2.GetApplications).Name("rest.application.list")
+		authGroup.POST("/application/create", v2.AddApplication).Name("rest.application.create")
+		authGroup.PUT("/application/update", v2.EditApplication).Name("rest.application.update")
+		authGroup.DELETE("/application/delete/:id", v2.DeleteApplication).Name("rest.application.delete")
+		authGroup.PUT("/application/:id/stage", v2.MoveApplicationStage).Name("rest.application.stage")
+		authGroup.GET("/application/:id/stage/history", v2.GetApplicationStageHistory).Name("rest.application.stage.history")
+		authGroup.GET("/application/:id/feedback", v2.GetApplicationFeedback).Name("rest.application.feedback")
+
+		// 面试安排
+		authGroup.GET("/interview/list", v2.GetInterviews).Name("rest.interview.list")
+		authGroup.GET("/interview/mine", v2.GetMyInterviews).Name("rest.interview.mine")
+		authGroup.GET("/interview/conflicts", v2.GetInterviewConflicts).Name("rest.interview.conflicts")
+		authGroup.POST("/interview/create", v2.AddInterview).Name("rest.interview.create")
+		authGroup.PUT("/interview/update", v2.EditInterview).Name("rest.interview.update")
+		authGroup.DELETE("/interview/delete/:id", v2.DeleteInterview).Name("rest.interview.delete")
+		authGroup.GET("/interview/:id", v2.GetInterview).Name("rest.interview.get")
+		authGroup.GET("/interview/:id/ics", v2.DownloadInterviewInvite).Name("rest.interview.ics")
+		authGroup.POST("/interview/:id/scorecard", v2.SubmitScorecard).Name("rest.interview.scorecard")
+
+		// offer
+		authGroup.GET("/offer/list", v2.GetOffers).Name("rest.offer.list")
+		authGroup.GET("/offer/approvals/mine", v2.GetMyOfferApprovals).Name("rest.offer.approvals")
+		authGroup.GET("/offer/templates", v2.GetOfferTemplates).Name("rest.offer.templates")
+		authGroup.POST("/offer/create", v2.AddOffer).Name("rest.offer.create")
+		authGroup.PUT("/offer/update", v2.EditOffer).Name("rest.offer.update")
+		authGroup.DELETE("/offer/delete/:id", v2.DeleteOffer).Name("rest.offer.delete")
+		authGroup.GET("/offer/:id", v2.GetOffer).Name("rest.offer.get")
+		authGroup.POST("/offer/:id/submit", v2.SubmitOffer).Name("rest.offer.submit")
+		authGroup.POST("/offer/:id/approve", v2.ApproveOffer).Name("rest.offer.approve")
+		authGroup.POST("/offer/:id/respond", v2.RespondOffer).Name("rest.offer.respond")
+		authGroup.GET("/offer/:id/letter", v2.DownloadOfferLetter).Name("rest.offer.letter")
+
+		// webhook 订阅
+		authGroup.GET("/webhook/list", v2.GetWebhooks).Name("rest.webhook.list")
+		authGroup.POST("/webhook/create", v2.AddWebhook).Name("rest.webhook.create")
+		authGroup.PUT("/webhook/update", v2.EditWebhook).Name("rest.webhook.update")
+		authGroup.DELETE("/webhook/delete/:id", v2.DeleteWebhook).Name("rest.webhook.delete")
+		authGroup.POST("/webhook/delivery/:id/retry", v2.RedeliverWebhook).Name("rest.webhook.delivery.retry")
+		authGroup.GET("/webhook/:id", v2.GetWebhook).Name("rest.webhook.get")
+		authGroup.GET("/webhook/:id/deliveries", v2.GetWebhookDeliveries).Name("rest.webhook.deliveries")
+
+		// 通知设置
+		authGroup.GET("/notification/preferences", v2.GetNotificationPreferences).Name("rest.notification.preferences")
+		authGroup.PUT("/notification/preferences", v2.EditNotificationPreferences).Name("rest.notification.preferences.update")
+
+		// AI 用量统计
+		authGroup.GET("/ai/usage", v2.GetAIUsage).Name("rest.ai.usage")
 
 		// 返回所有接口地址的名称
 		authGroup.GET("/all/perms", func(c *gin.Context) {
@@ -0,0 +1,113 @@
+package ai_usage_service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/client"
+	"hr-api/pkg/setting"
+)
+
+// ErrBudgetExceeded 本月 token 预算已用完
+var ErrBudgetExceeded = errors.New("本月AI分析token预算已用完")
+
+type AIUsage struct {
+	Uid        int
+	JobId      int
+	ResumeId   int
+	Deployment string
+
+	// 汇总条件，日期格式为 20060102
+	GroupBy  string
+	StartDay int
+	EndDay   int
+}
+
+// Add 记录一次分析的 token 消耗
+func (a *AIUsage) Add(usage *client.Usage, success bool) error {
+	data := map[string]interface{}{
+		"day":               Day(time.Now()),
+		"uid":               a.Uid,
+		"job_id":            a.JobId,
+		"resume_id":         a.ResumeId,
+		"deployment":        usage.Deployment,
+		"prompt_tokens":     usage.PromptTokens,
+		"completion_tokens": usage.CompletionTokens,
+		"total_tokens":      usage.TotalTokens(),
+		"calls":             usage.Calls,
+		"retries":           usage.Retries,
+		"latency_ms":        int(usage.Latency.Milliseconds()),
+		"success":           0,
+	}
+	if success {
+		data["success"] = 1
+	}
+	return models.AddAIUsage(data)
+}
+
+// GetSummary 按 GroupBy 汇总 token 消耗并估算成本
+func (a *AIUsage) GetSummary() ([]*models.AIUsageSummary, error) {
+	datas, err := models.GetAIUsageSummary(a.GroupBy, a.StartDay, a.EndDay, a.getMaps())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, data := range datas {
+		data.Cost = Cost(data.PromptTokens, data.CompletionTokens)
+	}
+	return datas, nil
+}
+
+func (a *AIUsage) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if a.Uid > 0 {
+		maps["uid"] = a.Uid
+	}
+	if a.JobId > 0 {
+		maps["job_id"] = a.JobId
+	}
+	if a.Deployment != "" {
+		maps["deployment"] = a.Deployment
+	}
+
+	return maps
+}
+
+// Cost 按 [llm] 配置的每千 token 价格估算成本
+func Cost(promptTokens, completionTokens int) float64 {
+	return float64(promptTokens)/1000*setting.LLMSetting.PromptTokenPrice +
+		float64(completionTokens)/1000*setting.LLMSetting.CompletionTokenPrice
+}
+
+// MonthUsed 本月已消耗的 token 数
+func MonthUsed() (int, error) {
+	now := time.Now()
+	return models.GetAIUsageTokens(Day(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())))
+}
+
+// CheckBudget 配置了每月预算且本月已用完时返回 ErrBudgetExceeded
+func CheckBudget() error {
+	budget := setting.LLMSetting.MonthlyTokenBudget
+	if budget <= 0 {
+		return nil
+	}
+
+	used, err := MonthUsed()
+	if err != nil {
+		return err
+	}
+	if used >= budget {
+		return fmt.Errorf("%w: %d/%d", ErrBudgetExceeded, used, budget)
+	}
+	return nil
+}
+
+// Day 日期转为 20060102 形式的整数
+func Day(t time.Time) int {
+	day, _ := strconv.Atoi(t.Format("20060102"))
+	return day
+}
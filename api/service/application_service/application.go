@@ -0,0 +1,118 @@
+package application_service
+
+import (
+	"context"
+	"time"
+
+	"hr-api/models"
+)
+
+type Application struct {
+	Id          int
+	CandidateId int
+	JobId       int
+	ResumeId    int
+	Status      string
+	Stage       string
+	CreateUid   int
+	Ctx         context.Context
+
+	Page  int
+	Limit int
+}
+
+func (a *Application) Add() error {
+	if a.Status == "" {
+		a.Status = models.ApplicationActive
+	}
+	if a.Stage == "" {
+		a.Stage = StageScreening
+	}
+	id, err := models.AddApplication(map[string]interface{}{
+		"candidate_id": a.CandidateId,
+		"job_id":       a.JobId,
+		"resume_id":    a.ResumeId,
+		"status":       a.Status,
+		"stage":        a.Stage,
+		"create_uid":   a.CreateUid,
+	})
+	if err != nil {
+		return err
+	}
+	a.Id = id
+	a.emitNewCandidate()
+	return nil
+}
+
+func (a *Application) Edit() error {
+	data := make(map[string]interface{})
+	data["resume_id"] = a.ResumeId
+	data["status"] = a.Status
+	data["update_time"] = int(time.Now().Unix())
+
+	return models.EditApplication(a.Id, data)
+}
+
+func (a *Application) Delete() error {
+	return models.DeleteApplication(a.Id)
+}
+
+func (a *Application) Count() (int, error) {
+	return models.GetApplicationTotal(a.getMaps())
+}
+
+func (a *Application) ExistByID() (bool, error) {
+	return models.ExistApplicationByID(a.Id)
+}
+
+func (a *Application) GetAll() ([]*models.Application, error) {
+	return models.GetApplications(a.Page, a.Limit, a.getMaps())
+}
+
+func (a *Application) GetApplication() (*models.Application, error) {
+	return models.GetApplication(a.Id)
+}
+
+// GetByCandidateJob 候选人在该职位下已有的应聘记录，不存在时 ID 为 0
+func (a *Application) GetByCandidateJob() (*models.Application, error) {
+	return models.GetApplicationByCandidateJob(a.CandidateId, a.JobId)
+}
+
+// EnsureForResume 简历归并到候选人后，确保候选人对简历所属职位有一条应聘记录
+func EnsureForResume(resume *models.Resume, candidateId int) error {
+	if candidateId == 0 || resume.JobId == 0 {
+		return nil
+	}
+
+	exists, err := models.GetApplicationByCandidateJob(candidateId, resume.JobId)
+	if err != nil || exists.ID > 0 {
+		return err
+	}
+
+	application := Application{
+		CandidateId: candidateId,
+		JobId:       resume.JobId,
+		ResumeId:    resume.ID,
+		CreateUid:   resume.CreateUid,
+	}
+	return application.Add()
+}
+
+func (a *Application) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if a.CandidateId > 0 {
+		maps["applications.candidate_id"] = a.CandidateId
+	}
+	if a.JobId > 0 {
+		maps["applications.job_id"] = a.JobId
+	}
+	if a.Status != "" {
+		maps["applications.status"] = a.Status
+	}
+	if a.Stage != "" {
+		maps["applications.stage"] = a.Stage
+	}
+
+	return maps
+}
@@ -0,0 +1,43 @@
+package application_service
+
+import (
+	"log"
+
+	"hr-api/models"
+	"hr-api/pkg/notify"
+	"hr-api/service/notify_service"
+)
+
+// notifyStageChanged 通知职位负责人与应聘记录创建人阶段变更，操作人自己不通知
+func (a *Application) notifyStageChanged(application *models.Application, reason string, uid int) {
+	job, err := models.GetJob(application.JobId)
+	if err != nil {
+		log.Printf("发送阶段变更通知失败: application=%d, %v", application.ID, err)
+		return
+	}
+	operator, err := models.GetUser(uid)
+	if err != nil {
+		log.Printf("发送阶段变更通知失败: application=%d, %v", application.ID, err)
+		return
+	}
+
+	operatorName := operator.Username
+	if operatorName == "" {
+		operatorName = "系统"
+	}
+
+	var uids []int
+	for _, recipient := range []int{job.CreateUid, application.CreateUid} {
+		if recipient != uid {
+			uids = append(uids, recipient)
+		}
+	}
+	notify_service.Notify(a.Ctx, notify.EventStageChanged, uids, map[string]string{
+		"candidate_name": application.CandidateName,
+		"job_name":       application.JobName,
+		"from_stage":     application.Stage,
+		"to_stage":       a.Stage,
+		"reason":         reason,
+		"operator":       operatorName,
+	})
+}
@@ -0,0 +1,112 @@
+package application_service
+
+import (
+	"fmt"
+
+	"hr-api/models"
+)
+
+// KanbanColumn 看板中一个阶段下的应聘记录
+type KanbanColumn struct {
+	Stage        string                `json:"stage"`
+	Total        int                   `json:"total"`
+	Applications []*models.Application `json:"applications"`
+}
+
+// PipelineForJob 职位配置的招聘流程
+func PipelineForJob(jobId int) (*Pipeline, error) {
+	job, err := models.GetJob(jobId)
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == 0 {
+		return nil, fmt.Errorf("招聘需求不存在: %d", jobId)
+	}
+	return ParsePipeline(job.Stages)
+}
+
+// MoveStage 经状态机校验后将应聘记录变更到 to 阶段，并记录操作人与原因。
+// 不允许的变更返回 ErrInvalidMove，并发修改返回 ErrStageChanged
+func (a *Application) MoveStage(to, reason string, uid int) error {
+	application, err := models.GetApplication(a.Id)
+	if err != nil {
+		return err
+	}
+	if application.ID == 0 {
+		return fmt.Errorf("应聘记录不存在: %d", a.Id)
+	}
+
+	pipeline, err := PipelineForJob(application.JobId)
+	if err != nil {
+		return err
+	}
+	if err := pipeline.CanMove(application.Stage, to); err != nil {
+		return err
+	}
+	if to == StageRejected && reason == "" {
+		return fmt.Errorf("%w: 淘汰时必须填写原因", ErrInvalidMove)
+	}
+
+	moved, err := models.MoveApplicationStage(a.Id, application.Stage, to, reason, uid)
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return ErrStageChanged
+	}
+	a.Stage = to
+	a.notifyStageChanged(application, reason, uid)
+	a.emitStageChanged(application, reason)
+	return nil
+}
+
+// StageHistory 应聘记录的阶段变更历史，按时间先后排列
+func (a *Application) StageHistory() ([]*models.ApplicationStageLog, error) {
+	return models.GetApplicationStageLogs(a.Id)
+}
+
+// Kanban 按职位流程的阶段顺序分组展示进行中的应聘记录
+func Kanban(jobId int) ([]*KanbanColumn, error) {
+	pipeline, err := PipelineForJob(jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	applications, err := models.GetApplications(0, 0, map[string]interface{}{
+		"applications.job_id": jobId,
+		"applications.status": models.ApplicationActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]*KanbanColumn, 0, len(pipeline.Stages()))
+	byStage := make(map[string]*KanbanColumn)
+	for _, stage := range pipeline.Stages() {
+		column := &KanbanColumn{Stage: stage, Applications: []*models.Application{}}
+		columns = append(columns, column)
+		byStage[stage] = column
+	}
+	for _, application := range applications {
+		// 流程调整前遗留的阶段不会出现在看板中
+		if column, ok := byStage[application.Stage]; ok {
+			column.Applications = append(column.Applications, application)
+			column.Total++
+		}
+	}
+	return columns, nil
+}
+
+// CheckPipelineChange 职位修改流程前检查，仍有应聘记录处于被移除的阶段时不允许修改
+func CheckPipelineChange(jobId int, pipeline *Pipeline) error {
+	counts, err := models.GetApplicationStageCounts(jobId)
+	if err != nil {
+		return err
+	}
+	for stage, count := range counts {
+		if count > 0 && !pipeline.Has(stage) {
+			return fmt.Errorf("%w: 仍有 %d 条应聘记录处于 %s 阶段", ErrInvalidMove, count, stage)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package application_service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 招聘流程阶段，面试轮次为 interview_1、interview_2 ...
+const (
+	StageScreening   = "screening"
+	StagePhoneScreen = "phone_screen"
+	StageInterview   = "interview"
+	StageOffer       = "offer"
+	StageHired       = "hired"
+	StageRejected    = "rejected"
+)
+
+// maxInterviewRounds 单个职位最多配置的面试轮次
+const maxInterviewRounds = 10
+
+// DefaultStages 职位未配置流程时使用的阶段
+var DefaultStages = []string{StageScreening, StagePhoneScreen, InterviewStage(1), InterviewStage(2), StageOffer, StageHired, StageRejected}
+
+var (
+	// ErrInvalidMove 状态机不允许的阶段变更，具体原因包含在错误信息中
+	ErrInvalidMove = errors.New("不允许的阶段变更")
+	// ErrStageChanged 变更前应聘阶段已被其他请求修改
+	ErrStageChanged = errors.New("应聘阶段已被其他人修改，请刷新后重试")
+)
+
+// InterviewStage 第 round 轮面试的阶段名
+func InterviewStage(round int) string {
+	return fmt.Sprintf("%s_%d", StageInterview, round)
+}
+
+// IsTerminalStage 录用与淘汰为终止阶段
+func IsTerminalStage(stage string) bool {
+	return stage == StageHired || stage == StageRejected
+}
+
+// Pipeline 一个职位的招聘流程，阶段按推进顺序排列
+type Pipeline struct {
+	stages []string
+	index  map[string]int
+}
+
+// NewPipeline 校验并创建招聘流程，stages 为空时使用 DefaultStages。
+// 流程必须依次为 screening、可选的 phone_screen、从第 1 轮起连续的面试轮次、offer、hired、rejected
+func NewPipeline(stages []string) (*Pipeline, error) {
+	if len(stages) == 0 {
+		stages = DefaultStages
+	}
+
+	p := &Pipeline{index: make(map[string]int, len(stages))}
+	for _, stage := range stages {
+		stage = strings.TrimSpace(stage)
+		if _, ok := p.index[stage]; ok {
+			return nil, fmt.Errorf("招聘流程阶段重复: %s", stage)
+		}
+		p.index[stage] = len(p.stages)
+		p.stages = append(p.stages, stage)
+	}
+
+	// 按约定顺序逐个核对
+	i := 0
+	expect := func(stage string) bool {
+		if i < len(p.stages) && p.stages[i] == stage {
+			i++
+			return true
+		}
+		return false
+	}
+	if !expect(StageScreening) {
+		return nil, fmt.Errorf("招聘流程必须以 %s 开始", StageScreening)
+	}
+	expect(StagePhoneScreen)
+	for round := 1; expect(InterviewStage(round)); round++ {
+		if round > maxInterviewRounds {
+			return nil, fmt.Errorf("面试轮次不能超过 %d 轮", maxInterviewRounds)
+		}
+	}
+	for _, stage := range []string{StageOffer, StageHired, StageRejected} {
+		if !expect(stage) {
+			if i < len(p.stages) {
+				return nil, fmt.Errorf("招聘流程阶段 %s 无效或顺序错误", p.stages[i])
+			}
+			return nil, fmt.Errorf("招聘流程缺少阶段 %s", stage)
+		}
+	}
+	if i < len(p.stages) {
+		return nil, fmt.Errorf("招聘流程阶段 %s 无效或顺序错误", p.stages[i])
+	}
+
+	return p, nil
+}
+
+// ParsePipeline 解析职位保存的逗号分隔阶段列表
+func ParsePipeline(stages string) (*Pipeline, error) {
+	if strings.TrimSpace(stages) == "" {
+		return NewPipeline(nil)
+	}
+	return NewPipeline(strings.Split(stages, ","))
+}
+
+// Stages 按推进顺序返回全部阶段
+func (p *Pipeline) Stages() []string {
+	return append([]string(nil), p.stages...)
+}
+
+// String 逗号分隔的阶段列表，用于保存到职位
+func (p *Pipeline) String() string {
+	return strings.Join(p.stages, ",")
+}
+
+// Has 流程中是否包含该阶段
+func (p *Pipeline) Has(stage string) bool {
+	_, ok := p.index[stage]
+	return ok
+}
+
+// CanMove 判断阶段变更是否允许：
+// 终止阶段不能再变更；任意进行中的阶段都可以淘汰；录用只能从 offer 进入；
+// 其余阶段可以向后跳过若干阶段，向前只能回退一个阶段以便纠正误操作
+func (p *Pipeline) CanMove(from, to string) error {
+	fromIndex, ok := p.index[from]
+	if !ok {
+		return fmt.Errorf("%w: 职位流程中不存在阶段 %s", ErrInvalidMove, from)
+	}
+	toIndex, ok := p.index[to]
+	if !ok {
+		return fmt.Errorf("%w: 职位流程中不存在阶段 %s", ErrInvalidMove, to)
+	}
+
+	switch {
+	case from == to:
+		return fmt.Errorf("%w: 应聘记录已处于 %s 阶段", ErrInvalidMove, to)
+	case IsTerminalStage(from):
+		return fmt.Errorf("%w: 已录用或已淘汰的应聘记录不能再变更阶段", ErrInvalidMove)
+	case to == StageRejected:
+		return nil
+	case to == StageHired && from != StageOffer:
+		return fmt.Errorf("%w: 只能从 %s 阶段录用", ErrInvalidMove, StageOffer)
+	case toIndex > fromIndex:
+		return nil
+	case toIndex == fromIndex-1:
+		return nil
+	}
+	return fmt.Errorf("%w: 不能从 %s 回退到 %s，只能回退一个阶段", ErrInvalidMove, from, to)
+}
@@ -0,0 +1,44 @@
+package application_service
+
+import (
+	"fmt"
+	"log"
+
+	"hr-api/models"
+	"hr-api/pkg/webhook"
+	"hr-api/service/webhook_service"
+)
+
+// emitNewCandidate 候选人进入职位（生成应聘记录）时投递 webhook 事件
+func (a *Application) emitNewCandidate() {
+	application, err := models.GetApplication(a.Id)
+	if err != nil {
+		log.Printf("投递新候选人事件失败: application=%d, %v", a.Id, err)
+		return
+	}
+
+	e := webhook.NewEvent(webhook.EventNewCandidate, application.JobId,
+		fmt.Sprintf("新候选人：%s 应聘「%s」", application.CandidateName, application.JobName))
+	e.Add("job_name", "职位", application.JobName).
+		Add("candidate_name", "候选人", application.CandidateName).
+		Add("stage", "阶段", application.Stage).
+		Add("application_id", "", application.ID).
+		Add("candidate_id", "", application.CandidateId)
+	webhook_service.Emit(a.Ctx, e)
+}
+
+// emitStageChanged 应聘阶段变更后投递 webhook 事件，application 为变更前的记录
+func (a *Application) emitStageChanged(application *models.Application, reason string) {
+	e := webhook.NewEvent(webhook.EventStageChanged, application.JobId,
+		fmt.Sprintf("%s 在「%s」的阶段变更为 %s", application.CandidateName, application.JobName, a.Stage))
+	e.Add("job_name", "职位", application.JobName).
+		Add("candidate_name", "候选人", application.CandidateName).
+		Add("from_stage", "原阶段", application.Stage).
+		Add("to_stage", "新阶段", a.Stage).
+		Add("application_id", "", application.ID).
+		Add("candidate_id", "", application.CandidateId)
+	if reason != "" {
+		e.Add("reason", "原因", reason)
+	}
+	webhook_service.Emit(a.Ctx, e)
+}
@@ -0,0 +1,41 @@
+package audit_service
+
+import (
+	"encoding/json"
+
+	"hr-api/models"
+)
+
+// 审计动作
+const (
+	// ActionRedactPII 简历发送给大模型前脱敏了个人信息
+	ActionRedactPII = "redact_pii"
+)
+
+// 审计对象类型
+const (
+	TargetResume = "resume"
+)
+
+type Audit struct {
+	Action     string
+	TargetType string
+	TargetId   int
+	Uid        int
+}
+
+// Add 记录一条审计日志，detail 序列化为 JSON 保存
+func (a *Audit) Add(detail interface{}) error {
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+
+	return models.AddAuditLog(map[string]interface{}{
+		"action":      a.Action,
+		"target_type": a.TargetType,
+		"target_id":   a.TargetId,
+		"uid":         a.Uid,
+		"detail":      data,
+	})
+}
@@ -0,0 +1,220 @@
+package batch_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/cache"
+	"hr-api/service/queue_service"
+	"hr-api/service/resume_service"
+)
+
+// 批次进度保存在 Redis 哈希中的计数字段，done 为已有结果（成功或最终失败）的简历数
+const (
+	fieldRunning   = "running"
+	fieldSucceeded = "succeeded"
+	fieldFailed    = "failed"
+	fieldDone      = "done"
+
+	progressExpiration = 7 * 24 * time.Hour
+)
+
+type Batch struct {
+	Id        int
+	JobId     int
+	CreateUid int
+	Ctx       context.Context
+}
+
+// Add 创建批次，并为其中每份简历投递一个分析任务。每个 worker 进程依次处理任务，
+// 同时执行的分析任务数即 worker 进程数，需要限制对 AI 服务的并发时减少 worker 进程即可。
+// 投递中途失败时，未投递的简历直接计为失败，批次不会一直停在进行中
+func (b *Batch) Add() error {
+	rd, err := cache.GetInstance()
+	if err != nil {
+		return err
+	}
+
+	resumeIds, err := models.GetResumeIdsByJob(b.JobId)
+	if err != nil {
+		return err
+	}
+
+	id, err := models.AddAnalysisBatch(map[string]interface{}{
+		"job_id":     b.JobId,
+		"total":      len(resumeIds),
+		"create_uid": b.CreateUid,
+	})
+	if err != nil {
+		return err
+	}
+	b.Id = id
+
+	if err := rd.HSet(b.Ctx, b.key(), "job_id", b.JobId, "total", len(resumeIds), "status", models.BatchStatusRunning,
+		fieldRunning, 0, fieldSucceeded, 0, fieldFailed, 0, fieldDone, 0); err != nil {
+		return err
+	}
+	rd.Expire(b.Ctx, b.key(), progressExpiration)
+
+	if len(resumeIds) == 0 {
+		return b.finish(rd)
+	}
+	if err := models.EditAnalysisBatch(b.Id, map[string]interface{}{"status": models.BatchStatusRunning}); err != nil {
+		return err
+	}
+
+	// 请求结束后 b.Ctx 会被取消，投递不能随之中断
+	ctx := context.WithoutCancel(b.Ctx)
+	for i, resumeId := range resumeIds {
+		task := resume_service.AnalyzeTask{ResumeId: resumeId, CreateUid: b.CreateUid, BatchId: b.Id}
+		if err := queue_service.Publish(ctx, queue_service.TaskAnalyzeResume, task); err != nil {
+			b.skip(rd, len(resumeIds)-i)
+			return fmt.Errorf("投递简历 %d 的分析任务失败: %w", resumeId, err)
+		}
+	}
+	return nil
+}
+
+func (b *Batch) ExistByID() (bool, error) {
+	return models.ExistAnalysisBatchByID(b.Id)
+}
+
+// Get 返回批次及其实时进度，进度以 Redis 中的计数为准
+func (b *Batch) Get() (*models.AnalysisBatch, error) {
+	batch, err := models.GetAnalysisBatch(b.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch.Status == models.BatchStatusFinished {
+		return batch, nil
+	}
+
+	rd, err := cache.GetInstance()
+	if err != nil {
+		batch.Pending = batch.Total
+		return batch, nil
+	}
+
+	progress, err := rd.HGetAll(b.Ctx, b.key())
+	if err != nil || len(progress) == 0 {
+		batch.Pending = batch.Total
+		return batch, nil
+	}
+	if progress["status"] != "" {
+		batch.Status = progress["status"]
+	}
+	batch.Running, _ = strconv.Atoi(progress[fieldRunning])
+	batch.Succeeded, _ = strconv.Atoi(progress[fieldSucceeded])
+	batch.Failed, _ = strconv.Atoi(progress[fieldFailed])
+	batch.Pending = batch.Total - batch.Running - batch.Succeeded - batch.Failed
+	if batch.Pending < 0 {
+		batch.Pending = 0
+	}
+
+	return batch, nil
+}
+
+// HandleAnalyzeTask worker 中执行简历分析任务，属于批次的任务有结果后计入批次进度。
+// 失败后驱动还会重新投递的任务不计数，不再投递时才计为失败。MaxDeliveryCount 为 0 时失败的任务会一直重试，
+// 批次要等每份简历都有结果才结束
+func HandleAnalyzeTask(ctx context.Context, payload []byte) (err error) {
+	var task resume_service.AnalyzeTask
+	if json.Unmarshal(payload, &task) != nil || task.BatchId <= 0 {
+		return resume_service.HandleAnalyzeTask(ctx, payload)
+	}
+
+	rd, cacheErr := cache.GetInstance()
+	if cacheErr != nil {
+		return cacheErr
+	}
+	b := Batch{Id: task.BatchId, Ctx: ctx}
+
+	rd.HIncrBy(ctx, b.key(), fieldRunning, 1)
+	defer func() {
+		// panic 由 worker 转为 ErrPoison，不会再重试，同样计为失败
+		r := recover()
+		rd.HIncrBy(context.WithoutCancel(ctx), b.key(), fieldRunning, -1)
+		switch {
+		case r != nil:
+			b.record(rd, false)
+			panic(r)
+		case err == nil:
+			b.record(rd, true)
+		case !queue_service.WillRedeliver(ctx, err):
+			log.Printf("[batch] batch %d resume %d failed: %v", b.Id, task.ResumeId, err)
+			b.record(rd, false)
+		}
+	}()
+
+	return resume_service.HandleAnalyzeTask(ctx, payload)
+}
+
+// record 记录一份简历的最终结果，最后一份有结果时结束批次
+func (b *Batch) record(rd *cache.RedisCache, succeeded bool) {
+	field := fieldFailed
+	if succeeded {
+		field = fieldSucceeded
+	}
+	b.count(rd, field, 1)
+}
+
+// skip 未能投递的 n 份简历计为失败
+func (b *Batch) skip(rd *cache.RedisCache, n int) {
+	log.Printf("[batch] batch %d: %d resumes not published, counted as failed", b.Id, n)
+	b.count(rd, fieldFailed, int64(n))
+}
+
+// count 把 n 份简历计入 field 与 done，计数达到总数时结束批次
+func (b *Batch) count(rd *cache.RedisCache, field string, n int64) {
+	ctx := context.WithoutCancel(b.Ctx)
+	if _, err := rd.HIncrBy(ctx, b.key(), field, n); err != nil {
+		log.Printf("[batch] record batch %d progress failed: %v", b.Id, err)
+		return
+	}
+
+	done, err := rd.HIncrBy(ctx, b.key(), fieldDone, n)
+	if err != nil {
+		log.Printf("[batch] record batch %d progress failed: %v", b.Id, err)
+		return
+	}
+	var total int
+	if err := rd.HGet(ctx, b.key(), "total", &total); err != nil {
+		log.Printf("[batch] read batch %d total failed: %v", b.Id, err)
+		return
+	}
+	// 只有计数恰好达到总数的任务结束批次
+	if int(done) == total {
+		b.Ctx = ctx
+		if err := b.finish(rd); err != nil {
+			log.Printf("[batch] finish batch %d failed: %v", b.Id, err)
+		}
+	}
+}
+
+// finish 把最终计数写回数据库并标记批次完成
+func (b *Batch) finish(rd *cache.RedisCache) error {
+	progress, err := rd.HGetAll(b.Ctx, b.key())
+	if err != nil {
+		return err
+	}
+	succeeded, _ := strconv.Atoi(progress[fieldSucceeded])
+	failed, _ := strconv.Atoi(progress[fieldFailed])
+
+	rd.HSet(b.Ctx, b.key(), "status", models.BatchStatusFinished)
+	return models.EditAnalysisBatch(b.Id, map[string]interface{}{
+		"status":      models.BatchStatusFinished,
+		"succeeded":   succeeded,
+		"failed":      failed,
+		"finish_time": int(time.Now().Unix()),
+	})
+}
+
+func (b *Batch) key() string {
+	return "BATCH_" + strconv.Itoa(b.Id)
+}
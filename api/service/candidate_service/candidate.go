@@ -0,0 +1,86 @@
+package candidate_service
+
+import (
+	"strings"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/dedupe"
+)
+
+type Candidate struct {
+	Id        int
+	Name      string
+	Phone     string
+	Email     string
+	Location  string
+	Remark    string
+	CreateUid int
+
+	Keyword string
+	Page    int
+	Limit   int
+}
+
+// Add 新建候选人，手机号按输入保存，另存规范化后的手机号用于查重；邮箱规范化后保存
+func (c *Candidate) Add() error {
+	phone := strings.TrimSpace(c.Phone)
+	id, err := models.AddCandidate(map[string]interface{}{
+		"name":       c.Name,
+		"phone":      phone,
+		"phone_key":  dedupe.NormalizePhone(phone),
+		"email":      dedupe.NormalizeEmail(c.Email),
+		"location":   c.Location,
+		"remark":     c.Remark,
+		"create_uid": c.CreateUid,
+	})
+	if err != nil {
+		return err
+	}
+	c.Id = id
+	return nil
+}
+
+func (c *Candidate) Edit() error {
+	data := make(map[string]interface{})
+	data["name"] = c.Name
+	data["phone"] = strings.TrimSpace(c.Phone)
+	data["phone_key"] = dedupe.NormalizePhone(c.Phone)
+	data["email"] = dedupe.NormalizeEmail(c.Email)
+	data["location"] = c.Location
+	data["remark"] = c.Remark
+	data["update_time"] = int(time.Now().Unix())
+
+	return models.EditCandidate(c.Id, data)
+}
+
+// Delete 删除候选人及其应聘记录，简历保留但不再关联该候选人
+func (c *Candidate) Delete() error {
+	return models.DeleteCandidate(c.Id)
+}
+
+func (c *Candidate) Count() (int, error) {
+	return models.GetCandidateTotal(c.Keyword, c.getMaps())
+}
+
+func (c *Candidate) ExistByID() (bool, error) {
+	return models.ExistCandidateByID(c.Id)
+}
+
+func (c *Candidate) GetAll() ([]*models.Candidate, error) {
+	return models.GetCandidates(c.Page, c.Limit, c.Keyword, c.getMaps())
+}
+
+func (c *Candidate) GetCandidate() (*models.Candidate, error) {
+	return models.GetCandidate(c.Id)
+}
+
+func (c *Candidate) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if c.Id > 0 {
+		maps["id"] = c.Id
+	}
+
+	return maps
+}
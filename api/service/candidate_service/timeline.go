@@ -0,0 +1,82 @@
+package candidate_service
+
+import (
+	"sort"
+
+	"hr-api/models"
+)
+
+// 时间线事件类型
+const (
+	EventResume      = "resume"
+	EventAnalysis    = "analysis"
+	EventApplication = "application"
+)
+
+// TimelineEvent 候选人时间线中的一条记录，Data 为对应的简历、分析摘要或应聘记录
+type TimelineEvent struct {
+	Type    string      `json:"type"`
+	Time    int         `json:"time"`
+	JobId   int         `json:"job_id"`
+	JobName string      `json:"job_name"`
+	Data    interface{} `json:"data"`
+}
+
+// Timeline 合并候选人的全部简历、AI 分析与应聘的职位，按时间倒序排列
+func (c *Candidate) Timeline() ([]*TimelineEvent, error) {
+	var events []*TimelineEvent
+
+	resumes, err := models.GetResumesByCandidate(c.Id)
+	if err != nil {
+		return nil, err
+	}
+	resumeIds := make([]int, 0, len(resumes))
+	for _, resume := range resumes {
+		resumeIds = append(resumeIds, resume.ID)
+		events = append(events, &TimelineEvent{
+			Type:    EventResume,
+			Time:    resume.CreateTime,
+			JobId:   resume.JobId,
+			JobName: resume.JobName,
+			Data:    resume,
+		})
+	}
+
+	analyses, err := models.GetResumeAnalysisBriefs(resumeIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, analysis := range analyses {
+		events = append(events, &TimelineEvent{
+			Type:    EventAnalysis,
+			Time:    analysis.CreateTime,
+			JobId:   analysis.JobId,
+			JobName: analysis.JobName,
+			Data:    analysis,
+		})
+	}
+
+	applications, err := models.GetApplications(0, 0, map[string]interface{}{"applications.candidate_id": c.Id})
+	if err != nil {
+		return nil, err
+	}
+	for _, application := range applications {
+		events = append(events, &TimelineEvent{
+			Type:    EventApplication,
+			Time:    application.CreateTime,
+			JobId:   application.JobId,
+			JobName: application.JobName,
+			Data:    application,
+		})
+	}
+
+	// 同一时刻的事件按“简历、应聘、分析”的发生顺序倒排
+	order := map[string]int{EventResume: 0, EventApplication: 1, EventAnalysis: 2}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Time != events[j].Time {
+			return events[i].Time > events[j].Time
+		}
+		return order[events[i].Type] > order[events[j].Type]
+	})
+	return events, nil
+}
@@ -0,0 +1,224 @@
+package interview_service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/ics"
+	"hr-api/pkg/setting"
+)
+
+type Interview struct {
+	Id            int
+	ApplicationId int
+	Round         int
+	StartTime     int
+	EndTime       int
+	Location      string
+	MeetingUrl    string
+	Status        string
+	Remark        string
+	Interviewers  []int
+	CreateUid     int
+	Ctx           context.Context
+
+	// 我的面试：面试官 Uid 在 Since 之后结束的面试
+	Uid   int
+	Since int
+
+	Page  int
+	Limit int
+}
+
+func (i *Interview) Add() error {
+	id, err := models.AddInterview(map[string]interface{}{
+		"application_id": i.ApplicationId,
+		"round":          i.Round,
+		"start_time":     i.StartTime,
+		"end_time":       i.EndTime,
+		"location":       i.Location,
+		"meeting_url":    i.MeetingUrl,
+		"remark":         i.Remark,
+		"create_uid":     i.CreateUid,
+	}, i.Interviewers)
+	if err != nil {
+		return err
+	}
+	i.Id = id
+	return nil
+}
+
+// Edit 修改面试，改期、更换地点或面试官、取消时递增邀请版本号，日历客户端据此更新已发出的邀请
+func (i *Interview) Edit(existing *models.Interview) error {
+	data := make(map[string]interface{})
+	data["round"] = i.Round
+	data["start_time"] = i.StartTime
+	data["end_time"] = i.EndTime
+	data["location"] = i.Location
+	data["meeting_url"] = i.MeetingUrl
+	data["status"] = i.Status
+	data["remark"] = i.Remark
+	data["update_time"] = int(time.Now().Unix())
+
+	if i.inviteChanged(existing) {
+		data["sequence"] = existing.Sequence + 1
+	}
+
+	return models.EditInterview(i.Id, data, i.Interviewers)
+}
+
+// inviteChanged 邀请中的时间、地点、参会人或状态是否变化
+func (i *Interview) inviteChanged(existing *models.Interview) bool {
+	if i.StartTime != existing.StartTime || i.EndTime != existing.EndTime ||
+		i.Location != existing.Location || i.MeetingUrl != existing.MeetingUrl ||
+		i.Status != existing.Status {
+		return true
+	}
+	if i.Interviewers == nil {
+		return false
+	}
+
+	uids := make(map[int]bool, len(existing.Interviewers))
+	for _, interviewer := range existing.Interviewers {
+		uids[interviewer.Uid] = true
+	}
+	if len(uids) != len(i.Interviewers) {
+		return true
+	}
+	for _, uid := range i.Interviewers {
+		if !uids[uid] {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Interview) Delete() error {
+	return models.DeleteInterview(i.Id)
+}
+
+func (i *Interview) Count() (int, error) {
+	return models.GetInterviewTotal(i.getMaps())
+}
+
+func (i *Interview) ExistByID() (bool, error) {
+	return models.ExistInterviewByID(i.Id)
+}
+
+func (i *Interview) GetAll() ([]*models.Interview, error) {
+	return models.GetInterviews(i.Page, i.Limit, i.getMaps())
+}
+
+func (i *Interview) GetInterview() (*models.Interview, error) {
+	return models.GetInterview(i.Id)
+}
+
+// Mine 面试官 Uid 的面试，默认只包含尚未结束的
+func (i *Interview) Mine() ([]*models.Interview, error) {
+	return models.GetInterviewsByInterviewer(i.Uid, i.Since, i.Page, i.Limit, i.getMaps())
+}
+
+// MineCount 面试官 Uid 的面试数量
+func (i *Interview) MineCount() (int, error) {
+	return models.GetInterviewTotalByInterviewer(i.Uid, i.Since, i.getMaps())
+}
+
+// Conflicts 面试官在该时间段已安排的其他面试，修改面试时排除自身
+func (i *Interview) Conflicts() ([]*models.InterviewConflict, error) {
+	return models.GetInterviewConflicts(i.Interviewers, i.StartTime, i.EndTime, i.Id)
+}
+
+// ConflictMessage 将冲突列表转换为提示信息
+func ConflictMessage(conflicts []*models.InterviewConflict) string {
+	parts := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		parts = append(parts, fmt.Sprintf("面试官 %s 在 %s - %s 已有面试 #%d",
+			c.Username,
+			time.Unix(int64(c.StartTime), 0).Format("2006-01-02 15:04"),
+			time.Unix(int64(c.EndTime), 0).Format("15:04"),
+			c.InterviewId))
+	}
+	return "面试官时间冲突: " + strings.Join(parts, "; ")
+}
+
+// Invite 生成面试的 iCalendar 邀请，面试官与候选人为参会人，创建人为组织者。
+// 已取消的面试生成 CANCEL 邀请，用于从参会人日历中移除
+func (i *Interview) Invite() ([]byte, error) {
+	interview, err := models.GetInterview(i.Id)
+	if err != nil {
+		return nil, err
+	}
+	if interview.ID == 0 {
+		return nil, fmt.Errorf("面试不存在: %d", i.Id)
+	}
+
+	organizer, err := models.GetUser(interview.CreateUid)
+	if err != nil {
+		return nil, err
+	}
+	candidate, err := models.GetCandidate(interview.CandidateId)
+	if err != nil {
+		return nil, err
+	}
+
+	event := ics.Event{
+		UID:       fmt.Sprintf("interview-%d@%s", interview.ID, inviteDomain()),
+		Sequence:  interview.Sequence,
+		Start:     time.Unix(int64(interview.StartTime), 0),
+		End:       time.Unix(int64(interview.EndTime), 0),
+		Summary:   fmt.Sprintf("面试：%s - %s 第%d轮", candidate.Name, interview.JobName, interview.Round),
+		Location:  interview.Location,
+		URL:       interview.MeetingUrl,
+		Organizer: ics.Person{Name: organizer.Username, Email: organizer.Email},
+	}
+	if event.Location == "" {
+		event.Location = interview.MeetingUrl
+	}
+
+	var desc []string
+	if interview.MeetingUrl != "" {
+		desc = append(desc, "会议链接："+interview.MeetingUrl)
+	}
+	if interview.Remark != "" {
+		desc = append(desc, interview.Remark)
+	}
+	event.Description = strings.Join(desc, "\n")
+
+	for _, interviewer := range interview.Interviewers {
+		event.Attendees = append(event.Attendees, ics.Person{Name: interviewer.Username, Email: interviewer.Email})
+	}
+	if candidate.Email != "" {
+		event.Attendees = append(event.Attendees, ics.Person{Name: candidate.Name, Email: candidate.Email})
+	}
+
+	method := ics.MethodRequest
+	if interview.Status == models.InterviewCancelled {
+		method, event.Status = ics.MethodCancel, ics.StatusCancelled
+	}
+	return ics.Calendar(method, event, time.Now()), nil
+}
+
+// inviteDomain 邀请 UID 使用的域名，取自 PrefixUrl
+func inviteDomain() string {
+	if u, err := url.Parse(setting.AppSetting.PrefixUrl); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "hr-api"
+}
+
+func (i *Interview) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if i.ApplicationId > 0 {
+		maps["interviews.application_id"] = i.ApplicationId
+	}
+	if i.Status != "" {
+		maps["interviews.status"] = i.Status
+	}
+
+	return maps
+}
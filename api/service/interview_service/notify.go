@@ -0,0 +1,49 @@
+package interview_service
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/notify"
+	"hr-api/service/notify_service"
+)
+
+const timeLayout = "2006-01-02 15:04"
+
+// NotifyScheduled 给面试官发送面试通知并附上日历邀请，安排面试的人自己不通知
+func (i *Interview) NotifyScheduled() {
+	interview, err := models.GetInterview(i.Id)
+	if err != nil {
+		log.Printf("发送面试通知失败: interview=%d, %v", i.Id, err)
+		return
+	}
+	invite, err := i.Invite()
+	if err != nil {
+		log.Printf("发送面试通知失败: interview=%d, %v", i.Id, err)
+		return
+	}
+
+	var uids []int
+	for _, interviewer := range interview.Interviewers {
+		if interviewer.Uid != interview.CreateUid {
+			uids = append(uids, interviewer.Uid)
+		}
+	}
+	notify_service.Notify(i.Ctx, notify.EventInterviewScheduled, uids, map[string]string{
+		"interview_id":   strconv.Itoa(interview.ID),
+		"candidate_name": interview.CandidateName,
+		"job_name":       interview.JobName,
+		"round":          strconv.Itoa(interview.Round),
+		"start_time":     time.Unix(int64(interview.StartTime), 0).Format(timeLayout),
+		"end_time":       time.Unix(int64(interview.EndTime), 0).Format(timeLayout),
+		"location":       interview.Location,
+		"meeting_url":    interview.MeetingUrl,
+	}, notify.Attachment{
+		Filename:    fmt.Sprintf("interview-%d.ics", interview.ID),
+		ContentType: "text/calendar; method=REQUEST; charset=utf-8",
+		Data:        invite,
+	})
+}
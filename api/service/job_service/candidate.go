@@ -0,0 +1,105 @@
+package job_service
+
+import (
+	"encoding/json"
+	"hr-api/pkg/cache"
+	"strings"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/util"
+	"hr-api/service/cache_service"
+)
+
+// candidateCacheTTL 分析结果随时会更新，排名与总数只做短时间缓存，两者有效期相同以免分页与总数对不上
+const candidateCacheTTL = 300 * time.Second
+
+// GetCandidates 按AI匹配度评分排序的候选简历列表
+func (j *Job) GetCandidates() ([]*models.JobCandidate, error) {
+	var datas []*models.JobCandidate
+
+	cacheService := cache_service.Cache{
+		Name:    cache.CACHE_JOB_CANDIDATE,
+		Id:      j.Id,
+		Keyword: j.candidateFilterKey(),
+		Page:    j.Page,
+		Limit:   j.Limit,
+	}
+
+	rd, err := cache.GetInstance()
+	if err != nil {
+		return models.GetJobCandidates(j.Page, j.Limit, j.Id, j.candidateFilter())
+	}
+
+	key := cacheService.GetTagsKey()
+	if j.CacheClear > 0 {
+		rd.Delete(j.Ctx, key)
+	} else if err := rd.Get(j.Ctx, key, &datas); err == nil {
+		return datas, nil
+	}
+
+	datas, err = models.GetJobCandidates(j.Page, j.Limit, j.Id, j.candidateFilter())
+	if err != nil {
+		return nil, err
+	}
+
+	rd.Set(j.Ctx, key, datas, candidateCacheTTL)
+	return datas, nil
+}
+
+// CountCandidates 候选简历总数，与列表使用相同的缓存有效期
+func (j *Job) CountCandidates() (int, error) {
+	var count int
+
+	cacheService := cache_service.Cache{
+		Name:    cache.CACHE_JOB_CANDIDATE,
+		Id:      j.Id,
+		Keyword: strings.TrimSuffix("TOTAL_"+j.candidateFilterKey(), "_"),
+	}
+
+	rd, err := cache.GetInstance()
+	if err != nil {
+		return models.GetJobCandidateTotal(j.Id, j.candidateFilter())
+	}
+
+	key := cacheService.GetTagsKey()
+	if j.CacheClear > 0 {
+		rd.Delete(j.Ctx, key)
+	} else if err := rd.Get(j.Ctx, key, &count); err == nil {
+		return count, nil
+	}
+
+	count, err = models.GetJobCandidateTotal(j.Id, j.candidateFilter())
+	if err != nil {
+		return 0, err
+	}
+
+	rd.Set(j.Ctx, key, count, candidateCacheTTL)
+	return count, nil
+}
+
+func (j *Job) candidateFilter() models.JobCandidateFilter {
+	var skills []string
+	for _, skill := range j.Skills {
+		skill = strings.ToLower(strings.TrimSpace(skill))
+		if skill != "" {
+			skills = append(skills, skill)
+		}
+	}
+
+	return models.JobCandidateFilter{
+		MinScore: j.MinScore,
+		MinYoe:   j.MinYoe,
+		Skills:   skills,
+	}
+}
+
+// candidateFilterKey 筛选条件在缓存键中的部分，取 JSON 编码的 MD5，技能名中含有分隔符时也不会与其他条件冲突
+func (j *Job) candidateFilterKey() string {
+	filter := j.candidateFilter()
+	if filter.MinScore == 0 && filter.MinYoe == 0 && len(filter.Skills) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(filter)
+	return util.EncodeMD5(string(data))
+}
@@ -0,0 +1,101 @@
+package job_service
+
+import (
+	"errors"
+	"log"
+
+	"hr-api/models"
+	"hr-api/pkg/setting"
+	"hr-api/service/application_service"
+	"hr-api/service/offer_service"
+)
+
+// CloseResult 关闭职位时淘汰的应聘记录与撤回的 offer 数量
+type CloseResult struct {
+	Rejected  int
+	Withdrawn int
+}
+
+// CloseActions 关闭职位时需要处理的应聘记录与 offer
+type CloseActions struct {
+	Reject   []*models.Application
+	Withdraw []*models.Offer
+}
+
+// PlanClose 计算关闭职位时要做的处理：进行中且未到终止阶段的应聘记录全部淘汰，
+// 除已录用的应聘记录外，审批中或已发出的 offer 全部撤回。
+// 已处理过的记录不会再次出现，因此上次未处理完时可以重新执行
+func PlanClose(applications []*models.Application, offers []*models.Offer) CloseActions {
+	var actions CloseActions
+	hired := make(map[int]bool)
+	for _, application := range applications {
+		if application.Stage == application_service.StageHired {
+			hired[application.ID] = true
+			continue
+		}
+		if application.Status == models.ApplicationActive && !application_service.IsTerminalStage(application.Stage) {
+			actions.Reject = append(actions.Reject, application)
+		}
+	}
+
+	for _, offer := range offers {
+		if offer_service.IsWithdrawable(offer.Status) && !hired[offer.ApplicationId] {
+			actions.Withdraw = append(actions.Withdraw, offer)
+		}
+	}
+	return actions
+}
+
+// RejectOpenApplications 职位关闭后撤回其下未答复的 offer 并淘汰所有进行中的应聘记录，reason 为空时使用配置的默认原因。
+// 已录用或已淘汰的记录保持不变；单条记录因并发修改等原因处理失败时跳过，职位再次保存为关闭状态时会重新处理
+func (j *Job) RejectOpenApplications(reason string, uid int) (CloseResult, error) {
+	var result CloseResult
+	if reason == "" {
+		reason = setting.AppSetting.JobCloseReason
+	}
+
+	applications, err := models.GetApplications(0, 0, map[string]interface{}{
+		"applications.job_id": j.Id,
+	})
+	if err != nil {
+		return result, err
+	}
+	offers, err := models.GetOffers(0, 0, map[string]interface{}{
+		"applications.job_id": j.Id,
+		"offers.status":       []string{models.OfferPendingApproval, models.OfferSent},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	actions := PlanClose(applications, offers)
+
+	// 先撤回 offer，避免候选人在应聘记录淘汰后仍能接受 offer
+	for _, offer := range actions.Withdraw {
+		service := offer_service.Offer{Id: offer.ID, Ctx: j.Ctx}
+		err := service.Withdraw()
+		if errors.Is(err, offer_service.ErrInvalidTransition) {
+			log.Printf("关闭职位时撤回 offer 失败: job=%d, offer=%d, %v", j.Id, offer.ID, err)
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Withdrawn++
+	}
+
+	for _, application := range actions.Reject {
+		service := application_service.Application{Id: application.ID, Ctx: j.Ctx}
+		err := service.MoveStage(application_service.StageRejected, reason, uid)
+		if errors.Is(err, application_service.ErrInvalidMove) || errors.Is(err, application_service.ErrStageChanged) {
+			log.Printf("关闭职位时淘汰应聘记录失败: job=%d, application=%d, %v", j.Id, application.ID, err)
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Rejected++
+	}
+
+	return result, nil
+}
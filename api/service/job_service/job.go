@@ -3,6 +3,7 @@ package job_service
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"hr-api/pkg/cache"
 	"time"
 
@@ -11,14 +12,39 @@ import (
 )
 
 type Job struct {
-	Id         int
-	Name       string
-	Demand     string
-	Desc       string
-	CreateUid  int
-	CreateTime int
-	UpdateTime int
-	Ctx        context.Context
+	Id     int
+	Name   string
+	Demand string
+	Desc   string
+	// PromptTemplate AI分析使用的提示词模板
+	PromptTemplate string
+	CreateUid      int
+	CreateTime     int
+	UpdateTime     int
+	Ctx            context.Context
+
+	// Stages 逗号分隔的招聘流程阶段，为空时使用默认流程
+	Stages string
+	// Criteria 逗号分隔的面试评价维度，为空时使用默认维度
+	Criteria string
+
+	Status           string
+	Headcount        int
+	Department       string
+	Location         string
+	SalaryMin        int
+	SalaryMax        int
+	EmploymentType   string
+	HiringManagerUid int
+	CloseTime        int
+
+	// 列表筛选条件
+	CloseBefore int
+
+	// 候选人筛选条件
+	MinScore int
+	MinYoe   int
+	Skills   []string
 
 	Page       int
 	Limit      int
@@ -27,10 +53,23 @@ type Job struct {
 
 func (j *Job) Add() error {
 	job := map[string]interface{}{
-		"name":       j.Name,
-		"demand":     j.Demand,
-		"desc":       j.Desc,
-		"create_uid": j.CreateUid,
+		"name":            j.Name,
+		"demand":          j.Demand,
+		"desc":            j.Desc,
+		"prompt_template": j.PromptTemplate,
+		"stages":          j.Stages,
+		"criteria":        j.Criteria,
+		"create_uid":      j.CreateUid,
+
+		"status":             j.Status,
+		"headcount":          j.Headcount,
+		"department":         j.Department,
+		"location":           j.Location,
+		"salary_min":         j.SalaryMin,
+		"salary_max":         j.SalaryMax,
+		"employment_type":    j.EmploymentType,
+		"hiring_manager_uid": j.HiringManagerUid,
+		"close_time":         j.CloseTime,
 	}
 	return models.AddJob(job)
 }
@@ -40,6 +79,18 @@ func (j *Job) Edit() error {
 	data["name"] = j.Name
 	data["demand"] = j.Demand
 	data["desc"] = j.Desc
+	data["prompt_template"] = j.PromptTemplate
+	data["stages"] = j.Stages
+	data["criteria"] = j.Criteria
+	data["status"] = j.Status
+	data["headcount"] = j.Headcount
+	data["department"] = j.Department
+	data["location"] = j.Location
+	data["salary_min"] = j.SalaryMin
+	data["salary_max"] = j.SalaryMax
+	data["employment_type"] = j.EmploymentType
+	data["hiring_manager_uid"] = j.HiringManagerUid
+	data["close_time"] = j.CloseTime
 	data["update_time"] = int(time.Now().Unix())
 
 	return models.EditJob(j.Id, data)
@@ -50,7 +101,7 @@ func (r *Job) Delete() error {
 }
 
 func (r *Job) Count() (int, error) {
-	return models.GetJobTotal(r.Name, r.getMaps())
+	return models.GetJobTotal(r.getFilter(), r.getMaps())
 }
 
 func (r *Job) ExistByID() (bool, error) {
@@ -65,7 +116,7 @@ func (r *Job) GetAll() ([]*models.Job, error) {
 
 	cacheService := cache_service.Cache{
 		Name:    cache.CACHE_JOB,
-		Keyword: r.Name,
+		Keyword: r.filterKey(),
 
 		Page:  r.Page,
 		Limit: r.Limit,
@@ -92,7 +143,7 @@ func (r *Job) GetAll() ([]*models.Job, error) {
 		}
 	}
 
-	datas, err = models.GetJobs(r.Page, r.Limit, r.Name, r.getMaps())
+	datas, err = models.GetJobs(r.Page, r.Limit, r.getFilter(), r.getMaps())
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +164,39 @@ func (j *Job) getMaps() map[string]interface{} {
 	if j.Id > 0 {
 		maps["id"] = j.Id
 	}
+	if j.Status != "" {
+		maps["status"] = j.Status
+	}
+	if j.Department != "" {
+		maps["department"] = j.Department
+	}
+	if j.Location != "" {
+		maps["location"] = j.Location
+	}
+	if j.EmploymentType != "" {
+		maps["employment_type"] = j.EmploymentType
+	}
+	if j.HiringManagerUid > 0 {
+		maps["hiring_manager_uid"] = j.HiringManagerUid
+	}
 
 	return maps
 }
+
+func (j *Job) getFilter() models.JobFilter {
+	return models.JobFilter{
+		Keyword:     j.Name,
+		SalaryMin:   j.SalaryMin,
+		SalaryMax:   j.SalaryMax,
+		CloseBefore: j.CloseBefore,
+	}
+}
+
+// filterKey 列表缓存按全部筛选条件区分，没有筛选时与原来一样只用关键字
+func (j *Job) filterKey() string {
+	if len(j.getMaps()) == 0 && j.getFilter() == (models.JobFilter{Keyword: j.Name}) {
+		return j.Name
+	}
+	return fmt.Sprintf("%s_%s_%s_%s_%s_M%d_S%d-%d_C%d", j.Name, j.Status, j.Department, j.Location,
+		j.EmploymentType, j.HiringManagerUid, j.SalaryMin, j.SalaryMax, j.CloseBefore)
+}
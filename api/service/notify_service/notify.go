@@ -0,0 +1,135 @@
+package notify_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/bus"
+	"hr-api/pkg/notify"
+	"hr-api/service/queue_service"
+)
+
+// retryDelays 发送失败后依次等待的时间，用完后放弃
+var retryDelays = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+// SendTask 发送一封通知邮件的消息体，Attempt 为已失败的次数
+type SendTask struct {
+	Event       string              `json:"event"`
+	Uid         int                 `json:"uid"`
+	Fields      map[string]string   `json:"fields"`
+	Attachments []notify.Attachment `json:"attachments,omitempty"`
+	Attempt     int                 `json:"attempt"`
+}
+
+// Notify 给 uids 中接收该事件通知的用户各投递一个邮件发送任务。
+// 未配置 SMTP 时不发送；通知失败不影响业务流程，只记录日志
+func Notify(ctx context.Context, event string, uids []int, fields map[string]string, attachments ...notify.Attachment) {
+	if !notify.Enabled() {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	recipients, err := recipients(event, uids)
+	if err != nil {
+		log.Printf("查询通知偏好失败: event=%s, %v", event, err)
+		return
+	}
+	for _, uid := range recipients {
+		task := SendTask{Event: event, Uid: uid, Fields: fields, Attachments: attachments}
+		if err := queue_service.Publish(ctx, queue_service.TaskSendNotification, task); err != nil {
+			log.Printf("投递通知任务失败: event=%s, uid=%d, %v", event, uid, err)
+		}
+	}
+}
+
+// recipients 去重并排除关闭了该事件通知的用户
+func recipients(event string, uids []int) ([]int, error) {
+	seen := make(map[int]bool)
+	var candidates []int
+	for _, uid := range uids {
+		if uid > 0 && !seen[uid] {
+			seen[uid] = true
+			candidates = append(candidates, uid)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	optOuts, err := models.GetNotificationOptOuts(event, candidates)
+	if err != nil {
+		return nil, err
+	}
+	for _, uid := range optOuts {
+		seen[uid] = false
+	}
+
+	var result []int
+	for _, uid := range candidates {
+		if seen[uid] {
+			result = append(result, uid)
+		}
+	}
+	return result, nil
+}
+
+// HandleSendTask worker 中渲染模板并发送通知邮件。
+// 临时错误按 retryDelays 延迟重新投递，收件人被拒等永久错误直接放弃
+func HandleSendTask(ctx context.Context, payload []byte) error {
+	var task SendTask
+	if err := json.Unmarshal(payload, &task); err != nil || task.Uid <= 0 {
+		return fmt.Errorf("%w: 无效的通知任务: %s", bus.ErrPoison, payload)
+	}
+
+	user, err := models.GetUser(task.Uid)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 || user.Email == "" {
+		// 用户已删除或没有邮箱，任务直接完成
+		log.Printf("[worker] user %d has no email, skip notification %s", task.Uid, task.Event)
+		return nil
+	}
+
+	tmpl, err := notify.Load(task.Event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", bus.ErrPoison, err)
+	}
+	fields := map[string]string{"recipient": user.Username}
+	for k, v := range task.Fields {
+		fields[k] = v
+	}
+	msg, err := tmpl.Render(fields)
+	if err != nil {
+		return fmt.Errorf("%w: %v", bus.ErrPoison, err)
+	}
+	msg.To = []string{user.Email}
+	msg.Attachments = task.Attachments
+
+	err = notify.NewSMTP().Send(ctx, notify.Sender(), msg)
+	if err == nil {
+		log.Printf("[worker] notification %s sent to user %d", task.Event, task.Uid)
+		return nil
+	}
+	if notify.IsPermanent(err) {
+		return fmt.Errorf("%w: 通知 %s 发送给用户 %d 失败: %v", bus.ErrPoison, task.Event, task.Uid, err)
+	}
+	if task.Attempt >= len(retryDelays) {
+		return fmt.Errorf("%w: 通知 %s 发送给用户 %d 失败 %d 次，放弃: %v", bus.ErrPoison, task.Event, task.Uid, task.Attempt+1, err)
+	}
+
+	delay := retryDelays[task.Attempt]
+	task.Attempt++
+	if perr := queue_service.PublishAt(ctx, queue_service.TaskSendNotification, task, time.Now().Add(delay)); perr != nil {
+		// 重新投递失败时交给队列重试
+		return err
+	}
+	log.Printf("[worker] notification %s to user %d failed, retry in %s: %v", task.Event, task.Uid, delay, err)
+	return nil
+}
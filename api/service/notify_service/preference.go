@@ -0,0 +1,41 @@
+package notify_service
+
+import (
+	"hr-api/models"
+	"hr-api/pkg/notify"
+)
+
+// Preference 用户对一个通知事件的设置
+type Preference struct {
+	Event   string `json:"event"`
+	Enabled bool   `json:"enabled"`
+}
+
+// GetPreferences 返回用户对全部通知事件的设置，未设置的事件默认接收
+func GetPreferences(uid int) ([]Preference, error) {
+	saved, err := models.GetNotificationPreferences(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[string]bool)
+	for _, p := range saved {
+		enabled[p.Event] = p.Enabled
+	}
+
+	prefs := make([]Preference, 0, len(notify.Events))
+	for _, event := range notify.Events {
+		on, ok := enabled[event]
+		prefs = append(prefs, Preference{Event: event, Enabled: !ok || on})
+	}
+	return prefs, nil
+}
+
+// SetPreferences 保存用户的通知设置，未提交的事件保持原设置
+func SetPreferences(uid int, prefs []Preference) error {
+	data := make(map[string]bool, len(prefs))
+	for _, p := range prefs {
+		data[p.Event] = p.Enabled
+	}
+	return models.SetNotificationPreferences(uid, data)
+}
@@ -0,0 +1,34 @@
+package offer_service
+
+import (
+	"log"
+	"strconv"
+
+	"hr-api/models"
+	"hr-api/pkg/notify"
+	"hr-api/service/notify_service"
+)
+
+// notifyApprover 通知审批链中第 step 步的审批人
+func (o *Offer) notifyApprover(step int) {
+	offer, err := models.GetOffer(o.Id)
+	if err != nil {
+		log.Printf("发送 offer 审批通知失败: offer=%d, %v", o.Id, err)
+		return
+	}
+
+	for _, approval := range offer.Approvals {
+		if approval.Step != step {
+			continue
+		}
+		notify_service.Notify(o.Ctx, notify.EventOfferApproval, []int{approval.Uid}, map[string]string{
+			"offer_id":       strconv.Itoa(offer.ID),
+			"candidate_name": offer.CandidateName,
+			"job_name":       offer.JobName,
+			"level":          offer.Level,
+			"salary":         strconv.Itoa(offer.Salary),
+			"start_date":     offer.StartDate,
+			"step":           strconv.Itoa(step),
+		})
+	}
+}
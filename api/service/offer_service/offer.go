@@ -0,0 +1,326 @@
+package offer_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/letter"
+	"hr-api/service/application_service"
+)
+
+// ErrInvalidTransition 当前状态下不允许的操作，具体原因包含在错误信息中
+var ErrInvalidTransition = errors.New("offer 当前状态不允许该操作")
+
+// OpenStatuses 未结束的 offer 状态，同一应聘记录同时只能有一个
+var OpenStatuses = []string{models.OfferDraft, models.OfferPendingApproval, models.OfferSent, models.OfferAccepted}
+
+type Offer struct {
+	Id            int
+	ApplicationId int
+	Salary        int
+	Level         string
+	StartDate     string
+	ExpireTime    int
+	Template      string
+	Remark        string
+	Approvers     []int
+	Status        string
+	CreateUid     int
+	Ctx           context.Context
+
+	Page  int
+	Limit int
+}
+
+func (o *Offer) Add() error {
+	id, err := models.AddOffer(map[string]interface{}{
+		"application_id": o.ApplicationId,
+		"salary":         o.Salary,
+		"level":          o.Level,
+		"start_date":     o.StartDate,
+		"expire_time":    o.ExpireTime,
+		"template":       o.Template,
+		"remark":         o.Remark,
+		"create_uid":     o.CreateUid,
+	}, o.Approvers)
+	if err != nil {
+		return err
+	}
+	o.Id = id
+	return nil
+}
+
+// Edit 只有草稿可以修改，审批人为 nil 时保持原审批链
+func (o *Offer) Edit() error {
+	data := make(map[string]interface{})
+	data["salary"] = o.Salary
+	data["level"] = o.Level
+	data["start_date"] = o.StartDate
+	data["expire_time"] = o.ExpireTime
+	data["template"] = o.Template
+	data["remark"] = o.Remark
+	data["update_time"] = int(time.Now().Unix())
+
+	return models.EditOffer(o.Id, data, o.Approvers)
+}
+
+func (o *Offer) Delete() error {
+	return models.DeleteOffer(o.Id)
+}
+
+func (o *Offer) Count() (int, error) {
+	return models.GetOfferTotal(o.getMaps())
+}
+
+func (o *Offer) ExistByID() (bool, error) {
+	return models.ExistOfferByID(o.Id)
+}
+
+func (o *Offer) GetAll() ([]*models.Offer, error) {
+	return models.GetOffers(o.Page, o.Limit, o.getMaps())
+}
+
+func (o *Offer) GetOffer() (*models.Offer, error) {
+	return models.GetOffer(o.Id)
+}
+
+// CountOpen 应聘记录下未结束的 offer 数量
+func (o *Offer) CountOpen() (int, error) {
+	return models.GetOfferTotal(map[string]interface{}{
+		"application_id": o.ApplicationId,
+		"status":         OpenStatuses,
+	})
+}
+
+// PendingApprovals 等待 uid 审批的 offer
+func PendingApprovals(uid int) ([]*models.Offer, error) {
+	return models.GetPendingOfferApprovals(uid)
+}
+
+// load 读取 offer 并确认其处于 status 状态
+func (o *Offer) load(status string) (*models.Offer, error) {
+	offer, err := models.GetOffer(o.Id)
+	if err != nil {
+		return nil, err
+	}
+	if offer.ID == 0 {
+		return nil, fmt.Errorf("offer 不存在: %d", o.Id)
+	}
+	if offer.Status != status {
+		return nil, fmt.Errorf("%w: 当前状态为 %s", ErrInvalidTransition, offer.Status)
+	}
+	return offer, nil
+}
+
+// transit 从 from 状态更新 offer，状态已被并发修改时返回 ErrInvalidTransition
+func (o *Offer) transit(from string, data map[string]interface{}) error {
+	data["update_time"] = int(time.Now().Unix())
+	ok, err := models.TransitOffer(o.Id, from, data)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: 状态已被其他人修改", ErrInvalidTransition)
+	}
+	return nil
+}
+
+// Submit 草稿提交审批，从第一个审批人开始依次审批
+func (o *Offer) Submit() error {
+	offer, err := o.load(models.OfferDraft)
+	if err != nil {
+		return err
+	}
+	if len(offer.Approvals) == 0 {
+		return fmt.Errorf("%w: 未设置审批人", ErrInvalidTransition)
+	}
+	if offer.ExpireTime <= int(time.Now().Unix()) {
+		return fmt.Errorf("%w: 有效期已过，请修改后再提交", ErrInvalidTransition)
+	}
+
+	if err := models.ResetOfferApprovals(o.Id); err != nil {
+		return err
+	}
+	if err := o.transit(models.OfferDraft, map[string]interface{}{
+		"status":       models.OfferPendingApproval,
+		"current_step": 1,
+	}); err != nil {
+		return err
+	}
+
+	o.notifyApprover(1)
+	return nil
+}
+
+// Approve 当前审批人审批。通过后交给下一个审批人，最后一人通过后 offer 发出并开始计算有效期；
+// 驳回后 offer 退回草稿，修改后可重新提交
+func (o *Offer) Approve(uid int, approved bool, comment string) error {
+	offer, err := o.load(models.OfferPendingApproval)
+	if err != nil {
+		return err
+	}
+
+	var current *models.OfferApproval
+	for _, approval := range offer.Approvals {
+		if approval.Step == offer.CurrentStep {
+			current = approval
+		}
+	}
+	if current == nil || current.Uid != uid {
+		return fmt.Errorf("%w: 当前不是您审批", ErrInvalidTransition)
+	}
+
+	now := int(time.Now().Unix())
+	decision := models.ApprovalApproved
+	if !approved {
+		decision = models.ApprovalRejected
+	}
+	if err := models.EditOfferApproval(current.ID, map[string]interface{}{
+		"status":   decision,
+		"comment":  comment,
+		"act_time": now,
+	}); err != nil {
+		return err
+	}
+
+	switch {
+	case !approved:
+		return o.transit(models.OfferPendingApproval, map[string]interface{}{
+			"status":       models.OfferDraft,
+			"current_step": 0,
+		})
+	case current.Step < len(offer.Approvals):
+		if err := o.transit(models.OfferPendingApproval, map[string]interface{}{
+			"current_step": current.Step + 1,
+		}); err != nil {
+			return err
+		}
+		o.notifyApprover(current.Step + 1)
+		return nil
+	}
+	return o.send(offer)
+}
+
+// send 审批全部通过后发出 offer，并投递到期时执行的过期任务
+func (o *Offer) send(offer *models.Offer) error {
+	now := int(time.Now().Unix())
+	if offer.ExpireTime <= now {
+		return fmt.Errorf("%w: 有效期已过，请驳回后修改有效期", ErrInvalidTransition)
+	}
+
+	if err := o.transit(models.OfferPendingApproval, map[string]interface{}{
+		"status":       models.OfferSent,
+		"current_step": 0,
+		"sent_time":    now,
+	}); err != nil {
+		return err
+	}
+
+	return o.scheduleExpiry(offer.ExpireTime)
+}
+
+// Respond 记录候选人接受或拒绝 offer，接受后应聘记录从 offer 阶段推进到录用
+func (o *Offer) Respond(accepted bool, uid int) error {
+	offer, err := o.load(models.OfferSent)
+	if err != nil {
+		return err
+	}
+
+	status := models.OfferDeclined
+	if accepted {
+		status = models.OfferAccepted
+	}
+	if err := o.transit(models.OfferSent, map[string]interface{}{
+		"status":       status,
+		"respond_time": int(time.Now().Unix()),
+	}); err != nil {
+		return err
+	}
+
+	if accepted {
+		application := application_service.Application{Id: offer.ApplicationId, Ctx: o.Ctx}
+		if err := application.MoveStage(application_service.StageHired, "候选人接受 offer", uid); err != nil {
+			log.Printf("offer %d 已接受，应聘记录 %d 未能推进到录用: %v", offer.ID, offer.ApplicationId, err)
+		}
+	}
+	return nil
+}
+
+// Withdraw 撤回审批中或已发出、候选人尚未答复的 offer，状态已被并发修改时返回 ErrInvalidTransition
+func (o *Offer) Withdraw() error {
+	offer, err := models.GetOffer(o.Id)
+	if err != nil {
+		return err
+	}
+	if offer.ID == 0 {
+		return fmt.Errorf("offer 不存在: %d", o.Id)
+	}
+	if !IsWithdrawable(offer.Status) {
+		return fmt.Errorf("%w: 当前状态为 %s", ErrInvalidTransition, offer.Status)
+	}
+
+	return o.transit(offer.Status, map[string]interface{}{
+		"status":       models.OfferWithdrawn,
+		"current_step": 0,
+	})
+}
+
+// IsWithdrawable 审批中与已发出的 offer 可以撤回
+func IsWithdrawable(status string) bool {
+	return status == models.OfferPendingApproval || status == models.OfferSent
+}
+
+// Letter 用候选人与职位信息填充 offer 模板生成 offer 函
+func (o *Offer) Letter() (*letter.Letter, error) {
+	offer, err := models.GetOffer(o.Id)
+	if err != nil {
+		return nil, err
+	}
+	if offer.ID == 0 {
+		return nil, fmt.Errorf("offer 不存在: %d", o.Id)
+	}
+	candidate, err := models.GetCandidate(offer.CandidateId)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := letter.Load(offer.Template)
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.Render(fmt.Sprintf("offer-%d", offer.ID), Fields(offer, candidate, time.Now()))
+}
+
+// Fields offer 模板中可用的占位符
+func Fields(offer *models.Offer, candidate *models.Candidate, now time.Time) map[string]string {
+	return map[string]string{
+		"offer_id":        strconv.Itoa(offer.ID),
+		"candidate_name":  candidate.Name,
+		"candidate_email": candidate.Email,
+		"candidate_phone": candidate.Phone,
+		"job_name":        offer.JobName,
+		"level":           offer.Level,
+		"salary":          strconv.Itoa(offer.Salary),
+		"start_date":      offer.StartDate,
+		"expire_date":     time.Unix(int64(offer.ExpireTime), 0).Format("2006-01-02 15:04"),
+		"today":           now.Format("2006-01-02"),
+	}
+}
+
+func (o *Offer) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if o.ApplicationId > 0 {
+		maps["offers.application_id"] = o.ApplicationId
+	}
+	if o.Status != "" {
+		maps["offers.status"] = o.Status
+	}
+
+	return maps
+}
@@ -0,0 +1,66 @@
+package offer_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/bus"
+	"hr-api/service/queue_service"
+)
+
+// ExpireTask offer 过期任务的消息体
+type ExpireTask struct {
+	OfferId    int `json:"offer_id"`
+	ExpireTime int `json:"expire_time"`
+}
+
+// scheduleExpiry 投递在有效期截止时执行的过期任务，Azure Service Bus 通过 Sender.SendScheduled 定时入队
+func (o *Offer) scheduleExpiry(expireTime int) error {
+	ctx := o.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return queue_service.PublishAt(ctx, queue_service.TaskExpireOffer, ExpireTask{
+		OfferId:    o.Id,
+		ExpireTime: expireTime,
+	}, time.Unix(int64(expireTime), 0))
+}
+
+// HandleExpireTask worker 中将到期仍未答复的 offer 标记为已过期
+func HandleExpireTask(ctx context.Context, payload []byte) error {
+	var task ExpireTask
+	if err := json.Unmarshal(payload, &task); err != nil || task.OfferId <= 0 {
+		return fmt.Errorf("%w: 无效的 offer 过期任务: %s", bus.ErrPoison, payload)
+	}
+
+	offer, err := models.GetOffer(task.OfferId)
+	if err != nil {
+		return err
+	}
+	if offer.ID == 0 || offer.Status != models.OfferSent {
+		// offer 已删除或候选人已答复，任务直接完成
+		return nil
+	}
+	if now := int(time.Now().Unix()); offer.ExpireTime > now {
+		// 定时消息提前投递时放回队列重试
+		return fmt.Errorf("offer %d 尚未到期", offer.ID)
+	}
+
+	service := Offer{Id: offer.ID}
+	err = service.transit(models.OfferSent, map[string]interface{}{"status": models.OfferExpired})
+	if errors.Is(err, ErrInvalidTransition) {
+		// 候选人在此期间已答复
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[worker] offer %d expired", offer.ID)
+	return nil
+}
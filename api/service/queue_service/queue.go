@@ -0,0 +1,169 @@
+package queue_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"hr-api/pkg/bus"
+	"hr-api/pkg/setting"
+)
+
+const (
+	TaskAnalyzeResume = "analyze_resume"
+	TaskExpireOffer   = "expire_offer"
+
+	TaskSendNotification = "send_notification"
+	TaskDeliverWebhook   = "deliver_webhook"
+)
+
+// Task 队列中传递的异步任务
+type Task struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// deliveryCountKey 任务的投递次数保存在 ctx 中，处理函数据此判断是否还会重试
+type deliveryCountKey struct{}
+
+// Handler 异步任务处理函数，返回 bus.ErrPoison 表示任务无法处理、不必重试
+type Handler func(ctx context.Context, payload []byte) error
+
+var (
+	handlers = make(map[string]Handler)
+	queue    bus.Queue
+	mu       sync.Mutex
+)
+
+// Register 注册任务类型对应的处理函数
+func Register(taskType string, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[taskType] = handler
+}
+
+// GetQueue 返回任务队列单例
+func GetQueue() (bus.Queue, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if queue != nil {
+		return queue, nil
+	}
+
+	q, err := bus.NewQueue(setting.QueueSetting.Name)
+	if err != nil {
+		return nil, fmt.Errorf("创建任务队列失败: %w", err)
+	}
+	queue = q
+	return queue, nil
+}
+
+// Publish 投递一个异步任务
+func Publish(ctx context.Context, taskType string, payload interface{}) error {
+	q, body, err := encode(taskType, payload)
+	if err != nil {
+		return err
+	}
+	return q.Send(ctx, body)
+}
+
+// PublishAt 投递一个在指定时间执行的异步任务
+func PublishAt(ctx context.Context, taskType string, payload interface{}, at time.Time) error {
+	q, body, err := encode(taskType, payload)
+	if err != nil {
+		return err
+	}
+	return q.SendScheduled(ctx, body, at)
+}
+
+// Run 持续消费任务队列直到 ctx 结束
+func Run(ctx context.Context) error {
+	q, err := GetQueue()
+	if err != nil {
+		return err
+	}
+	defer q.Close(context.Background())
+
+	for {
+		err := q.Receive(ctx, func(msg *bus.Message) error {
+			return dispatch(ctx, msg)
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Printf("[worker] receive from %s failed: %v", q.Name(), err)
+			time.Sleep(5 * time.Second)
+		}
+	}
+}
+
+func dispatch(ctx context.Context, msg *bus.Message) error {
+	var task Task
+	if err := json.Unmarshal(msg.Body, &task); err != nil {
+		return fmt.Errorf("%w: 无法解析任务: %v", bus.ErrPoison, err)
+	}
+
+	mu.Lock()
+	handler, ok := handlers[task.Type]
+	mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: 未知的任务类型: %s", bus.ErrPoison, task.Type)
+	}
+
+	ctx = context.WithValue(ctx, deliveryCountKey{}, msg.DeliveryCount)
+	err := runHandler(ctx, task.Type, handler, task.Payload)
+	if err != nil && !errors.Is(err, bus.ErrPoison) {
+		log.Printf("[worker] task %s failed (delivery %d): %v", task.Type, msg.DeliveryCount, err)
+	}
+	return err
+}
+
+// FinalDelivery 当前任务是否为最后一次投递，失败后不会再重试；不在 worker 中执行时返回 false
+func FinalDelivery(ctx context.Context) bool {
+	count, ok := ctx.Value(deliveryCountKey{}).(int)
+	max := setting.QueueSetting.MaxDeliveryCount
+	return ok && max > 0 && count >= max
+}
+
+// WillRedeliver 任务以 err 失败后驱动是否还会重新投递；不在 worker 中执行时返回 false
+func WillRedeliver(ctx context.Context, err error) bool {
+	count, ok := ctx.Value(deliveryCountKey{}).(int)
+	return ok && err != nil && !bus.ShouldDeadLetter(err, count)
+}
+
+// runHandler 调用处理函数，处理函数 panic 时转为 ErrPoison：损坏的任务重投只会反复崩溃，
+// 而 memory 驱动下 worker 与 API 在同一进程中，panic 会导致整个服务退出
+func runHandler(ctx context.Context, taskType string, handler Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[worker] task %s panicked: %v\n%s", taskType, r, debug.Stack())
+			err = fmt.Errorf("%w: 任务处理 panic: %v", bus.ErrPoison, r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+func encode(taskType string, payload interface{}) (bus.Queue, []byte, error) {
+	q, err := GetQueue()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := json.Marshal(Task{Type: taskType, Payload: data})
+	if err != nil {
+		return nil, nil, err
+	}
+	return q, body, nil
+}
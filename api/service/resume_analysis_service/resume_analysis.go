@@ -0,0 +1,107 @@
+package resume_analysis_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"hr-api/models"
+	"hr-api/pkg/client"
+)
+
+type ResumeAnalysis struct {
+	Id            int
+	ResumeId      int
+	JobId         int
+	Deployment    string
+	PromptVersion string
+	CreateUid     int
+
+	Page  int
+	Limit int
+}
+
+// Add 保存一次AI分析结果
+func (r *ResumeAnalysis) Add(analysis *client.ResumeAnalysis) error {
+	result, err := json.Marshal(analysis)
+	if err != nil {
+		return err
+	}
+
+	personalInfo, err := json.Marshal(analysis.PersonalInfo)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"resume_id":      r.ResumeId,
+		"job_id":         r.JobId,
+		"deployment":     r.Deployment,
+		"prompt_version": r.PromptVersion,
+		"match_score":    analysis.Analysis.MatchScore,
+		"estimated_yoe":  analysis.Metadata.EstimatedYOE,
+		"skills":         NormalizeSkills(analysis.Skills.Technical),
+		"personal_info":  personalInfo,
+		"result":         result,
+		"create_uid":     r.CreateUid,
+	}
+	return models.AddResumeAnalysis(data)
+}
+
+// NormalizeSkills 将技能列表转为小写、逗号分隔的形式，便于按技能筛选
+func NormalizeSkills(skills []string) string {
+	var normalized []string
+	for _, skill := range skills {
+		skill = strings.ToLower(strings.TrimSpace(skill))
+		skill = strings.ReplaceAll(skill, ",", " ")
+		if skill != "" {
+			normalized = append(normalized, skill)
+		}
+	}
+	return strings.Join(normalized, ",")
+}
+
+func (r *ResumeAnalysis) GetLatest() (*models.ResumeAnalysis, error) {
+	return models.GetLatestResumeAnalysis(r.getMaps())
+}
+
+// GetLatestModelResult 简历在该职位下最新一次由模型完成的分析结果，没有时返回 nil
+func (r *ResumeAnalysis) GetLatestModelResult() (*client.ResumeAnalysis, error) {
+	latest, err := models.GetLatestModelResumeAnalysis(r.ResumeId, r.JobId, client.SourceRules)
+	if err != nil || latest.ID == 0 {
+		return nil, err
+	}
+
+	var analysis client.ResumeAnalysis
+	if err := json.Unmarshal(latest.Result, &analysis); err != nil {
+		return nil, fmt.Errorf("解析已有的分析结果失败: %w", err)
+	}
+	return &analysis, nil
+}
+
+func (r *ResumeAnalysis) GetAll() ([]*models.ResumeAnalysis, error) {
+	return models.GetResumeAnalyses(r.Page, r.Limit, r.getMaps())
+}
+
+func (r *ResumeAnalysis) Count() (int, error) {
+	return models.GetResumeAnalysisTotal(r.getMaps())
+}
+
+func (r *ResumeAnalysis) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if r.ResumeId > 0 {
+		maps["resume_id"] = r.ResumeId
+	}
+	if r.JobId > 0 {
+		maps["job_id"] = r.JobId
+	}
+	if r.Deployment != "" {
+		maps["deployment"] = r.Deployment
+	}
+	if r.PromptVersion != "" {
+		maps["prompt_version"] = r.PromptVersion
+	}
+
+	return maps
+}
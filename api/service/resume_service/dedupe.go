@@ -0,0 +1,147 @@
+package resume_service
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/client"
+	"hr-api/pkg/dedupe"
+	"hr-api/service/application_service"
+)
+
+// Deduplicate 分析完成后查重并归并候选人：
+// 内容哈希相同、手机号或邮箱相同、MinHash 估算的文本相似度超过阈值，依次判定为可能重复，
+// 取最早的一份简历记为 duplicate_of；手机号或邮箱相同的简历归并到同一个候选人，并生成对应职位的应聘记录
+func (r *Resume) Deduplicate(resume *models.Resume, analysis *client.ResumeAnalysis, contentHash string) error {
+	data := map[string]interface{}{
+		"update_time": int(time.Now().Unix()),
+	}
+	duplicateOf, reason := 0, ""
+	candidateId := 0
+
+	if contentHash != "" {
+		data["content_hash"] = contentHash
+		same, err := models.GetFirstResumeByContentHash(resume.ID, contentHash)
+		if err != nil {
+			return err
+		}
+		if same.ID > 0 {
+			duplicateOf, reason = same.ID, models.DuplicateContent
+			candidateId = same.CandidateId
+		}
+	}
+
+	info := analysis.PersonalInfo
+	phone, email := strings.TrimSpace(info.Phone), dedupe.NormalizeEmail(info.Email)
+	phoneKey := dedupe.NormalizePhone(phone)
+	candidate, err := models.GetCandidateByIdentity(phoneKey, email)
+	if err != nil {
+		return err
+	}
+	switch {
+	case candidate.ID > 0:
+		candidateId = candidate.ID
+		r.fillCandidate(candidate, info.Name, phone, email)
+	case candidateId == 0 && (phoneKey != "" || email != ""):
+		candidateId, err = models.AddCandidate(map[string]interface{}{
+			"name":       info.Name,
+			"phone":      phone,
+			"phone_key":  phoneKey,
+			"email":      email,
+			"location":   info.Location,
+			"remark":     "",
+			"create_uid": resume.CreateUid,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if candidateId > 0 {
+		data["candidate_id"] = candidateId
+		if err := application_service.EnsureForResume(resume, candidateId); err != nil {
+			return err
+		}
+		if duplicateOf == 0 {
+			same, err := models.GetFirstResumeByCandidate(resume.ID, candidateId)
+			if err != nil {
+				return err
+			}
+			if same.ID > 0 {
+				duplicateOf, reason = same.ID, models.DuplicateIdentity
+			}
+		}
+	}
+
+	sig := dedupe.Signature(analysis.ResumeText)
+	if sig != nil {
+		data["minhash"] = dedupe.EncodeSignature(sig)
+		bands := dedupe.Bands(sig)
+		if err := models.ReplaceResumeMinhashBands(resume.ID, bands); err != nil {
+			return err
+		}
+		if duplicateOf == 0 {
+			similar, err := r.findSimilar(resume.ID, sig, bands)
+			if err != nil {
+				return err
+			}
+			if similar > 0 {
+				duplicateOf, reason = similar, models.DuplicateSimilar
+			}
+		}
+	}
+
+	data["duplicate_of"] = duplicateOf
+	data["duplicate_reason"] = reason
+	if duplicateOf > 0 {
+		log.Printf("简历 %d 可能与 %d 重复(%s)", resume.ID, duplicateOf, reason)
+	}
+	return models.EditResume(resume.ID, data)
+}
+
+// findSimilar 在 LSH 分段相同的简历中找出最早一份相似度超过阈值的
+func (r *Resume) findSimilar(id int, sig []uint32, bands []int64) (int, error) {
+	ids, err := models.GetResumeIdsByBands(id, bands)
+	if err != nil {
+		return 0, err
+	}
+	others, err := models.GetResumesByIds(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, other := range others {
+		otherSig, err := dedupe.DecodeSignature(other.Minhash)
+		if err != nil {
+			continue
+		}
+		if dedupe.Similarity(sig, otherSig) >= dedupe.SimilarThreshold {
+			return other.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// fillCandidate 补全已有候选人缺失的姓名与联系方式
+func (r *Resume) fillCandidate(candidate *models.Candidate, name, phone, email string) {
+	data := make(map[string]interface{})
+	if candidate.Name == "" && name != "" {
+		data["name"] = name
+	}
+	if candidate.Phone == "" && phone != "" {
+		data["phone"] = phone
+		data["phone_key"] = dedupe.NormalizePhone(phone)
+	}
+	if candidate.Email == "" && email != "" {
+		data["email"] = email
+	}
+	if len(data) == 0 {
+		return
+	}
+
+	data["update_time"] = int(time.Now().Unix())
+	if err := models.EditCandidate(candidate.ID, data); err != nil {
+		log.Printf("补全候选人信息失败: candidate=%d, %v", candidate.ID, err)
+	}
+}
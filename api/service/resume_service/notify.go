@@ -0,0 +1,62 @@
+package resume_service
+
+import (
+	"log"
+	"strconv"
+
+	"hr-api/models"
+	"hr-api/pkg/notify"
+	"hr-api/service/notify_service"
+)
+
+// NotifyUploaded 通知职位负责人收到了新简历，上传人自己负责的职位不通知
+func (r *Resume) NotifyUploaded() {
+	resume, job, err := r.loadForNotify()
+	if err != nil {
+		log.Printf("发送简历上传通知失败: resume=%d, %v", r.Id, err)
+		return
+	}
+	if job.CreateUid == r.CreateUid {
+		return
+	}
+
+	uploader, err := models.GetUser(r.CreateUid)
+	if err != nil {
+		log.Printf("发送简历上传通知失败: resume=%d, %v", r.Id, err)
+		return
+	}
+	notify_service.Notify(r.Ctx, notify.EventResumeUploaded, []int{job.CreateUid}, map[string]string{
+		"resume_id": strconv.Itoa(resume.ID),
+		"filename":  resume.FileName,
+		"job_name":  job.Name,
+		"uploader":  uploader.Username,
+	})
+}
+
+// notifyAnalyzed 通知上传人与职位负责人简历分析完成
+func (r *Resume) notifyAnalyzed(matchScore int) {
+	resume, job, err := r.loadForNotify()
+	if err != nil {
+		log.Printf("发送简历分析通知失败: resume=%d, %v", r.Id, err)
+		return
+	}
+
+	notify_service.Notify(r.Ctx, notify.EventAnalysisDone, []int{resume.CreateUid, job.CreateUid}, map[string]string{
+		"resume_id":   strconv.Itoa(resume.ID),
+		"filename":    resume.FileName,
+		"job_name":    job.Name,
+		"match_score": strconv.Itoa(matchScore),
+	})
+}
+
+func (r *Resume) loadForNotify() (*models.Resume, *models.Job, error) {
+	resume, err := models.GetResume(r.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	job, err := models.GetJob(resume.JobId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resume, job, nil
+}
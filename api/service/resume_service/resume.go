@@ -2,24 +2,40 @@ package resume_service
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"hr-api/pkg/cache"
+	"io"
+	"log"
 	"time"
 
 	"hr-api/models"
+	"hr-api/pkg/analyzer"
+	"hr-api/pkg/blob"
+	"hr-api/pkg/client"
+	"hr-api/pkg/dedupe"
+	"hr-api/pkg/setting"
+	"hr-api/service/ai_usage_service"
+	"hr-api/service/audit_service"
 	"hr-api/service/cache_service"
+	"hr-api/service/resume_analysis_service"
 )
 
 type Resume struct {
-	Id         int
-	JobId      int
-	Url        string
-	FileName   string
-	Size       int
-	CreateUid  int
-	CreateTime int
-	UpdateTime int
-	Ctx        context.Context
+	Id          int
+	JobId       int
+	Url         string
+	FileName    string
+	Size        int
+	ContentHash string
+	CreateUid   int
+	CreateTime  int
+	UpdateTime  int
+	Ctx         context.Context
+
+	// FallbackToRules 模型分析失败时保存规则提取的部分结果，只在不会再重试时开启
+	FallbackToRules bool
 
 	Page       int
 	Limit      int
@@ -28,12 +44,33 @@ type Resume struct {
 
 func (r *Resume) Add() error {
 	resume := map[string]interface{}{
-		"job_id":     r.JobId,
-		"url":        r.Url,
-		"size":       r.Size,
-		"create_uid": r.CreateUid,
+		"job_id":       r.JobId,
+		"url":          r.Url,
+		"size":         r.Size,
+		"content_hash": r.ContentHash,
+		"create_uid":   r.CreateUid,
+	}
+	id, err := models.AddResume(resume)
+	if err != nil {
+		return err
+	}
+	r.Id = id
+
+	// 上传时已知内容哈希的，入库即可标记完全相同的文件，分析完成后再做完整查重
+	if r.ContentHash != "" {
+		same, err := models.GetFirstResumeByContentHash(id, r.ContentHash)
+		if err != nil {
+			return err
+		}
+		if same.ID > 0 {
+			return models.EditResume(id, map[string]interface{}{
+				"duplicate_of":     same.ID,
+				"duplicate_reason": models.DuplicateContent,
+				"candidate_id":     same.CandidateId,
+			})
+		}
 	}
-	return models.AddResume(resume)
+	return nil
 }
 
 func (r *Resume) Edit() error {
@@ -46,6 +83,12 @@ func (r *Resume) Edit() error {
 }
 
 func (r *Resume) Delete() error {
+	if err := models.DeleteResumeAnalyses(r.Id); err != nil {
+		return err
+	}
+	if err := models.DeleteResumeMinhashBands(r.Id); err != nil {
+		return err
+	}
 	return models.DeleteResume(r.Id)
 }
 
@@ -106,6 +149,120 @@ func (r *Resume) GetResume() (*models.Resume, error) {
 	return models.GetResume(r.Id)
 }
 
+// Analyze 下载简历文件并结合关联的招聘需求进行AI分析
+func (r *Resume) Analyze() (*client.ResumeAnalysis, error) {
+	resume, err := models.GetResume(r.Id)
+	if err != nil {
+		return nil, err
+	}
+	if resume.ID == 0 {
+		return nil, fmt.Errorf("简历记录不存在: %d", r.Id)
+	}
+
+	job, err := models.GetJob(resume.JobId)
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == 0 {
+		return nil, fmt.Errorf("招聘需求不存在: %d", resume.JobId)
+	}
+
+	// 预算用完时退回规则提取；模型不可用时先交给队列重试，最后一次仍失败才退回规则提取
+	var resumeAnalyzer *analyzer.ResumeAnalyzer
+	if err := ai_usage_service.CheckBudget(); err != nil {
+		log.Printf("使用规则提取简历字段: resume=%d, %v", resume.ID, err)
+	} else if resumeAnalyzer, err = analyzer.NewResumeAnalyzer(&analyzer.AnalyzerConfig{
+		PromptTemplate:   job.PromptTemplate,
+		FallbackToRules:  r.FallbackToRules,
+		RedactCategories: setting.LLMSetting.RedactCategories,
+	}); err != nil {
+		if !r.FallbackToRules {
+			return nil, fmt.Errorf("创建简历分析器失败: %w", err)
+		}
+		log.Printf("创建简历分析器失败，使用规则提取: resume=%d, %v", resume.ID, err)
+	}
+
+	client.Report(r.Ctx, client.StageDownload, resume.FileName, nil)
+	// 下载流直接交给解析器，按内容识别格式，不再写临时文件
+	obj, err := blob.Open(r.Ctx, resume.Url)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Body.Close()
+
+	// 解析时顺带计算文件内容哈希，用于查重
+	hasher := dedupe.NewHasher()
+	body := io.TeeReader(obj.Body, hasher)
+
+	var analysis *client.ResumeAnalysis
+	if resumeAnalyzer == nil {
+		analysis, err = analyzer.ExtractReader(r.Ctx, body, resume.FileName, obj.ContentType)
+	} else {
+		var usage *client.Usage
+		analysis, usage, err = resumeAnalyzer.AnalyzeReader(r.Ctx, job.Name, job.Demand, job.Desc, body, resume.FileName, obj.ContentType)
+		if usage != nil {
+			usageService := ai_usage_service.AIUsage{
+				Uid:      r.CreateUid,
+				JobId:    job.ID,
+				ResumeId: resume.ID,
+			}
+			if err := usageService.Add(usage, err == nil && analysis.Metadata.Source == client.SourceLLM); err != nil {
+				log.Printf("记录AI用量失败: resume=%d, %v", resume.ID, err)
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(analysis.Metadata.Redacted) > 0 {
+		auditService := audit_service.Audit{
+			Action:     audit_service.ActionRedactPII,
+			TargetType: audit_service.TargetResume,
+			TargetId:   resume.ID,
+			Uid:        r.CreateUid,
+		}
+		detail := map[string]interface{}{
+			"deployment": resumeAnalyzer.Deployment(),
+			"masked":     analysis.Metadata.Redacted,
+		}
+		if err := auditService.Add(detail); err != nil {
+			log.Printf("记录脱敏审计日志失败: resume=%d, %v", resume.ID, err)
+		}
+	}
+
+	analysisService := resume_analysis_service.ResumeAnalysis{
+		ResumeId:   resume.ID,
+		JobId:      job.ID,
+		Deployment: client.SourceRules,
+		CreateUid:  r.CreateUid,
+	}
+	if err := r.Deduplicate(resume, analysis, hex.EncodeToString(hasher.Sum(nil))); err != nil {
+		log.Printf("简历查重失败: resume=%d, %v", resume.ID, err)
+	}
+
+	if analysis.Metadata.Source != client.SourceLLM {
+		// 规则提取没有匹配度，不能覆盖已有的模型分析结果
+		existing, err := analysisService.GetLatestModelResult()
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			log.Printf("保留已有的模型分析结果，不保存规则提取结果: resume=%d", resume.ID)
+			return existing, nil
+		}
+	} else {
+		analysisService.Deployment = resumeAnalyzer.Deployment()
+		analysisService.PromptVersion = resumeAnalyzer.PromptVersion()
+	}
+	if err := analysisService.Add(analysis); err != nil {
+		return nil, fmt.Errorf("保存分析结果失败: %w", err)
+	}
+	r.emitHighMatch(resume, job, analysis)
+
+	return analysis, nil
+}
+
 func (r *Resume) getMaps() map[string]interface{} {
 	maps := make(map[string]interface{})
 
@@ -0,0 +1,62 @@
+package resume_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"hr-api/models"
+	"hr-api/pkg/bus"
+	"hr-api/service/queue_service"
+)
+
+// AnalyzeTask 简历分析任务的消息体
+type AnalyzeTask struct {
+	ResumeId  int `json:"resume_id"`
+	CreateUid int `json:"create_uid"`
+	// BatchId 所属的批量分析批次，单独分析时为 0
+	BatchId int `json:"batch_id,omitempty"`
+}
+
+// QueueAnalyze 投递简历分析任务
+func (r *Resume) QueueAnalyze() error {
+	return queue_service.Publish(r.Ctx, queue_service.TaskAnalyzeResume, AnalyzeTask{
+		ResumeId:  r.Id,
+		CreateUid: r.CreateUid,
+	})
+}
+
+// HandleAnalyzeTask worker 中执行简历分析任务
+func HandleAnalyzeTask(ctx context.Context, payload []byte) error {
+	var task AnalyzeTask
+	if err := json.Unmarshal(payload, &task); err != nil || task.ResumeId <= 0 {
+		return fmt.Errorf("%w: 无效的简历分析任务: %s", bus.ErrPoison, payload)
+	}
+
+	exists, err := models.ExistResumeByID(task.ResumeId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		// 简历已被删除，任务直接完成
+		log.Printf("[worker] resume %d no longer exists, skip analysis", task.ResumeId)
+		return nil
+	}
+
+	// 模型调用失败先交给队列重试，最后一次投递仍失败时才保存规则提取的结果
+	service := Resume{
+		Id:              task.ResumeId,
+		CreateUid:       task.CreateUid,
+		Ctx:             ctx,
+		FallbackToRules: queue_service.FinalDelivery(ctx),
+	}
+	analysis, err := service.Analyze()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[worker] resume %d analyzed, match score %d", task.ResumeId, analysis.Analysis.MatchScore)
+	service.notifyAnalyzed(analysis.Analysis.MatchScore)
+	return nil
+}
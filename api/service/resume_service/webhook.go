@@ -0,0 +1,31 @@
+package resume_service
+
+import (
+	"fmt"
+
+	"hr-api/models"
+	"hr-api/pkg/client"
+	"hr-api/pkg/webhook"
+	"hr-api/service/webhook_service"
+)
+
+// emitHighMatch 向职位的 webhook 订阅投递匹配度事件，是否达到阈值由各订阅自行判断；
+// 规则提取的结果没有可信的匹配度，不投递
+func (r *Resume) emitHighMatch(resume *models.Resume, job *models.Job, analysis *client.ResumeAnalysis) {
+	if analysis.Metadata.Source != client.SourceLLM {
+		return
+	}
+
+	score := analysis.Analysis.MatchScore
+	name := analysis.PersonalInfo.Name
+	if name == "" {
+		name = resume.FileName
+	}
+	e := webhook.NewEvent(webhook.EventHighMatchScore, job.ID, fmt.Sprintf("高匹配度简历：%s 匹配「%s」%d 分", name, job.Name, score))
+	e.Add("job_name", "职位", job.Name).
+		Add("candidate_name", "候选人", name).
+		Add("match_score", "匹配度", score).
+		Add("resume_id", "", resume.ID).
+		Add("filename", "简历", resume.FileName)
+	webhook_service.Emit(r.Ctx, e)
+}
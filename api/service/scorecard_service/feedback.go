@@ -0,0 +1,146 @@
+package scorecard_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"hr-api/models"
+	"hr-api/pkg/client"
+)
+
+// CriterionSummary 一个评价维度的平均分
+type CriterionSummary struct {
+	Criterion string  `json:"criterion"`
+	Average   float64 `json:"average"`
+	Count     int     `json:"count"`
+}
+
+// Summary 一次应聘全部面试评价的汇总
+type Summary struct {
+	Scorecards int                 `json:"scorecards"`
+	Average    float64             `json:"average"`
+	Criteria   []*CriterionSummary `json:"criteria"`
+	Hire       int                 `json:"hire"`
+	NoHire     int                 `json:"no_hire"`
+}
+
+// Feedback 应聘记录的面试反馈。当前用户还有未提交评价的面试时 Hidden 为 true，
+// 只返回自己的评价且不返回汇总，避免受到其他面试官意见的影响
+type Feedback struct {
+	ApplicationId int                 `json:"application_id"`
+	Criteria      []string            `json:"criteria"`
+	Hidden        bool                `json:"hidden"`
+	Pending       int                 `json:"pending"`
+	Summary       *Summary            `json:"summary"`
+	Scorecards    []*models.Scorecard `json:"scorecards"`
+	AIAnalysis    *client.JobAnalysis `json:"ai_analysis"`
+}
+
+// Summarize 按评价维度汇总平均分与录用建议，职位调整后已不存在的维度不参与汇总
+func Summarize(criteria []string, scorecards []*models.Scorecard) *Summary {
+	summary := &Summary{Criteria: make([]*CriterionSummary, 0, len(criteria))}
+	byCriterion := make(map[string]*CriterionSummary, len(criteria))
+	sums := make(map[string]int, len(criteria))
+	for _, criterion := range criteria {
+		c := &CriterionSummary{Criterion: criterion}
+		summary.Criteria = append(summary.Criteria, c)
+		byCriterion[criterion] = c
+	}
+
+	total, count := 0, 0
+	for _, scorecard := range scorecards {
+		var ratings []Rating
+		if err := json.Unmarshal(scorecard.Ratings, &ratings); err != nil {
+			continue
+		}
+		summary.Scorecards++
+		switch scorecard.Recommendation {
+		case models.RecommendHire:
+			summary.Hire++
+		case models.RecommendNoHire:
+			summary.NoHire++
+		}
+
+		for _, rating := range ratings {
+			c, ok := byCriterion[rating.Criterion]
+			if !ok {
+				continue
+			}
+			c.Count++
+			sums[rating.Criterion] += rating.Score
+			total += rating.Score
+			count++
+		}
+	}
+
+	for _, c := range summary.Criteria {
+		if c.Count > 0 {
+			c.Average = round2(float64(sums[c.Criterion]) / float64(c.Count))
+		}
+	}
+	if count > 0 {
+		summary.Average = round2(float64(total) / float64(count))
+	}
+	return summary
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// GetFeedback 汇总应聘记录的全部面试评价，并附上该简历针对职位的最新 AI 分析
+func GetFeedback(applicationId int, uid int) (*Feedback, error) {
+	application, err := models.GetApplication(applicationId)
+	if err != nil {
+		return nil, err
+	}
+	if application.ID == 0 {
+		return nil, fmt.Errorf("应聘记录不存在: %d", applicationId)
+	}
+	job, err := models.GetJob(application.JobId)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := &Feedback{
+		ApplicationId: applicationId,
+		Criteria:      ParseCriteria(job.Criteria),
+	}
+
+	feedback.Pending, err = models.CountPendingScorecards(applicationId, uid)
+	if err != nil {
+		return nil, err
+	}
+	feedback.Hidden = feedback.Pending > 0
+
+	maps := map[string]interface{}{"scorecards.application_id": applicationId}
+	if feedback.Hidden {
+		maps["scorecards.uid"] = uid
+	}
+	feedback.Scorecards, err = models.GetScorecards(maps)
+	if err != nil {
+		return nil, err
+	}
+	if !feedback.Hidden {
+		feedback.Summary = Summarize(feedback.Criteria, feedback.Scorecards)
+	}
+
+	if application.ResumeId > 0 {
+		analysis, err := models.GetLatestResumeAnalysis(map[string]interface{}{
+			"resume_id": application.ResumeId,
+			"job_id":    application.JobId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if analysis.ID > 0 {
+			var result client.ResumeAnalysis
+			if err := json.Unmarshal(analysis.Result, &result); err == nil {
+				feedback.AIAnalysis = &result.Analysis
+			}
+		}
+	}
+
+	return feedback, nil
+}
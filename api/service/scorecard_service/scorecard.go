@@ -0,0 +1,135 @@
+package scorecard_service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-api/models"
+)
+
+// 评分范围
+const (
+	MinScore = 1
+	MaxScore = 5
+)
+
+const (
+	maxCriteria     = 10
+	maxCriterionLen = 32
+)
+
+// DefaultCriteria 职位未配置评价维度时使用的维度
+var DefaultCriteria = []string{"technical_skill", "communication", "culture_fit"}
+
+// ErrInvalidScorecard 评价内容不符合职位的评价维度或评分范围
+var ErrInvalidScorecard = errors.New("面试评价无效")
+
+// NormalizeCriteria 校验评价维度并转为逗号分隔的形式保存到职位
+func NormalizeCriteria(criteria []string) (string, error) {
+	if len(criteria) > maxCriteria {
+		return "", fmt.Errorf("评价维度不能超过 %d 个", maxCriteria)
+	}
+
+	seen := make(map[string]bool, len(criteria))
+	normalized := make([]string, 0, len(criteria))
+	for _, criterion := range criteria {
+		criterion = strings.TrimSpace(criterion)
+		switch {
+		case criterion == "":
+			return "", fmt.Errorf("评价维度不能为空")
+		case strings.Contains(criterion, ","):
+			return "", fmt.Errorf("评价维度不能包含逗号: %s", criterion)
+		case len([]rune(criterion)) > maxCriterionLen:
+			return "", fmt.Errorf("评价维度不能超过 %d 个字符: %s", maxCriterionLen, criterion)
+		case seen[criterion]:
+			return "", fmt.Errorf("评价维度重复: %s", criterion)
+		}
+		seen[criterion] = true
+		normalized = append(normalized, criterion)
+	}
+	return strings.Join(normalized, ","), nil
+}
+
+// ParseCriteria 解析职位保存的评价维度
+func ParseCriteria(criteria string) []string {
+	if strings.TrimSpace(criteria) == "" {
+		return DefaultCriteria
+	}
+	return strings.Split(criteria, ",")
+}
+
+// Rating 一个评价维度的评分与评语
+type Rating struct {
+	Criterion string `json:"criterion"`
+	Score     int    `json:"score"`
+	Comment   string `json:"comment"`
+}
+
+type Scorecard struct {
+	InterviewId    int
+	ApplicationId  int
+	Uid            int
+	Ratings        []Rating
+	Recommendation string
+	Comment        string
+}
+
+// Validate 每个评价维度必须且只能评分一次，分数为 1-5，建议为 hire 或 no_hire
+func (s *Scorecard) Validate(criteria []string) error {
+	if s.Recommendation != models.RecommendHire && s.Recommendation != models.RecommendNoHire {
+		return fmt.Errorf("%w: 录用建议只能为 %s 或 %s", ErrInvalidScorecard, models.RecommendHire, models.RecommendNoHire)
+	}
+
+	rated := make(map[string]bool, len(s.Ratings))
+	for _, rating := range s.Ratings {
+		if rated[rating.Criterion] {
+			return fmt.Errorf("%w: 评价维度重复评分: %s", ErrInvalidScorecard, rating.Criterion)
+		}
+		rated[rating.Criterion] = true
+		if rating.Score < MinScore || rating.Score > MaxScore {
+			return fmt.Errorf("%w: %s 的评分必须在 %d-%d 之间", ErrInvalidScorecard, rating.Criterion, MinScore, MaxScore)
+		}
+	}
+	for _, criterion := range criteria {
+		if !rated[criterion] {
+			return fmt.Errorf("%w: 缺少评价维度 %s", ErrInvalidScorecard, criterion)
+		}
+	}
+	if len(rated) != len(criteria) {
+		return fmt.Errorf("%w: 存在职位未定义的评价维度", ErrInvalidScorecard)
+	}
+	return nil
+}
+
+// Submit 提交面试评价，已提交过时覆盖原评价
+func (s *Scorecard) Submit() error {
+	ratings, err := json.Marshal(s.Ratings)
+	if err != nil {
+		return err
+	}
+
+	existing, err := models.GetScorecard(s.InterviewId, s.Uid)
+	if err != nil {
+		return err
+	}
+	if existing.ID > 0 {
+		return models.EditScorecard(existing.ID, map[string]interface{}{
+			"ratings":        ratings,
+			"recommendation": s.Recommendation,
+			"comment":        s.Comment,
+			"update_time":    int(time.Now().Unix()),
+		})
+	}
+
+	return models.AddScorecard(map[string]interface{}{
+		"interview_id":   s.InterviewId,
+		"application_id": s.ApplicationId,
+		"uid":            s.Uid,
+		"ratings":        ratings,
+		"recommendation": s.Recommendation,
+		"comment":        s.Comment,
+	})
+}
@@ -0,0 +1,206 @@
+package webhook_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"hr-api/models"
+	"hr-api/pkg/bus"
+	"hr-api/pkg/webhook"
+	"hr-api/service/queue_service"
+)
+
+// ErrNotRetryable 投递已成功或正在重试，不能手动重新投递
+var ErrNotRetryable = errors.New("该投递不能重新投递")
+
+// retryDelays 投递失败后依次等待的时间，用完后标记为失败
+var retryDelays = []time.Duration{30 * time.Second, 2 * time.Minute, 10 * time.Minute, time.Hour}
+
+// DeliverTask 投递 webhook 的消息体
+type DeliverTask struct {
+	DeliveryId int `json:"delivery_id"`
+}
+
+// Delivery 投递记录查询
+type Delivery struct {
+	Id        int
+	WebhookId int
+	Status    string
+
+	Page  int
+	Limit int
+}
+
+func (d *Delivery) GetAll() ([]*models.WebhookDelivery, error) {
+	return models.GetWebhookDeliveries(d.Page, d.Limit, d.getMaps())
+}
+
+func (d *Delivery) Count() (int, error) {
+	return models.GetWebhookDeliveryTotal(d.getMaps())
+}
+
+func (d *Delivery) GetDelivery() (*models.WebhookDelivery, error) {
+	return models.GetWebhookDelivery(d.Id)
+}
+
+func (d *Delivery) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if d.WebhookId > 0 {
+		maps["webhook_id"] = d.WebhookId
+	}
+	if d.Status != "" {
+		maps["status"] = d.Status
+	}
+
+	return maps
+}
+
+// Emit 把事件投递给职位下订阅了该事件的 webhook，每个订阅生成一条投递记录并异步发送；
+// 高匹配度事件只投递给分数达到阈值的订阅。投递失败不影响业务流程，只记录日志
+func Emit(ctx context.Context, e *webhook.Event) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	webhooks, err := models.GetWebhooks(0, 0, map[string]interface{}{
+		"webhooks.job_id":  e.JobId,
+		"webhooks.enabled": true,
+	})
+	if err != nil {
+		log.Printf("查询 webhook 订阅失败: job=%d, %v", e.JobId, err)
+		return
+	}
+
+	for _, w := range webhooks {
+		if !Subscribes(w, e.Type) {
+			continue
+		}
+		if score, ok := e.Data["match_score"].(int); ok && e.Type == webhook.EventHighMatchScore && score < w.MinScore {
+			continue
+		}
+
+		payload, err := webhook.Payload(w.Kind, e)
+		if err != nil {
+			log.Printf("生成 webhook 请求失败: webhook=%d, %v", w.ID, err)
+			continue
+		}
+		id, err := models.AddWebhookDelivery(map[string]interface{}{
+			"webhook_id": w.ID,
+			"event":      e.Type,
+			"event_id":   e.ID,
+			"payload":    string(payload),
+		})
+		if err != nil {
+			log.Printf("保存 webhook 投递记录失败: webhook=%d, %v", w.ID, err)
+			continue
+		}
+		if err := queue_service.Publish(ctx, queue_service.TaskDeliverWebhook, DeliverTask{DeliveryId: id}); err != nil {
+			log.Printf("投递 webhook 任务失败: delivery=%d, %v", id, err)
+		}
+	}
+}
+
+// Redeliver 手动重新投递一条失败的记录，重新开始计算重试次数
+func (d *Delivery) Redeliver(ctx context.Context) error {
+	delivery, err := models.GetWebhookDelivery(d.Id)
+	if err != nil {
+		return err
+	}
+	if delivery.ID == 0 {
+		return fmt.Errorf("投递记录不存在: %d", d.Id)
+	}
+	if delivery.Status != models.DeliveryFailed {
+		return fmt.Errorf("%w: 当前状态为 %s", ErrNotRetryable, delivery.Status)
+	}
+
+	if err := models.EditWebhookDelivery(delivery.ID, map[string]interface{}{
+		"status":          models.DeliveryPending,
+		"attempts":        0,
+		"next_retry_time": 0,
+		"update_time":     int(time.Now().Unix()),
+	}); err != nil {
+		return err
+	}
+	return queue_service.Publish(ctx, queue_service.TaskDeliverWebhook, DeliverTask{DeliveryId: delivery.ID})
+}
+
+// HandleDeliverTask worker 中发送 webhook 请求并记录结果。
+// 网络错误、超时、限流与 5xx 按 retryDelays 延迟重试，其余 4xx 与指向内网的地址直接标记为失败
+func HandleDeliverTask(ctx context.Context, payload []byte) error {
+	var task DeliverTask
+	if err := json.Unmarshal(payload, &task); err != nil || task.DeliveryId <= 0 {
+		return fmt.Errorf("%w: 无效的 webhook 投递任务: %s", bus.ErrPoison, payload)
+	}
+
+	delivery, err := models.GetWebhookDelivery(task.DeliveryId)
+	if err != nil {
+		return err
+	}
+	if delivery.ID == 0 || delivery.Status == models.DeliverySuccess || delivery.Status == models.DeliveryFailed {
+		// 订阅已删除或已有结果，任务直接完成
+		return nil
+	}
+	w, err := models.GetWebhook(delivery.WebhookId)
+	if err != nil {
+		return err
+	}
+	if w.ID == 0 {
+		return nil
+	}
+
+	resp, sendErr := webhook.Send(ctx, &webhook.Request{
+		Kind:       w.Kind,
+		Url:        w.Url,
+		Secret:     w.Secret,
+		Event:      delivery.Event,
+		DeliveryId: fmt.Sprintf("%s-%d", delivery.EventId, delivery.ID),
+		Body:       []byte(delivery.Payload),
+	})
+
+	now := time.Now()
+	data := map[string]interface{}{
+		"attempts":        delivery.Attempts + 1,
+		"response_code":   0,
+		"response_body":   "",
+		"error":           "",
+		"next_retry_time": 0,
+		"update_time":     int(now.Unix()),
+	}
+	if resp != nil {
+		data["response_code"] = resp.StatusCode
+		data["response_body"] = resp.Body
+	}
+
+	switch {
+	case sendErr == nil && resp.Success():
+		data["status"] = models.DeliverySuccess
+		return models.EditWebhookDelivery(delivery.ID, data)
+	case sendErr != nil:
+		data["error"] = sendErr.Error()
+	default:
+		data["error"] = fmt.Sprintf("订阅方返回 %d", resp.StatusCode)
+	}
+
+	if !resp.Retryable() || errors.Is(sendErr, webhook.ErrForbiddenAddress) || delivery.Attempts >= len(retryDelays) {
+		data["status"] = models.DeliveryFailed
+		log.Printf("[worker] webhook delivery %d failed after %d attempts: %s", delivery.ID, delivery.Attempts+1, data["error"])
+		return models.EditWebhookDelivery(delivery.ID, data)
+	}
+
+	at := now.Add(retryDelays[delivery.Attempts])
+	data["status"] = models.DeliveryRetrying
+	data["next_retry_time"] = int(at.Unix())
+	if err := models.EditWebhookDelivery(delivery.ID, data); err != nil {
+		return err
+	}
+	if err := queue_service.PublishAt(ctx, queue_service.TaskDeliverWebhook, task, at); err != nil {
+		// 重新投递失败时交给队列重试
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package webhook_service
+
+import (
+	"strings"
+	"time"
+
+	"hr-api/models"
+)
+
+type Webhook struct {
+	Id        int
+	JobId     int
+	Name      string
+	Kind      string
+	Url       string
+	Secret    string
+	Events    []string
+	MinScore  int
+	Enabled   bool
+	CreateUid int
+
+	Page  int
+	Limit int
+}
+
+func (w *Webhook) Add() error {
+	id, err := models.AddWebhook(map[string]interface{}{
+		"job_id":     w.JobId,
+		"name":       w.Name,
+		"kind":       w.Kind,
+		"url":        w.Url,
+		"secret":     w.Secret,
+		"events":     strings.Join(w.Events, ","),
+		"min_score":  w.MinScore,
+		"enabled":    w.Enabled,
+		"create_uid": w.CreateUid,
+	})
+	if err != nil {
+		return err
+	}
+	w.Id = id
+	return nil
+}
+
+func (w *Webhook) Edit() error {
+	data := make(map[string]interface{})
+	data["name"] = w.Name
+	data["kind"] = w.Kind
+	data["url"] = w.Url
+	data["secret"] = w.Secret
+	data["events"] = strings.Join(w.Events, ",")
+	data["min_score"] = w.MinScore
+	data["enabled"] = w.Enabled
+	data["update_time"] = int(time.Now().Unix())
+
+	return models.EditWebhook(w.Id, data)
+}
+
+func (w *Webhook) Delete() error {
+	return models.DeleteWebhook(w.Id)
+}
+
+func (w *Webhook) Count() (int, error) {
+	return models.GetWebhookTotal(w.getMaps())
+}
+
+func (w *Webhook) ExistByID() (bool, error) {
+	return models.ExistWebhookByID(w.Id)
+}
+
+func (w *Webhook) GetAll() ([]*models.Webhook, error) {
+	return models.GetWebhooks(w.Page, w.Limit, w.getMaps())
+}
+
+func (w *Webhook) GetWebhook() (*models.Webhook, error) {
+	return models.GetWebhook(w.Id)
+}
+
+// Subscribes 订阅是否包含事件
+func Subscribes(webhook *models.Webhook, event string) bool {
+	for _, e := range strings.Split(webhook.Events, ",") {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Webhook) getMaps() map[string]interface{} {
+	maps := make(map[string]interface{})
+
+	if w.JobId > 0 {
+		maps["webhooks.job_id"] = w.JobId
+	}
+
+	return maps
+}
@@ -0,0 +1,196 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hr-api/pkg/analyzer"
+	"hr-api/pkg/client"
+	"hr-api/pkg/parser"
+)
+
+func TestAnalyzeFileWithFakeLLM(t *testing.T) {
+	resumePath := filepath.Join(t.TempDir(), "resume.txt")
+	resume := "张三 zhangsan@example.com 13800138000\n5年 Golang 开发经验，熟悉 MySQL 与 Redis"
+	if err := os.WriteFile(resumePath, []byte(resume), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resumeAnalyzer, err := analyzer.NewResumeAnalyzerWithLLM(client.NewFakeResumeLLM(), &analyzer.AnalyzerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, _, err := resumeAnalyzer.AnalyzeFile(context.Background(), "后端工程师", "熟悉 Golang、MySQL、Kafka", "", resumePath)
+	if err != nil {
+		t.Fatalf("AnalyzeFile err: %v", err)
+	}
+
+	if analysis.Analysis.MatchScore != 66 {
+		t.Errorf("expected match score 66, got %d", analysis.Analysis.MatchScore)
+	}
+	if analysis.PersonalInfo.Email != "zhangsan@example.com" {
+		t.Errorf("unexpected email: %s", analysis.PersonalInfo.Email)
+	}
+	if analysis.PersonalInfo.Phone != "13800138000" {
+		t.Errorf("unexpected phone: %s", analysis.PersonalInfo.Phone)
+	}
+	if analysis.Metadata.Source != client.SourceLLM || analysis.PersonalInfo.Name != "张三" {
+		t.Errorf("expected llm result cross-checked with rules, got source=%q name=%q",
+			analysis.Metadata.Source, analysis.PersonalInfo.Name)
+	}
+	if resumeAnalyzer.Deployment() != client.ProviderFake {
+		t.Errorf("unexpected deployment: %s", resumeAnalyzer.Deployment())
+	}
+}
+
+func TestResumeAnalysisValidate(t *testing.T) {
+	analysis := &client.ResumeAnalysis{Summary: "后端工程师"}
+	analysis.PersonalInfo.Name = "张三"
+	analysis.Analysis.MatchScore = 120
+
+	invalid := analysis.Validate()
+	if len(invalid) != 1 || invalid[0].Field != "analysis.match_score" {
+		t.Errorf("unexpected invalid fields: %v", invalid)
+	}
+
+	analysis.Analysis.MatchScore = 80
+	if invalid := analysis.Validate(); len(invalid) != 0 {
+		t.Errorf("expected valid analysis, got %v", invalid)
+	}
+
+	// 简历中没有姓名时模型返回空字符串，不应要求修复
+	analysis.PersonalInfo.Name = ""
+	if invalid := analysis.Validate(); len(invalid) != 0 {
+		t.Errorf("expected empty name to be valid, got %v", invalid)
+	}
+}
+
+func TestExtractFields(t *testing.T) {
+	resume := `戴先生
+男 | 36岁(1988年5月) | 现居住 长沙 | 8年工作经验
+手机：+86 138-0013-8000
+邮箱：dai@example.com
+
+工作经历
+2019.07 - 至今 深信服科技股份有限公司 (4年)
+高级开发工程师 | 月薪 25000
+2015/03-2019/06 长沙某某网络有限公司 后端工程师
+
+教育经历
+2011.09-2015.06 湖南大学 计算机科学与技术 本科`
+
+	analysis := parser.ExtractFields(resume)
+	info := analysis.PersonalInfo
+	if info.Name != "戴先生" || info.Gender != "男" || info.Age != 36 {
+		t.Errorf("unexpected personal info: %+v", info)
+	}
+	if info.Phone != "13800138000" || info.Email != "dai@example.com" {
+		t.Errorf("unexpected contact: %s %s", info.Phone, info.Email)
+	}
+	if analysis.Metadata.EstimatedYOE != 8 || analysis.Metadata.Source != client.SourceRules {
+		t.Errorf("unexpected metadata: %+v", analysis.Metadata)
+	}
+
+	if len(analysis.WorkExperience) != 2 {
+		t.Fatalf("expected 2 work experiences, got %+v", analysis.WorkExperience)
+	}
+	first := analysis.WorkExperience[0]
+	if first.Company != "深信服科技股份有限公司" || first.Position != "高级开发工程师" || first.Duration != "2019.07-至今" {
+		t.Errorf("unexpected work experience: %+v", first)
+	}
+	if second := analysis.WorkExperience[1]; second.Position != "后端工程师" || second.Duration != "2015.03-2019.06" {
+		t.Errorf("unexpected work experience: %+v", second)
+	}
+
+	expected := client.Education{Institution: "湖南大学", Degree: "本科", Field: "计算机科学与技术", GraduationYear: "2015"}
+	if len(analysis.Education) != 1 || analysis.Education[0] != expected {
+		t.Errorf("unexpected education: %+v", analysis.Education)
+	}
+}
+
+// recordingLLM 记录发送给模型的简历文本，name 不为空时作为模型识别出的姓名返回
+type recordingLLM struct {
+	*client.FakeResumeLLM
+	sent string
+	name string
+}
+
+func (r *recordingLLM) AnalyzeResume(ctx context.Context, job client.JobInfo, resumeText string) (*client.ResumeAnalysis, *client.Usage, error) {
+	r.sent = job.Prompt.User
+	analysis, usage, err := r.FakeResumeLLM.AnalyzeResume(ctx, job, resumeText)
+	if err == nil && r.name != "" {
+		analysis.PersonalInfo.Name = r.name
+	}
+	return analysis, usage, err
+}
+
+func TestAnalyzeRedactsPII(t *testing.T) {
+	resumePath := filepath.Join(t.TempDir(), "resume.txt")
+	resume := "姓名：张三\n电话：138-0013-8000 邮箱：zhangsan@example.com\n" +
+		"身份证：110101199003071234\n家庭住址：北京市朝阳区建国路88号\n5年 Golang 开发经验"
+	if err := os.WriteFile(resumePath, []byte(resume), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	llm := &recordingLLM{FakeResumeLLM: client.NewFakeResumeLLM()}
+	resumeAnalyzer, err := analyzer.NewResumeAnalyzerWithLLM(llm, &analyzer.AnalyzerConfig{
+		RedactCategories: []string{"name", "phone", "email", "id_card", "address"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, _, err := resumeAnalyzer.AnalyzeFile(context.Background(), "后端工程师", "Golang", "", resumePath)
+	if err != nil {
+		t.Fatalf("AnalyzeFile err: %v", err)
+	}
+
+	for _, pii := range []string{"张三", "13800138000", "0013-8000", "zhangsan@example.com", "110101199003071234", "建国路"} {
+		if strings.Contains(llm.sent, pii) {
+			t.Errorf("%q sent to the model", pii)
+		}
+	}
+	for _, placeholder := range []string{"[NAME_1]", "[PHONE_1]", "[EMAIL_1]", "[ID_CARD_1]", "[ADDRESS_1]"} {
+		if !strings.Contains(llm.sent, placeholder) {
+			t.Errorf("expected %s in prompt", placeholder)
+		}
+	}
+
+	info := analysis.PersonalInfo
+	if info.Name != "张三" || info.Phone != "13800138000" || info.Email != "zhangsan@example.com" {
+		t.Errorf("personal info not rehydrated: %+v", info)
+	}
+	if analysis.Metadata.Redacted["phone"] != 1 || analysis.Metadata.Redacted["address"] != 1 {
+		t.Errorf("unexpected redacted counts: %v", analysis.Metadata.Redacted)
+	}
+}
+
+func TestAnalyzeKeepsUnlabeledName(t *testing.T) {
+	resumePath := filepath.Join(t.TempDir(), "resume.txt")
+	resume := "后端开发\n李四 lisi@example.com\n5年 Golang 后端开发经验"
+	if err := os.WriteFile(resumePath, []byte(resume), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	llm := &recordingLLM{FakeResumeLLM: client.NewFakeResumeLLM(), name: "李四"}
+	resumeAnalyzer, err := analyzer.NewResumeAnalyzerWithLLM(llm, &analyzer.AnalyzerConfig{
+		RedactCategories: []string{"name", "email"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis, _, err := resumeAnalyzer.AnalyzeFile(context.Background(), "后端工程师", "Golang", "", resumePath)
+	if err != nil {
+		t.Fatalf("AnalyzeFile err: %v", err)
+	}
+
+	// 没有“姓名：”标签时猜测的姓名是“后端开发”，既不替换也不覆盖模型识别的姓名
+	if strings.Contains(llm.sent, "[NAME_1]") || !strings.Contains(llm.sent, "5年 Golang 后端开发经验") {
+		t.Errorf("unlabeled name should not be redacted: %q", llm.sent)
+	}
+	if analysis.PersonalInfo.Name != "李四" {
+		t.Errorf("model name overridden: %q", analysis.PersonalInfo.Name)
+	}
+}
@@ -2,10 +2,13 @@ package test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hr-api/pkg/bus"
 	"hr-api/pkg/keyvault"
+	"hr-api/pkg/setting"
 	"testing"
+	"time"
 )
 
 func TestBus(t *testing.T) {
@@ -41,3 +44,51 @@ func TestBus(t *testing.T) {
 		return
 	}
 }
+
+func TestMemoryQueueRetryAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	setting.QueueSetting.MaxDeliveryCount = 3
+	setting.QueueSetting.RetryDelay = 50 * time.Millisecond
+
+	q := bus.NewMemoryQueue("test")
+	q.Send(ctx, []byte("flaky"))
+	q.Send(ctx, []byte("poison"))
+
+	attempts := map[string]int{}
+	handler := func(m *bus.Message) error {
+		attempts[string(m.Body)]++
+		if string(m.Body) == "poison" {
+			return fmt.Errorf("%w: bad payload", bus.ErrPoison)
+		}
+		return errors.New("temporary failure")
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := q.Receive(ctx, handler); err != nil {
+			t.Fatalf("Receive err: %v", err)
+		}
+	}
+
+	// 失败的消息等待 RetryDelay 后才重新入队，不会被立即再次处理
+	early, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := q.Receive(early, handler); err != context.DeadlineExceeded {
+		t.Fatalf("expected no message before the retry delay, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := q.Receive(ctx, handler); err != nil {
+			t.Fatalf("Receive err: %v", err)
+		}
+	}
+
+	if attempts["poison"] != 1 {
+		t.Errorf("poison message should not be retried, got %d attempts", attempts["poison"])
+	}
+	if attempts["flaky"] != 3 {
+		t.Errorf("flaky message should be delivered MaxDeliveryCount times, got %d", attempts["flaky"])
+	}
+	if dead := q.DeadLetters(); len(dead) != 2 {
+		t.Errorf("expected 2 dead-lettered messages, got %d", len(dead))
+	}
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"hr-api/pkg/client"
+)
+
+// fakeChatServer 按顺序返回预设内容的 OpenAI 兼容接口，并记录收到的请求体
+type fakeChatServer struct {
+	mu       sync.Mutex
+	replies  []string
+	requests []string
+}
+
+func startFakeChatServer(t *testing.T, replies ...string) (*fakeChatServer, *client.OpenAICompatibleClient) {
+	s := &fakeChatServer{replies: replies}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, string(body))
+		content := "{}"
+		if len(s.replies) > 0 {
+			content, s.replies = s.replies[0], s.replies[1:]
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":      "chatcmpl-test",
+			"object":  "chat.completion",
+			"created": 0,
+			"model":   "test-model",
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"finish_reason": "stop",
+				"message":       map[string]interface{}{"role": "assistant", "content": content},
+			}},
+			"usage": map[string]interface{}{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.NewOpenAICompatibleClient(server.URL+"/v1", "test-model", "test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, c
+}
+
+// modelOutput 符合 schema 的模型输出，edit 修改其中的字段后序列化
+func modelOutput(t *testing.T, edit func(doc map[string]interface{})) string {
+	analysis := client.ResumeAnalysis{
+		PersonalInfo:   client.PersonalInfo{Name: "张三", Links: []string{}},
+		Summary:        "5年 Golang 后端开发经验",
+		WorkExperience: []client.WorkExperience{},
+		Education:      []client.Education{},
+		Skills: client.Skills{
+			Technical:      []string{"golang"},
+			Soft:           []string{},
+			Languages:      []client.Language{},
+			Certifications: []string{},
+		},
+		Analysis: client.JobAnalysis{
+			Strengths:       []string{"golang"},
+			Weaknesses:      []string{},
+			Recommendations: []string{},
+			MatchScore:      70,
+		},
+		Metadata: client.AnalysisMetadata{AnalysisDate: "2024-01-01", WordCount: 100, EstimatedYOE: 5},
+	}
+	data, _ := json.Marshal(analysis)
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if edit != nil {
+		edit(doc)
+	}
+	data, _ = json.Marshal(doc)
+	return string(data)
+}
+
+func analyzeWith(t *testing.T, c *client.OpenAICompatibleClient) (*client.ResumeAnalysis, *client.Usage) {
+	job := client.JobInfo{Title: "后端工程师", Prompt: &client.Prompt{System: "system", User: "resume"}}
+	analysis, usage, err := c.AnalyzeResume(context.Background(), job, "resume")
+	if err != nil {
+		t.Fatalf("AnalyzeResume err: %v", err)
+	}
+	return analysis, usage
+}
+
+func TestChatClientAcceptsEmptyName(t *testing.T) {
+	server, c := startFakeChatServer(t, modelOutput(t, func(doc map[string]interface{}) {
+		doc["personal_info"].(map[string]interface{})["name"] = ""
+	}))
+
+	analysis, _ := analyzeWith(t, c)
+	if analysis.PersonalInfo.Name != "" || analysis.Analysis.MatchScore != 70 {
+		t.Errorf("unexpected analysis: %+v", analysis)
+	}
+	if len(server.requests) != 1 {
+		t.Errorf("an empty name must not trigger a repair, got %d requests", len(server.requests))
+	}
+}
+
+func TestChatClientRepairsInvalidFields(t *testing.T) {
+	// 字段类型错误由 schema 检查发现，修复后与原结果合并
+	server, c := startFakeChatServer(t,
+		modelOutput(t, func(doc map[string]interface{}) {
+			doc["metadata"].(map[string]interface{})["estimated_yoe"] = "5年"
+		}),
+		`{"metadata": {"estimated_yoe": 5}}`,
+	)
+	analysis, usage := analyzeWith(t, c)
+	if analysis.Metadata.EstimatedYOE != 5 || analysis.Metadata.WordCount != 100 || analysis.Summary == "" {
+		t.Errorf("repaired fields not merged: %+v", analysis)
+	}
+	if len(server.requests) != 2 || !strings.Contains(server.requests[1], "metadata.estimated_yoe") {
+		t.Errorf("expected one repair request naming the invalid field, got %v", server.requests)
+	}
+	if usage.Calls != 2 || usage.TotalTokens() != 30 {
+		t.Errorf("repair tokens not counted: %+v", usage)
+	}
+
+	// 超出范围由业务约束发现
+	server, c = startFakeChatServer(t,
+		modelOutput(t, func(doc map[string]interface{}) {
+			doc["analysis"].(map[string]interface{})["match_score"] = 120
+		}),
+		"```json\n{\"analysis\": {\"match_score\": 85}}\n```",
+	)
+	analysis, _ = analyzeWith(t, c)
+	if analysis.Analysis.MatchScore != 85 || len(analysis.Analysis.Strengths) != 1 {
+		t.Errorf("repaired fields not merged: %+v", analysis.Analysis)
+	}
+	if len(server.requests) != 2 || !strings.Contains(server.requests[1], "analysis.match_score") {
+		t.Errorf("expected one repair request naming the invalid field, got %v", server.requests)
+	}
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"hr-api/pkg/dedupe"
+)
+
+func TestMinHashNearDuplicate(t *testing.T) {
+	resume := "张三 五年 Golang 后端开发经验，熟悉 MySQL、Redis 与 Kafka，负责订单系统的设计与性能优化。\n" +
+		"2019.07-至今 深信服科技股份有限公司 高级开发工程师，主导交易链路微服务化改造，接口延迟降低百分之四十。\n" +
+		"2015.03-2019.06 长沙某某网络有限公司 后端工程师，负责会员与积分系统，日均请求量过亿。\n" +
+		"2011.09-2015.06 湖南大学 计算机科学与技术 本科，获得国家奖学金，ACM 区域赛银牌。"
+	// 重新排版并补充了一项技能的同一份简历
+	edited := strings.ReplaceAll(resume, "，", "\n") + "\n熟悉 Kubernetes"
+	other := "李四 三年 Java 开发经验，熟悉 Spring Cloud 与 Elasticsearch，参与过支付清结算系统建设，2020 年加入字节跳动。"
+
+	sig, editedSig, otherSig := dedupe.Signature(resume), dedupe.Signature(edited), dedupe.Signature(other)
+	if s := dedupe.Similarity(sig, editedSig); s < dedupe.SimilarThreshold {
+		t.Errorf("expected edited resume to be similar, got %.2f", s)
+	}
+	if s := dedupe.Similarity(sig, otherSig); s >= dedupe.SimilarThreshold {
+		t.Errorf("expected different resumes to be dissimilar, got %.2f", s)
+	}
+
+	shared := false
+	editedBands := dedupe.Bands(editedSig)
+	for i, band := range dedupe.Bands(sig) {
+		shared = shared || band == editedBands[i]
+	}
+	if !shared {
+		t.Error("expected near duplicates to share an LSH band")
+	}
+
+	decoded, err := dedupe.DecodeSignature(dedupe.EncodeSignature(sig))
+	if err != nil || dedupe.Similarity(sig, decoded) != 1 {
+		t.Errorf("signature round trip failed: %v", err)
+	}
+}
+
+func TestNormalizeIdentity(t *testing.T) {
+	if got := dedupe.NormalizePhone("+86 138-0013-8000"); got != "13800138000" {
+		t.Errorf("unexpected phone %q", got)
+	}
+	if got := dedupe.NormalizePhone("0731-88888888"); got != "" {
+		t.Errorf("landline should not be an identity, got %q", got)
+	}
+	if got := dedupe.NormalizeEmail(" ZhangSan@Example.com "); got != "zhangsan@example.com" {
+		t.Errorf("unexpected email %q", got)
+	}
+}
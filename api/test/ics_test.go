@@ -0,0 +1,68 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"hr-api/pkg/ics"
+)
+
+func TestCalendarInvite(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	event := ics.Event{
+		UID:         "interview-1@example.com",
+		Sequence:    2,
+		Start:       time.Date(2026, 3, 2, 14, 0, 0, 0, loc),
+		End:         time.Date(2026, 3, 2, 15, 0, 0, 0, loc),
+		Summary:     "面试：张三 - 后端工程师 第1轮",
+		Description: "会议链接：https://teams.example.com/l/meetup\n请提前 5 分钟入会; 带上作品集, 谢谢",
+		Location:    "3F 会议室 A",
+		Organizer:   ics.Person{Name: "hr", Email: "hr@example.com"},
+		Attendees: []ics.Person{
+			{Name: "李四", Email: "lisi@example.com"},
+			{Name: "Wang, Wu", Email: "wangwu@example.com"},
+			{Name: "no email"},
+		},
+	}
+	data := string(ics.Calendar("", event, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	if !strings.HasSuffix(data, "END:VCALENDAR\r\n") || strings.Contains(strings.ReplaceAll(data, "\r\n", ""), "\n") {
+		t.Fatal("expected CRLF line endings")
+	}
+	// 还原折行后再检查内容
+	unfolded := strings.ReplaceAll(data, "\r\n ", "")
+	for _, want := range []string{
+		"METHOD:REQUEST",
+		"SEQUENCE:2",
+		"DTSTART:20260302T060000Z",
+		"DTEND:20260302T070000Z",
+		"DTSTAMP:20260301T000000Z",
+		`DESCRIPTION:会议链接：https://teams.example.com/l/meetup\n请提前 5 分钟入会\; 带上作品集\, 谢谢`,
+		"STATUS:CONFIRMED",
+		"ORGANIZER;CN=hr:mailto:hr@example.com",
+		`ATTENDEE;CN="Wang, Wu";ROLE=REQ-PARTICIPANT`,
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("expected %q in invite:\n%s", want, data)
+		}
+	}
+	if strings.Count(unfolded, "ATTENDEE") != 2 {
+		t.Error("attendees without email should be skipped")
+	}
+
+	for _, line := range strings.Split(data, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line folded inside a multi-byte character: %q", line)
+		}
+	}
+
+	cancelled := string(ics.Calendar(ics.MethodCancel, ics.Event{UID: "x", Status: ics.StatusCancelled}, time.Now()))
+	if !strings.Contains(cancelled, "METHOD:CANCEL") || !strings.Contains(cancelled, "STATUS:CANCELLED") {
+		t.Error("expected cancel invite")
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"slices"
+	"testing"
+
+	"hr-api/models"
+	"hr-api/service/job_service"
+)
+
+func TestPlanClose(t *testing.T) {
+	applications := []*models.Application{
+		{ID: 1, Status: models.ApplicationActive, Stage: "screening"},
+		{ID: 2, Status: models.ApplicationActive, Stage: "offer"},
+		{ID: 3, Status: models.ApplicationActive, Stage: "hired"},
+		{ID: 4, Status: models.ApplicationActive, Stage: "rejected"},
+		{ID: 5, Status: models.ApplicationWithdrawn, Stage: "interview_1"},
+		{ID: 6, Status: models.ApplicationActive, Stage: "interview_2"},
+	}
+	offers := []*models.Offer{
+		{ID: 11, ApplicationId: 2, Status: models.OfferSent},
+		{ID: 12, ApplicationId: 6, Status: models.OfferPendingApproval},
+		{ID: 13, ApplicationId: 3, Status: models.OfferSent},
+		// 上次关闭时应聘记录已淘汰但 offer 未撤回
+		{ID: 14, ApplicationId: 4, Status: models.OfferSent},
+		{ID: 15, ApplicationId: 1, Status: models.OfferDraft},
+		{ID: 16, ApplicationId: 2, Status: models.OfferDeclined},
+	}
+
+	actions := job_service.PlanClose(applications, offers)
+
+	var rejected []int
+	for _, application := range actions.Reject {
+		rejected = append(rejected, application.ID)
+	}
+	if !slices.Equal(rejected, []int{1, 2, 6}) {
+		t.Errorf("unexpected rejected applications %v", rejected)
+	}
+
+	var withdrawn []int
+	for _, offer := range actions.Withdraw {
+		withdrawn = append(withdrawn, offer.ID)
+	}
+	if !slices.Equal(withdrawn, []int{11, 12, 14}) {
+		t.Errorf("unexpected withdrawn offers %v", withdrawn)
+	}
+
+	// 全部处理完后再次关闭不会重复处理
+	for _, application := range actions.Reject {
+		application.Stage = "rejected"
+	}
+	for _, offer := range actions.Withdraw {
+		offer.Status = models.OfferWithdrawn
+	}
+	again := job_service.PlanClose(applications, offers)
+	if len(again.Reject) != 0 || len(again.Withdraw) != 0 {
+		t.Errorf("expected nothing left to close, got %+v", again)
+	}
+}
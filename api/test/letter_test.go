@@ -0,0 +1,154 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hr-api/pkg/letter"
+	"hr-api/pkg/setting"
+)
+
+var letterFields = map[string]string{
+	"candidate_name": "张三 <Tom>",
+	"job_name":       "后端工程师",
+	"level":          "P6",
+	"salary":         "30000",
+	"start_date":     "2026-11-01",
+	"expire_date":    "2026-10-24 18:00",
+	"today":          "2026-10-17",
+}
+
+func setupLetterDir(t *testing.T) {
+	setting.AppSetting.RuntimeRootPath = t.TempDir()
+	setting.AppSetting.OfferTemplateDir = "offer_templates"
+	t.Cleanup(func() { setting.AppSetting.RuntimeRootPath = "" })
+
+	if err := os.MkdirAll(letter.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOfferLetterHTML(t *testing.T) {
+	setupLetterDir(t)
+
+	builtin, err := letter.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !builtin.Builtin {
+		t.Fatal("expected the builtin default template")
+	}
+	doc, err := builtin.Render("offer-1", letterFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Filename != "offer-1.html" || doc.ContentType != letter.ContentTypeHTML {
+		t.Errorf("unexpected letter: %s %s", doc.Filename, doc.ContentType)
+	}
+	if !strings.Contains(string(doc.Body), "张三 &lt;Tom&gt;") || strings.Contains(string(doc.Body), "{{") {
+		t.Errorf("fields not filled or not escaped:\n%s", doc.Body)
+	}
+
+	content := "<p>{{ candidate_name }} {{bonus}}</p>"
+	if err := os.WriteFile(filepath.Join(letter.Dir(), "intern.html"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	intern, err := letter.Load("intern")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := intern.Render("offer-1", letterFields); err == nil || !strings.Contains(err.Error(), "bonus") {
+		t.Errorf("expected unknown placeholder error, got %v", err)
+	}
+
+	if _, err := letter.Load("../intern"); err == nil {
+		t.Error("expected invalid name error")
+	}
+	if _, err := letter.Load("missing"); err == nil {
+		t.Error("expected missing template error")
+	}
+
+	templates, err := letter.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0].Name != "default" || templates[1].Name != "intern" {
+		t.Errorf("unexpected templates: %+v", templates)
+	}
+}
+
+func TestOfferLetterDOCX(t *testing.T) {
+	setupLetterDir(t)
+
+	// Word 把 {{candidate_name}} 拆成了多个 run
+	document := `<w:document><w:body><w:p>` +
+		`<w:r><w:t>{{cand</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>idate_name}}</w:t></w:r>` +
+		`<w:r><w:t>, {{job_name}}</w:t></w:r>` +
+		`</w:p></w:body></w:document>`
+	footer := `<w:ftr><w:p><w:r><w:t>{{today}}</w:t></w:r></w:p></w:ftr>`
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml":   document,
+		"word/footer1.xml":    footer,
+	} {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(letter.Dir(), "default.docx"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := letter.Load("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Format != "docx" || tmpl.Builtin {
+		t.Fatalf("expected the docx template to take priority: %+v", tmpl)
+	}
+	doc, err := tmpl.Render("offer-2", letterFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Filename != "offer-2.docx" || doc.ContentType != letter.ContentTypeDOCX {
+		t.Errorf("unexpected letter: %s %s", doc.Filename, doc.ContentType)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(doc.Body), int64(len(doc.Body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(data)
+	}
+	if !strings.Contains(parts["word/document.xml"], "张三 &lt;Tom&gt;") ||
+		!strings.Contains(parts["word/document.xml"], "后端工程师") {
+		t.Errorf("document not filled: %s", parts["word/document.xml"])
+	}
+	if !strings.Contains(parts["word/footer1.xml"], "2026-10-17") {
+		t.Errorf("footer not filled: %s", parts["word/footer1.xml"])
+	}
+	if parts["[Content_Types].xml"] != `<Types/>` {
+		t.Errorf("other parts must be copied as is: %s", parts["[Content_Types].xml"])
+	}
+}
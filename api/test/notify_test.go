@@ -0,0 +1,237 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"hr-api/pkg/notify"
+	"hr-api/pkg/setting"
+)
+
+// fakeSMTP 只实现发送所需命令的本地 SMTP 服务器，拒绝 reject 开头的收件人
+type fakeSMTP struct {
+	addr string
+	data chan string
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeSMTP{addr: ln.Addr().String(), data: make(chan string, 4)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "RCPT TO:<REJECT"):
+			reply("550 5.1.1 no such user")
+		case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"), strings.HasPrefix(cmd, "RSET"):
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			s.data <- data.String()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestNotifyTemplates(t *testing.T) {
+	setting.AppSetting.RuntimeRootPath = t.TempDir()
+	setting.MailSetting.TemplateDir = "notify_templates"
+	defer func() { setting.AppSetting.RuntimeRootPath = "" }()
+
+	tmpl, err := notify.Load(notify.EventStageChanged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tmpl.Builtin {
+		t.Error("expected the builtin template")
+	}
+	msg, err := tmpl.Render(map[string]string{
+		"recipient":      "hr",
+		"candidate_name": "<b>张三</b>",
+		"job_name":       "后端工程师",
+		"from_stage":     "screening",
+		"to_stage":       "rejected",
+		"reason":         "经验不足",
+		"operator":       "lisi",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(msg.Subject, "\n") || !strings.Contains(msg.Subject, "rejected") {
+		t.Errorf("unexpected subject: %q", msg.Subject)
+	}
+	if !strings.Contains(msg.Text, "<b>张三</b>") || !strings.Contains(msg.Text, "原因：经验不足") {
+		t.Errorf("unexpected text: %s", msg.Text)
+	}
+	if !strings.Contains(msg.HTML, "&lt;b&gt;张三&lt;/b&gt;") {
+		t.Errorf("html must be escaped: %s", msg.HTML)
+	}
+
+	if _, err := tmpl.Render(map[string]string{"recipient": "hr"}); err == nil {
+		t.Error("expected missing field error")
+	}
+	if _, err := notify.Load("unknown"); err == nil {
+		t.Error("expected unknown event error")
+	}
+
+	if err := os.MkdirAll(notify.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	custom := `{{define "subject"}}New resume {{.filename}}{{end}}{{define "text"}}{{.job_name}}{{end}}`
+	path := filepath.Join(notify.Dir(), notify.EventResumeUploaded+".tmpl")
+	if err := os.WriteFile(path, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := notify.Load(notify.EventResumeUploaded); err == nil || !strings.Contains(err.Error(), "html") {
+		t.Errorf("expected missing html block error, got %v", err)
+	}
+	custom += `{{define "html"}}<p>{{.job_name}}</p>{{end}}`
+	if err := os.WriteFile(path, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	override, err := notify.Load(notify.EventResumeUploaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err = override.Render(map[string]string{"filename": "cv.pdf", "job_name": "QA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if override.Builtin || msg.Subject != "New resume cv.pdf" || msg.HTML != "<p>QA</p>" {
+		t.Errorf("unexpected override: %+v", msg)
+	}
+}
+
+func TestNotifySMTP(t *testing.T) {
+	server := startFakeSMTP(t)
+	host, port, _ := net.SplitHostPort(server.addr)
+	transport := &notify.SMTP{Host: host, TLS: notify.TLSNone}
+	transport.Port, _ = strconv.Atoi(port)
+
+	from := mail.Address{Name: "招聘系统", Address: "hr@example.com"}
+	msg := &notify.Message{
+		To:      []string{"lisi@example.com"},
+		Subject: "面试邀请：张三",
+		Text:    "您被安排为面试官。",
+		HTML:    "<p>您被安排为面试官。</p>",
+		Attachments: []notify.Attachment{
+			{Filename: "interview-1.ics", ContentType: "text/calendar; method=REQUEST; charset=utf-8", Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")},
+		},
+	}
+	if err := transport.Send(context.Background(), from, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	received, err := mail.ReadMessage(strings.NewReader(<-server.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(received.Header.Get("Subject"))
+	if subject != msg.Subject {
+		t.Errorf("unexpected subject: %s", subject)
+	}
+	sender, err := received.Header.AddressList("From")
+	if err != nil || sender[0].Name != from.Name {
+		t.Errorf("unexpected from: %v %v", sender, err)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(received.Header.Get("Content-Type"))
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("unexpected content type: %s", mediaType)
+	}
+	parts := multipart.NewReader(received.Body, params["boundary"])
+	body, err := parts.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, bodyParams, _ := mime.ParseMediaType(body.Header.Get("Content-Type"))
+	alternative := multipart.NewReader(body, bodyParams["boundary"])
+	var contents []string
+	for {
+		p, err := alternative.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		// multipart.Reader 会自动解码 quoted-printable
+		data, _ := io.ReadAll(p)
+		contents = append(contents, string(data))
+	}
+	if len(contents) != 2 || contents[0] != msg.Text || contents[1] != msg.HTML {
+		t.Errorf("unexpected alternative parts: %q", contents)
+	}
+	attachment, err := parts.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.FileName() != "interview-1.ics" {
+		t.Errorf("unexpected attachment: %s", attachment.FileName())
+	}
+
+	msg.To = []string{"reject@example.com"}
+	err = transport.Send(context.Background(), from, msg)
+	if err == nil || !notify.IsPermanent(err) {
+		t.Errorf("expected permanent error, got %v", err)
+	}
+
+	closed := &notify.SMTP{Host: "127.0.0.1", Port: 1, TLS: notify.TLSNone}
+	if err := closed.Send(context.Background(), from, msg); err == nil || notify.IsPermanent(err) {
+		t.Errorf("expected temporary error, got %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"hr-api/pkg/parser"
+)
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// buildTestDoc 构造只含 WordDocument 与 1Table 两个流的最小 Word 97 文档
+func buildTestDoc(text string) []byte {
+	le := binary.LittleEndian
+	const sectorSize = 512
+	units := utf16.Encode([]rune(text))
+
+	word := make([]byte, 4096)
+	le.PutUint16(word[0:], 0xA5EC)
+	le.PutUint16(word[0x0A:], 0x0200) // 使用 1Table
+	le.PutUint16(word[32:], 14)       // csw
+	le.PutUint16(word[62:], 22)       // cslw
+	le.PutUint32(word[64+3*4:], uint32(len(units)))
+	le.PutUint16(word[152:], 93) // cbRgFcLcb
+	for i, u := range units {
+		le.PutUint16(word[1024+i*2:], u)
+	}
+
+	table := make([]byte, 4096)
+	clx := []byte{0x02}
+	clx = le.AppendUint32(clx, 16)
+	clx = le.AppendUint32(clx, 0)
+	clx = le.AppendUint32(clx, uint32(len(units)))
+	clx = append(clx, 0, 0)
+	clx = le.AppendUint32(clx, 1024)
+	clx = append(clx, 0, 0)
+	copy(table, clx)
+	le.PutUint32(word[154+33*8:], 0)
+	le.PutUint32(word[154+33*8+4:], uint32(len(clx)))
+
+	header := make([]byte, sectorSize)
+	copy(header, []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1})
+	le.PutUint16(header[0x1A:], 3)
+	le.PutUint16(header[0x1C:], 0xFFFE)
+	le.PutUint16(header[0x1E:], 9)
+	le.PutUint16(header[0x20:], 6)
+	le.PutUint32(header[0x2C:], 1)
+	le.PutUint32(header[0x30:], 1) // 目录在扇区 1
+	le.PutUint32(header[0x38:], 4096)
+	le.PutUint32(header[0x3C:], 0xFFFFFFFE)
+	le.PutUint32(header[0x44:], 0xFFFFFFFE)
+	for i := 0; i < 109; i++ {
+		le.PutUint32(header[0x4C+i*4:], 0xFFFFFFFF)
+	}
+	le.PutUint32(header[0x4C:], 0) // FAT 在扇区 0
+
+	fat := make([]byte, sectorSize)
+	for i := 0; i < sectorSize/4; i++ {
+		le.PutUint32(fat[i*4:], 0xFFFFFFFF)
+	}
+	le.PutUint32(fat[0:], 0xFFFFFFFD)
+	le.PutUint32(fat[4:], 0xFFFFFFFE)
+	// WordDocument 占扇区 2-9，1Table 占扇区 10-17
+	for s := 2; s <= 17; s++ {
+		next := uint32(s + 1)
+		if s == 9 || s == 17 {
+			next = 0xFFFFFFFE
+		}
+		le.PutUint32(fat[s*4:], next)
+	}
+
+	dir := make([]byte, sectorSize)
+	entry := func(i int, name string, kind byte, start uint32, size uint64) {
+		e := dir[i*128:]
+		u := utf16.Encode([]rune(name))
+		for j, c := range u {
+			le.PutUint16(e[j*2:], c)
+		}
+		le.PutUint16(e[0x40:], uint16((len(u)+1)*2))
+		e[0x42] = kind
+		le.PutUint32(e[0x74:], start)
+		le.PutUint64(e[0x78:], size)
+	}
+	entry(0, "Root Entry", 5, 0xFFFFFFFE, 0)
+	entry(1, "WordDocument", 2, 2, uint64(len(word)))
+	entry(2, "1Table", 2, 10, uint64(len(table)))
+
+	var out []byte
+	for _, part := range [][]byte{header, fat, dir, word, table} {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func TestParseAdditionalFormats(t *testing.T) {
+	dir := t.TempDir()
+	m := parser.NewUnifiedResumeParser()
+
+	gbkHTML := []byte("<html><head><meta charset=\"gbk\"><style>p{}</style></head><body><h1>\xd5\xc5\xc8\xfd</h1>" +
+		"<table><tr><td><p>Go</p></td><td>5\xc4\xea</td></tr></table><ul><li>Kubernetes</li></ul></body></html>")
+	if err := os.WriteFile(filepath.Join(dir, "resume.html"), gbkHTML, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mht := "MIME-Version: 1.0\r\nContent-Type: multipart/related; boundary=\"b1\"\r\n\r\n" +
+		"--b1\r\nContent-Type: text/html; charset=\"utf-8\"\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n" +
+		"<p>=E5=BC=A0=E4=B8=89</p><p>Java</p>\r\n--b1--\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "resume.mht"), []byte(mht), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rtf := `{\rtf1\ansi\ansicpg1252{\fonttbl{\f0\fnil\fcharset134 SimSun;}{\f1 Arial;}}{\*\generator Test;}` +
+		`\f0 \'d5\'c5\'c8\'fd\par\f1 Go \u24037?\u31243?\'e9\par}`
+	if err := os.WriteFile(filepath.Join(dir, "resume.rtf"), []byte(rtf), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	writeZip(t, filepath.Join(dir, "resume.odt"), map[string]string{
+		"content.xml": `<office:document-content xmlns:office="o" xmlns:text="t" xmlns:table="tb"><office:body><office:text>` +
+			`<text:h>张三</text:h><text:p>Go<text:s text:c="2"/>工程师</text:p>` +
+			`<table:table><table:table-row><table:table-cell><text:p>2019</text:p></table:table-cell>` +
+			`<table:table-cell><text:p>字节跳动</text:p></table:table-cell></table:table-row></table:table>` +
+			`</office:text></office:body></office:document-content>`,
+	})
+
+	slide := func(text string) string {
+		return `<p:sld xmlns:p="p" xmlns:a="a"><p:cSld><p:spTree><p:sp><p:txBody><a:p><a:r><a:t>` + text +
+			`</a:t></a:r></a:p></p:txBody></p:sp></p:spTree></p:cSld></p:sld>`
+	}
+	writeZip(t, filepath.Join(dir, "resume.pptx"), map[string]string{
+		"ppt/slides/slide2.xml":  slide("工作经历"),
+		"ppt/slides/slide10.xml": slide("项目经历"),
+		"ppt/slides/slide1.xml":  slide("张三"),
+	})
+
+	doc := buildTestDoc("张三\r电话：13800138000\r\x13 HYPERLINK \"mailto:a@b.com\" \x14a@b.com\x15\r")
+	if err := os.WriteFile(filepath.Join(dir, "resume.doc"), doc, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"resume.html": "张三\nGo\t5年\n- Kubernetes",
+		"resume.mht":  "张三\nJava",
+		"resume.rtf":  "张三\nGo 工程é",
+		"resume.odt":  "张三\nGo  工程师\n2019\t字节跳动",
+		"resume.pptx": "张三\n\n工作经历\n\n项目经历",
+		"resume.doc":  "张三\n电话：13800138000\na@b.com",
+	}
+	for name, expected := range cases {
+		if !m.SupportsFormat(filepath.Ext(name)) {
+			t.Errorf("%s: format not registered", name)
+		}
+		text, err := m.Parse(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if strings.TrimSpace(text) != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, text)
+		}
+	}
+}
+
+func TestParseDocxStructure(t *testing.T) {
+	const ns = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+	para := func(ppr, text string) string {
+		return `<w:p><w:pPr>` + ppr + `</w:pPr><w:r><w:t>` + text + `</w:t></w:r></w:p>`
+	}
+	cell := func(text string) string { return `<w:tc>` + para("", text) + `</w:tc>` }
+
+	path := filepath.Join(t.TempDir(), "resume.docx")
+	writeZip(t, path, map[string]string{
+		"word/document.xml": `<w:document ` + ns + `><w:body>` +
+			para(`<w:pStyle w:val="1"/>`, "张三") +
+			`<w:tbl><w:tr>` + cell("2019-2023") + cell("字节跳动") + `</w:tr>` +
+			`<w:tr>` + cell("职位") + cell("后端工程师") + `</w:tr></w:tbl>` +
+			para(`<w:pStyle w:val="2"/>`, "技能") +
+			para(`<w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr>`, "Go") +
+			para(`<w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr>`, "gRPC") +
+			para(`<w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr>`, "第一") +
+			para(`<w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr>`, "第二") +
+			`<w:p><w:r><w:t xml:space="preserve">电话：</w:t></w:r><w:r><w:tab/><w:t>13800138000</w:t></w:r>` +
+			`<w:del><w:r><w:delText>旧号码</w:delText></w:r></w:del></w:p>` +
+			`</w:body></w:document>`,
+		"word/styles.xml": `<w:styles ` + ns + `>` +
+			`<w:style w:styleId="1"><w:name w:val="heading 1"/></w:style>` +
+			`<w:style w:styleId="2"><w:name w:val="标题 2"/><w:basedOn w:val="1"/></w:style></w:styles>`,
+		"word/numbering.xml": `<w:numbering ` + ns + `>` +
+			`<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/></w:lvl><w:lvl w:ilvl="1"><w:numFmt w:val="bullet"/></w:lvl></w:abstractNum>` +
+			`<w:abstractNum w:abstractNumId="1"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>` +
+			`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num><w:num w:numId="2"><w:abstractNumId w:val="1"/></w:num></w:numbering>`,
+		"word/header2.xml": `<w:hdr ` + ns + `>` + para("", "智联招聘") + `</w:hdr>`,
+		"word/header1.xml": `<w:hdr ` + ns + `>` + para("", "个人简历") + `</w:hdr>`,
+		"word/footer1.xml": `<w:ftr ` + ns + `>` + para("", "个人简历") + `</w:ftr>`,
+	})
+
+	text, err := parser.NewUnifiedResumeParser().Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "# 张三\n## 技能\n- Go\n  - gRPC\n1. 第一\n2. 第二\n电话：\t13800138000\n\n" +
+		"| 2019-2023 | 字节跳动 |\n| 职位 | 后端工程师 |\n\n个人简历\n\n智联招聘"
+	if text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+}
+
+func TestParseReaderSniffsContent(t *testing.T) {
+	p := parser.NewUnifiedResumeParser()
+	ctx := context.Background()
+
+	// 扩展名与内容不符时以内容为准
+	html := "<html><body><p>张三</p><p>Go 工程师</p></body></html>"
+	if got := parser.DetectFormat([]byte(html), "resume.pdf", "application/pdf"); got != ".html" {
+		t.Errorf("expected .html, got %q", got)
+	}
+	text, err := p.ParseReader(ctx, strings.NewReader(html), "resume.pdf", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "张三\nGo 工程师" {
+		t.Errorf("unexpected html text %q", text)
+	}
+
+	// 无扩展名的 zip 根据内部文件识别为 docx
+	path := filepath.Join(t.TempDir(), "blob")
+	writeZip(t, path, map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml": `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+			`<w:p><w:r><w:t>李四</w:t></w:r></w:p></w:body></w:document>`,
+	})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parser.DetectFormat(data, "blob", "application/octet-stream"); got != ".docx" {
+		t.Errorf("expected .docx, got %q", got)
+	}
+	text, err = p.ParseReader(ctx, bytes.NewReader(data), "blob", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "李四" {
+		t.Errorf("unexpected docx text %q", text)
+	}
+
+	p.SetMaxSize(8)
+	if _, err := p.ParseReader(ctx, strings.NewReader(html), "resume.html", ""); !errors.Is(err, parser.ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+// TestParseMalformedDoc 损坏的扇区大小不能导致 panic，短流阈值调到最大以读取短扇区
+func TestParseMalformedDoc(t *testing.T) {
+	p := parser.NewUnifiedResumeParser()
+	cases := map[string][2]uint16{
+		"mini sector shift 63": {9, 63},
+		"mini sector shift 64": {9, 64},
+		"mini sector shift 7":  {9, 7},
+		"sector shift 63":      {63, 6},
+		"sector shift 0":       {0, 6},
+	}
+	for name, shifts := range cases {
+		doc := buildTestDoc("张三")
+		binary.LittleEndian.PutUint16(doc[0x1E:], shifts[0])
+		binary.LittleEndian.PutUint16(doc[0x20:], shifts[1])
+		binary.LittleEndian.PutUint32(doc[0x38:], 0xFFFFFFFF)
+		if _, err := p.ParseReader(context.Background(), bytes.NewReader(doc), "resume.doc", ""); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"hr-api/pkg/client"
 	"hr-api/pkg/parser"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -39,7 +42,7 @@ func TestParseDocx(t *testing.T) {
 	jobRequirements := "工作职责:\n1、 负责开发操作医疗设备的软件；\n2、 负责二维或者三维图像的渲染，以及相关的交互；\n3、 根据功能要求完成相关的算法；\n4、 配合设备输入的图像进行功能开发；\n5、 根据公司技术文档规范编写相应的技术文档。"
 	jobDescription := "任职资格:\n1、 熟悉C++以及基本的数据结构；\n2、 熟悉基本的设计模式，并且能够运用；\n3、 数学基础较好的优先；\n4、 熟悉嵌入式Linux操作系统，有医疗产品研发经验者优先；\n5、 对医疗行业了解，有HIS，PACS系统开发的优先；\n6、 有较强的责任心，良好团队协作能力，沟通能力，谦虚踏实。"
 
-	analysis, err := resumeAnalyzer.AnalyzeFile(nil, jobTitle, jobRequirements, jobDescription, "/Users/captain/develop/verycloud/microsoft/hr-jianli/呼和浩特/杨先生_34岁_智联简历_00052-金万维.docx")
+	analysis, _, err := resumeAnalyzer.AnalyzeFile(nil, jobTitle, jobRequirements, jobDescription, "/Users/captain/develop/verycloud/microsoft/hr-jianli/呼和浩特/杨先生_34岁_智联简历_00052-金万维.docx")
 	if err != nil {
 		log.Fatalf("分析失败: %v", err)
 	}
@@ -83,3 +86,69 @@ func printAnalysisSummary(analysis *client.ResumeAnalysis) {
 		fmt.Printf("  • %s\n", rec)
 	}
 }
+
+func TestParsePDFTextLayer(t *testing.T) {
+	// Identity-H 字体：0001-0004 通过 ToUnicode 映射为“张三简历”，0005 通过 bfrange 数组映射为“，”
+	toUnicode := "/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"2 beginbfchar <0001> <5F20> <0002> <4E09> endbfchar\n" +
+		"2 beginbfrange <0003> <0004> [<7B80> <5386>] <0005> <0005> <FF0C> endbfrange\n" +
+		"endcmap CMapName currentdict /CMap defineresource pop end end"
+	// 第二行先绘制，验证按位置排序；TJ 中的大字距应当补出空格
+	content := "BT /F2 10 Tf 72 700 Td [(Senior) -300 (Go) -300 (Engineer)] TJ ET\n" +
+		"BT /F1 12 Tf 72 720 Td <00010002> Tj 24 0 Td <000300040005> Tj ET"
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 5 0 R /F2 8 0 R >> >> /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /Encoding /Identity-H /DescendantFonts [6 0 R] /ToUnicode 7 0 R >>",
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /SimSun /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /DW 1000 >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(toUnicode), toUnicode),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	var pdf strings.Builder
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	path := filepath.Join(t.TempDir(), "resume.pdf")
+	if err := os.WriteFile(path, []byte(pdf.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := parser.NewUnifiedResumeParser().Parse(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "张三简历，\nSenior Go Engineer"
+	if text != expected {
+		t.Errorf("expected %q, got %q", expected, text)
+	}
+}
+
+func TestParseMalformedPDF(t *testing.T) {
+	// 目录中没有 /Pages，pdfcpu 读取页数时会解引用空指针
+	data := "%PDF-1.0\n1 0 obj<</Type/Catalog 0\nendobj"
+	path := filepath.Join(t.TempDir(), "malformed.pdf")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 文本层解析失败后降级到简单提取，只要求返回结果而不是 panic
+	text, err := parser.NewUnifiedResumeParser().Parse(path)
+	if err == nil && strings.TrimSpace(text) == "" {
+		t.Error("expected an error or fallback text for a malformed pdf")
+	}
+}
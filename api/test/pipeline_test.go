@@ -0,0 +1,63 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"hr-api/service/application_service"
+)
+
+func TestPipelineStages(t *testing.T) {
+	if _, err := application_service.NewPipeline(nil); err != nil {
+		t.Fatalf("default pipeline: %v", err)
+	}
+
+	valid := []string{"screening", "interview_1", "interview_2", "interview_3", "offer", "hired", "rejected"}
+	if _, err := application_service.NewPipeline(valid); err != nil {
+		t.Errorf("expected %v to be valid: %v", valid, err)
+	}
+
+	invalid := [][]string{
+		{"phone_screen", "screening", "offer", "hired", "rejected"},
+		{"screening", "interview_2", "offer", "hired", "rejected"},
+		{"screening", "offer", "hired"},
+		{"screening", "offer", "offer", "hired", "rejected"},
+		{"screening", "offer", "hired", "rejected", "onboarding"},
+	}
+	for _, stages := range invalid {
+		if _, err := application_service.NewPipeline(stages); err == nil {
+			t.Errorf("expected %v to be rejected", stages)
+		}
+	}
+}
+
+func TestPipelineTransitions(t *testing.T) {
+	p, _ := application_service.ParsePipeline("")
+
+	allowed := [][2]string{
+		{"screening", "phone_screen"},
+		{"screening", "interview_1"},
+		{"interview_2", "interview_1"},
+		{"interview_1", "rejected"},
+		{"offer", "hired"},
+	}
+	for _, move := range allowed {
+		if err := p.CanMove(move[0], move[1]); err != nil {
+			t.Errorf("expected %s -> %s to be allowed: %v", move[0], move[1], err)
+		}
+	}
+
+	denied := [][2]string{
+		{"screening", "screening"},
+		{"screening", "hired"},
+		{"interview_2", "screening"},
+		{"hired", "rejected"},
+		{"rejected", "screening"},
+		{"screening", "interview_9"},
+	}
+	for _, move := range denied {
+		if err := p.CanMove(move[0], move[1]); !errors.Is(err, application_service.ErrInvalidMove) {
+			t.Errorf("expected %s -> %s to be denied, got %v", move[0], move[1], err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hr-api/pkg/client"
+	"hr-api/pkg/prompt"
+	"hr-api/pkg/setting"
+)
+
+func TestPromptTemplateLoadAndRender(t *testing.T) {
+	setting.AppSetting.RuntimeRootPath = t.TempDir()
+	setting.LLMSetting.PromptDir = "prompts"
+	defer func() { setting.AppSetting.RuntimeRootPath = "" }()
+
+	builtin, err := prompt.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if builtin.Version != prompt.BuiltinVersion {
+		t.Errorf("expected builtin version, got %s", builtin.Version)
+	}
+
+	if err := os.MkdirAll(prompt.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{{define "system"}}sales{{end}}{{define "user"}}{{.Job.Title}}: {{.Resume}}{{end}}`
+	if err := os.WriteFile(filepath.Join(prompt.Dir(), "sales.tmpl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sales, err := prompt.Load("sales")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sales.Version, "sales@") {
+		t.Errorf("unexpected version: %s", sales.Version)
+	}
+
+	rendered, err := sales.Render(client.JobInfo{Title: "销售经理"}, "十年销售经验")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered.System != "sales" || rendered.User != "销售经理: 十年销售经验" {
+		t.Errorf("unexpected prompt: %+v", rendered)
+	}
+
+	if _, err := prompt.Load("../sales"); err == nil {
+		t.Error("expected invalid template name error")
+	}
+
+	templates, err := prompt.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 {
+		t.Errorf("expected builtin and sales templates, got %d", len(templates))
+	}
+}
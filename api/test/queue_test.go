@@ -0,0 +1,78 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hr-api/pkg/bus"
+	"hr-api/pkg/setting"
+	"hr-api/service/queue_service"
+)
+
+func TestWorkerRecoversPanic(t *testing.T) {
+	setting.QueueSetting.Driver = bus.DriverMemory
+	setting.QueueSetting.MaxDeliveryCount = 5
+
+	calls := 0
+	queue_service.Register("test_panic", func(ctx context.Context, payload []byte) error {
+		calls++
+		var s []byte
+		_ = s[len(payload)]
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := queue_service.Publish(ctx, "test_panic", "boom"); err != nil {
+		t.Fatal(err)
+	}
+	if err := queue_service.Run(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected worker to run until the deadline, got %v", err)
+	}
+
+	// panic 按 ErrPoison 处理，不重试直接转入死信队列
+	if calls != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls)
+	}
+	q, _ := queue_service.GetQueue()
+	if dead := q.(*bus.MemoryQueue).DeadLetters(); len(dead) != 1 {
+		t.Errorf("expected 1 dead-lettered message, got %d", len(dead))
+	}
+}
+
+func TestWorkerFinalDelivery(t *testing.T) {
+	setting.QueueSetting.Driver = bus.DriverMemory
+	setting.QueueSetting.MaxDeliveryCount = 3
+	setting.QueueSetting.RetryDelay = 10 * time.Millisecond
+
+	var finals, redeliver []bool
+	queue_service.Register("test_final", func(ctx context.Context, payload []byte) error {
+		err := errors.New("model unavailable")
+		finals = append(finals, queue_service.FinalDelivery(ctx))
+		redeliver = append(redeliver, queue_service.WillRedeliver(ctx, err))
+		return err
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if err := queue_service.Publish(ctx, "test_final", nil); err != nil {
+		t.Fatal(err)
+	}
+	queue_service.Run(ctx)
+
+	// 只有最后一次投递可以退回规则提取
+	if len(finals) != 3 || finals[0] || finals[1] || !finals[2] {
+		t.Errorf("unexpected final deliveries: %v", finals)
+	}
+	if len(redeliver) != 3 || !redeliver[0] || !redeliver[1] || redeliver[2] {
+		t.Errorf("unexpected redeliveries: %v", redeliver)
+	}
+	if queue_service.FinalDelivery(context.Background()) {
+		t.Error("calls outside the worker must not be final")
+	}
+	if queue_service.WillRedeliver(context.Background(), errors.New("failed")) {
+		t.Error("calls outside the worker are never redelivered")
+	}
+}
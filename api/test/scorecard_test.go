@@ -0,0 +1,61 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"hr-api/models"
+	"hr-api/service/scorecard_service"
+)
+
+func TestScorecardValidate(t *testing.T) {
+	criteria := scorecard_service.ParseCriteria("")
+	ratings := []scorecard_service.Rating{
+		{Criterion: "technical_skill", Score: 4},
+		{Criterion: "communication", Score: 3},
+		{Criterion: "culture_fit", Score: 5, Comment: "认同团队文化"},
+	}
+
+	valid := scorecard_service.Scorecard{Ratings: ratings, Recommendation: models.RecommendHire}
+	if err := valid.Validate(criteria); err != nil {
+		t.Fatalf("expected valid scorecard: %v", err)
+	}
+
+	invalid := []scorecard_service.Scorecard{
+		{Ratings: ratings, Recommendation: "maybe"},
+		{Ratings: ratings[:2], Recommendation: models.RecommendHire},
+		{Ratings: append(ratings[:2:2], scorecard_service.Rating{Criterion: "culture_fit", Score: 6}), Recommendation: models.RecommendNoHire},
+		{Ratings: append(ratings[:3:3], scorecard_service.Rating{Criterion: "leadership", Score: 3}), Recommendation: models.RecommendHire},
+	}
+	for i, scorecard := range invalid {
+		if err := scorecard.Validate(criteria); !errors.Is(err, scorecard_service.ErrInvalidScorecard) {
+			t.Errorf("case %d: expected invalid scorecard, got %v", i, err)
+		}
+	}
+
+	if _, err := scorecard_service.NormalizeCriteria([]string{"技术能力", "技术能力"}); err == nil {
+		t.Error("expected duplicate criteria to be rejected")
+	}
+}
+
+func TestSummarizeScorecards(t *testing.T) {
+	scorecards := []*models.Scorecard{
+		{Ratings: []byte(`[{"criterion":"技术能力","score":4},{"criterion":"沟通能力","score":3}]`), Recommendation: models.RecommendHire},
+		{Ratings: []byte(`[{"criterion":"技术能力","score":5},{"criterion":"沟通能力","score":2},{"criterion":"已删除","score":1}]`), Recommendation: models.RecommendNoHire},
+		{Ratings: []byte(`[{"criterion":"技术能力","score":2}]`), Recommendation: models.RecommendHire},
+	}
+
+	summary := scorecard_service.Summarize([]string{"技术能力", "沟通能力"}, scorecards)
+	if summary.Scorecards != 3 || summary.Hire != 2 || summary.NoHire != 1 {
+		t.Errorf("unexpected counts: %+v", summary)
+	}
+	if c := summary.Criteria[0]; c.Count != 3 || c.Average != 3.67 {
+		t.Errorf("unexpected technical summary: %+v", c)
+	}
+	if c := summary.Criteria[1]; c.Count != 2 || c.Average != 2.5 {
+		t.Errorf("unexpected communication summary: %+v", c)
+	}
+	if summary.Average != 3.2 {
+		t.Errorf("unexpected overall average %.2f", summary.Average)
+	}
+}
@@ -0,0 +1,194 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"hr-api/pkg/setting"
+	"hr-api/pkg/webhook"
+)
+
+func newStageEvent() *webhook.Event {
+	e := webhook.NewEvent(webhook.EventStageChanged, 7, "张三 在「后端工程师」的阶段变更为 offer")
+	e.Add("job_name", "职位", "后端工程师").
+		Add("to_stage", "新阶段", "offer").
+		Add("application_id", "", 42)
+	return e
+}
+
+func TestWebhookPayload(t *testing.T) {
+	e := newStageEvent()
+
+	body, err := webhook.Payload(webhook.KindGeneric, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(body, &generic); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := generic["data"].(map[string]interface{})
+	if generic["event"] != webhook.EventStageChanged || generic["job_id"] != float64(7) || data["application_id"] != float64(42) {
+		t.Errorf("unexpected generic payload: %s", body)
+	}
+
+	body, err = webhook.Payload(webhook.KindTeams, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var teams struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string         `json:"type"`
+					Text  string         `json:"text"`
+					Facts []webhook.Fact `json:"facts"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(body, &teams); err != nil {
+		t.Fatal(err)
+	}
+	if teams.Type != "message" || len(teams.Attachments) != 1 {
+		t.Fatalf("unexpected teams payload: %s", body)
+	}
+	card := teams.Attachments[0]
+	if card.ContentType != "application/vnd.microsoft.card.adaptive" || card.Content.Type != "AdaptiveCard" {
+		t.Errorf("unexpected card: %s", body)
+	}
+	if card.Content.Body[0].Text != e.Title {
+		t.Errorf("unexpected title: %s", card.Content.Body[0].Text)
+	}
+	// 没有 label 的字段不出现在卡片中
+	facts := card.Content.Body[1].Facts
+	if len(facts) != 2 || facts[0] != (webhook.Fact{Title: "职位", Value: "后端工程师"}) {
+		t.Errorf("unexpected facts: %+v", facts)
+	}
+
+	if _, err := webhook.Payload("slack", e); err == nil {
+		t.Error("expected unsupported kind error")
+	}
+}
+
+func TestWebhookSendSigned(t *testing.T) {
+	const secret = "0123456789abcdef"
+	status := http.StatusOK
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if !webhook.Verify(secret, timestamp, body, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+	allowLocalWebhooks(t)
+
+	body, _ := webhook.Payload(webhook.KindGeneric, newStageEvent())
+	req := &webhook.Request{
+		Kind:       webhook.KindGeneric,
+		Url:        server.URL,
+		Secret:     secret,
+		Event:      webhook.EventStageChanged,
+		DeliveryId: "abc-1",
+		Body:       body,
+	}
+	resp, err := webhook.Send(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Success() || resp.Body != "ok" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if received.Get(webhook.HeaderEvent) != webhook.EventStageChanged || received.Get(webhook.HeaderDelivery) != "abc-1" {
+		t.Errorf("unexpected headers: %v", received)
+	}
+
+	req.Secret = "another-secret-value"
+	resp, err = webhook.Send(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Success() || resp.StatusCode != http.StatusUnauthorized || resp.Retryable() {
+		t.Errorf("a bad signature must fail without retry: %+v", resp)
+	}
+
+	req.Secret = secret
+	status = http.StatusServiceUnavailable
+	resp, _ = webhook.Send(context.Background(), req)
+	if resp.Success() || !resp.Retryable() {
+		t.Errorf("5xx must be retried: %+v", resp)
+	}
+
+	// Teams 请求不带签名头
+	req.Kind = webhook.KindTeams
+	status = http.StatusOK
+	webhook.Send(context.Background(), req)
+	if received.Get(webhook.HeaderSignature) != "" {
+		t.Error("teams requests must not be signed")
+	}
+
+	server.Close()
+	resp, err = webhook.Send(context.Background(), req)
+	if err == nil || !resp.Retryable() {
+		t.Errorf("network errors must be retried: %v", err)
+	}
+}
+
+// allowLocalWebhooks 允许投递到测试服务器所在的本机地址
+func allowLocalWebhooks(t *testing.T) {
+	allowed := setting.WebhookSetting.AllowedHosts
+	setting.WebhookSetting.AllowedHosts = []string{"127.0.0.1"}
+	t.Cleanup(func() { setting.WebhookSetting.AllowedHosts = allowed })
+}
+
+func TestWebhookForbiddenAddress(t *testing.T) {
+	for _, rawUrl := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+	} {
+		if err := webhook.CheckUrl(context.Background(), rawUrl); !errors.Is(err, webhook.ErrForbiddenAddress) {
+			t.Errorf("expected %s to be forbidden, got %v", rawUrl, err)
+		}
+	}
+	if err := webhook.CheckUrl(context.Background(), "https://203.0.113.10/hook"); err != nil {
+		t.Errorf("expected public address to be allowed: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := &webhook.Request{Kind: webhook.KindTeams, Url: server.URL, Body: []byte("{}")}
+	if _, err := webhook.Send(context.Background(), req); !errors.Is(err, webhook.ErrForbiddenAddress) {
+		t.Errorf("expected delivery to a loopback address to be refused, got %v", err)
+	}
+
+	allowLocalWebhooks(t)
+	if err := webhook.CheckUrl(context.Background(), server.URL); err != nil {
+		t.Errorf("expected allowed host to pass: %v", err)
+	}
+	if resp, err := webhook.Send(context.Background(), req); err != nil || !resp.Success() {
+		t.Errorf("expected delivery to an allowed host: %+v, %v", resp, err)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"hr-api/pkg/setting"
+	"hr-api/service/batch_service"
+	"hr-api/service/notify_service"
+	"hr-api/service/offer_service"
+	"hr-api/service/queue_service"
+	"hr-api/service/webhook_service"
+)
+
+// registerTasks 注册异步任务的处理函数
+func registerTasks() {
+	// 批量分析为每份简历投递一个分析任务，由 batch_service 在分析完成后统计批次进度
+	queue_service.Register(queue_service.TaskAnalyzeResume, batch_service.HandleAnalyzeTask)
+	queue_service.Register(queue_service.TaskExpireOffer, offer_service.HandleExpireTask)
+	queue_service.Register(queue_service.TaskSendNotification, notify_service.HandleSendTask)
+	queue_service.Register(queue_service.TaskDeliverWebhook, webhook_service.HandleDeliverTask)
+}
+
+// runWorker 以 worker 模式运行（hr-api worker），消费任务队列。
+// 每个 worker 进程依次处理消息，同时执行的任务数等于 worker 进程数，批量分析的并发也由此限制
+func runWorker() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("[info] start worker consuming queue %s (%s)", setting.QueueSetting.Name, setting.QueueSetting.Driver)
+
+	err := queue_service.Run(ctx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("worker stopped: %v", err)
+	}
+}